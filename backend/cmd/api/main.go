@@ -8,13 +8,17 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
 	logLevel "gorm.io/gorm/logger"
 
 	"mindsteps/config"
 	"mindsteps/database"
 	"mindsteps/internal/auth"
+	"mindsteps/internal/errorlog"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/oidc"
+	"mindsteps/internal/password"
 	"mindsteps/internal/router"
+	"mindsteps/internal/scheduler"
 	"mindsteps/pkg/cloudflare"
 	cache "mindsteps/pkg/redis"
 )
@@ -37,8 +41,13 @@ func main() {
 		println(err.Error())
 	}
 
+	mailer.MustLoad()
+	oidc.MustLoad()
+	password.MustLoad()
+
 	app := fiber.New(fiber.Config{
-		BodyLimit: 100 * 1024 * 1024, // 100 MB
+		BodyLimit:    100 * 1024 * 1024, // 100 MB
+		ErrorHandler: errorlog.ErrorHandler,
 	})
 
 	app.Use(cors.New(cors.Config{
@@ -65,11 +74,21 @@ func main() {
 		},
 	))
 
-	app.Use(recover.New())
+	// panic болон 5xx алдааг error_logs-д бичнэ (recover.New()-ийг орлоно)
+	app.Use(errorlog.Middleware)
 
-	auth.MustInitGjwt()
+	auth.MustInitGjwt(database.DB)
+	auth.InitSessions(database.DB)
+	auth.InitAudit(database.DB)
+	errorlog.Init(database.DB)
+	auth.InitUnverifiedAccess()
+	auth.InitMFAKey()
 	router.RegisterRoutes(app)
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	scheduler.Start(jobCtx)
+
 	err = app.Listen(`:8080`)
 	if err != nil {
 		log.Errorf("server stopped: %v", err)
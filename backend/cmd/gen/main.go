@@ -241,6 +241,66 @@ func main() {
 		gen.FieldIgnore("encrypted_key"),
 	)
 
+	// Rate limit counters (Redis-ийн fallback)
+	rateLimitCounters := g.GenerateModelAs(
+		model("rate_limit_counters"),
+		"RateLimitCounters",
+		gen.FieldType("count", "int"),
+	)
+
+	// OIDC (Google, Apple) бүртгэлүүд
+	userIdentities := g.GenerateModelAs(
+		model("user_identities"),
+		"UserIdentities",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("user_id", "uint"),
+	)
+
+	// TOTP хоёр шатат баталгаажуулалт
+	userMfa := g.GenerateModelAs(
+		model("user_mfa"),
+		"UserMfa",
+		gen.FieldType("user_id", "uint"),
+		gen.FieldJSONTag("secret_encrypted", "-"),
+	)
+
+	userRecoveryCodes := g.GenerateModelAs(
+		model("user_recovery_codes"),
+		"UserRecoveryCodes",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("user_id", "uint"),
+		gen.FieldJSONTag("code_hash", "-"),
+	)
+
+	// Email солих хүсэлтүүд
+	userEmailChanges := g.GenerateModelAs(
+		model("user_email_changes"),
+		"UserEmailChanges",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("user_id", "uint"),
+	)
+
+	// Нууц үг дахин ашиглахаас сэргийлэх түүх
+	passwordHistory := g.GenerateModelAs(
+		model("password_history"),
+		"PasswordHistory",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("user_id", "uint"),
+		gen.FieldJSONTag("password_hash", "-"),
+	)
+
+	// Системүүдийн API key
+	apiKeys := g.GenerateModelAs(
+		model("api_keys"),
+		"APIKeys",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("rate_limit_per_minute", "int"),
+		gen.FieldType("rotated_from_id", "uint"),
+		gen.FieldType("created_by_id", "uint"),
+		gen.FieldType("scopes", "datatypes.JSON"),
+		gen.FieldJSONTag("key_hash", "-"),
+	)
+
 	// ============================================================================
 	// MASLOW'S HIERARCHY & CORE VALUES
 	// ============================================================================
@@ -588,6 +648,22 @@ func main() {
 		}),
 	)
 
+	// Сэтгэл санааны trigger, coping шошго
+	moodTags := g.GenerateModelAs(
+		model("mood_tags"),
+		"MoodTags",
+		gen.FieldType("id", "uint"),
+		gen.FieldType("user_id", "uint"),
+		gen.FieldType("usage_count", "int"),
+	)
+
+	moodEntryTags := g.GenerateModelAs(
+		model("mood_entry_tags"),
+		"MoodEntryTags",
+		gen.FieldType("mood_entry_id", "uint"),
+		gen.FieldType("tag_id", "uint"),
+	)
+
 	// ============================================================================
 	// GOALS & MILESTONES
 	// ============================================================================
@@ -985,6 +1061,7 @@ func main() {
 		gen.FieldType("user_id", "uint"),
 		gen.FieldType("resolved_by_id", "uint"),
 		gen.FieldType("is_resolved", "bool"),
+		gen.FieldType("occurrences", "int"),
 		gen.FieldRelate(field.BelongsTo, "User", users, &field.RelateConfig{
 			RelatePointer: true,
 			GORMTag: field.GormTag{
@@ -1012,7 +1089,8 @@ func main() {
 		users, roles, roleOwners,
 
 		// Authentication & Security
-		authOTP, userSessions, revokedTokens, encryptionKeys,
+		authOTP, userSessions, revokedTokens, encryptionKeys, rateLimitCounters, userIdentities,
+		userMfa, userRecoveryCodes, userEmailChanges, passwordHistory, apiKeys,
 
 		// Gamification System
 		userLevels, scoringHistory, userGamification,
@@ -1027,7 +1105,7 @@ func main() {
 		journals,
 
 		// Mood Tracking
-		moodCategories, MoodUnit, moodEntries,
+		moodCategories, MoodUnit, moodEntries, moodTags, moodEntryTags,
 
 		// Goals & Milestones
 		goals, goalMilestones,
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"mindsteps/config"
+	"mindsteps/database"
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+
+	"gorm.io/gorm/logger"
+)
+
+// JWT гарын үсгийн түлхүүр удирдах CLI. Түлхүүр бүр encryption_keys (key_name=jwt)-д бүртгэгдэж,
+// хувийн түлхүүр нь AUTH_JWT_KEY_DIR/<kid>.pem файлд хадгалагдана.
+//
+//	go run ./cmd/jwtkeys list
+//	go run ./cmd/jwtkeys generate               # дараагийн түлхүүрийг бэлтгэж JWKS-д нийтэлнэ
+//	go run ./cmd/jwtkeys rotate -grace 24h      # бэлтгэсэн (эсвэл шинэ) түлхүүрийг идэвхжүүлнэ
+//	go run ./cmd/jwtkeys retire -version 1      # хуучин түлхүүрийг шалгалтаас хасна
+//
+// Ажиллаж буй серверүүд өөрчлөлтийг 5 минутын дотор автоматаар ачаална.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	grace := cmd.Duration("grace", 24*time.Hour, "rotate: хуучин түлхүүрээр token шалгасаар байх хугацаа")
+	version := cmd.Int("version", 0, "retire: түлхүүрийн хувилбар")
+	cmd.Parse(os.Args[2:])
+
+	config.MustLoad()
+	database.MustConnect(logger.Warn)
+
+	repo := authRepo.NewKeyRepository(database.DB)
+	keyDir := config.Get().Auth.JwtKeyDir
+
+	switch os.Args[1] {
+	case "list":
+		list(repo)
+	case "generate":
+		generate(repo, keyDir)
+	case "rotate":
+		rotate(repo, keyDir, *grace)
+	case "retire":
+		if *version <= 0 {
+			exit("-version заавал шаардлагатай")
+		}
+		if err := repo.Retire(authRepo.JWTKeyName, *version, time.Now()); err != nil {
+			exit("%s түлхүүрийг хасахад алдаа (идэвхтэй түлхүүрийг хасах боломжгүй): %v", auth.JWTKeyID(*version), err)
+		}
+		fmt.Printf("%s шалгалтаас хасагдлаа\n", auth.JWTKeyID(*version))
+	default:
+		usage()
+	}
+}
+
+func list(repo authRepo.KeyRepository) {
+	keys, err := repo.ListKeys(authRepo.JWTKeyName)
+	if err != nil {
+		exit("түлхүүр уншихад алдаа: %v", err)
+	}
+	if len(keys) == 0 {
+		fmt.Println("Бүртгэлтэй түлхүүр алга. Env (AUTH_JWT_PRIVATE_KEY) түлхүүрээр ажиллаж байна.")
+		return
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		status := "staged"
+		switch {
+		case key.IsActive:
+			status = "active"
+		case !key.ExpiresAt.IsZero() && !key.ExpiresAt.After(now):
+			status = "retired"
+		case !key.ExpiresAt.IsZero():
+			status = "verify-only until " + key.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%-10s %-8s created %s  %s\n", auth.JWTKeyID(key.KeyVersion), key.Algorithm, key.CreatedAt.Format(time.RFC3339), status)
+	}
+}
+
+func generate(repo authRepo.KeyRepository, keyDir string) int {
+	latest, err := repo.LatestVersion(authRepo.JWTKeyName)
+	if err != nil {
+		exit("хувилбар уншихад алдаа: %v", err)
+	}
+	version := latest + 1
+	kid := auth.JWTKeyID(version)
+
+	key, err := auth.GenerateKey()
+	if err != nil {
+		exit("түлхүүр үүсгэхэд алдаа: %v", err)
+	}
+	if err := auth.WriteKeyFile(keyDir, kid, key); err != nil {
+		exit("түлхүүр бичихэд алдаа: %v", err)
+	}
+
+	if err := repo.Create(&model.EncryptionKeys{
+		KeyName:    authRepo.JWTKeyName,
+		KeyVersion: version,
+		Algorithm:  "RS256",
+		IsActive:   false,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		exit("encryption_keys-д бүртгэхэд алдаа: %v", err)
+	}
+
+	fmt.Printf("%s үүслээ (%s). JWKS-д нийтлэгдсэн, rotate хийхэд идэвхжинэ\n", kid, keyDir)
+	return version
+}
+
+// rotate нь хамгийн сүүлд бэлтгэсэн түлхүүрийг идэвхжүүлнэ. Бэлтгэсэн түлхүүр байхгүй бол шинээр үүсгэнэ.
+func rotate(repo authRepo.KeyRepository, keyDir string, grace time.Duration) {
+	keys, err := repo.ListKeys(authRepo.JWTKeyName)
+	if err != nil {
+		exit("түлхүүр уншихад алдаа: %v", err)
+	}
+
+	version := 0
+	for _, key := range keys {
+		if !key.IsActive && key.ExpiresAt.IsZero() && key.KeyVersion > version {
+			version = key.KeyVersion
+		}
+	}
+	if version == 0 {
+		version = generate(repo, keyDir)
+	}
+
+	if _, err := auth.ReadKeyFile(keyDir, auth.JWTKeyID(version)); err != nil {
+		exit("%v", err)
+	}
+
+	if err := repo.Activate(authRepo.JWTKeyName, version, time.Now().Add(grace)); err != nil {
+		exit("түлхүүр идэвхжүүлэхэд алдаа: %v", err)
+	}
+	fmt.Printf("%s идэвхжлээ. Өмнөх түлхүүр %v хугацаанд шалгалтад ашиглагдана\n", auth.JWTKeyID(version), grace)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jwtkeys <list|generate|rotate|retire> [-grace 24h] [-version N]")
+	os.Exit(2)
+}
+
+func exit(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"mindsteps/config"
+	"mindsteps/database"
+	"mindsteps/database/model"
+	rbacRepo "mindsteps/internal/rbac/repository"
+	userRepo "mindsteps/internal/user/repository"
+
+	"gorm.io/gorm/logger"
+)
+
+// Хэрэглэгчид role оноох/хасах CLI. Анхны super_admin-ийг API-гүйгээр оноохад ашиглана.
+//
+//	go run ./cmd/rbac -email admin@example.com -role super_admin
+//	go run ./cmd/rbac -email admin@example.com -role super_admin -revoke
+func main() {
+	email := flag.String("email", "", "хэрэглэгчийн email")
+	roleCode := flag.String("role", "", "role code (жишээ нь super_admin)")
+	revoke := flag.Bool("revoke", false, "role-ийг хасах")
+	flag.Parse()
+
+	if *email == "" || *roleCode == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.MustLoad()
+	database.MustConnect(logger.Warn)
+
+	users := userRepo.NewUserRepository(database.DB)
+	roles := rbacRepo.NewRoleRepository(database.DB)
+
+	user, err := users.FindByEmail(*email)
+	if err != nil {
+		exit("хэрэглэгч олдсонгүй: %v", err)
+	}
+	role, err := roles.FindByCode(*roleCode)
+	if err != nil {
+		exit("role олдсонгүй: %v", err)
+	}
+
+	if *revoke {
+		revoked, err := roles.Revoke(user.ID, role.ID)
+		if err != nil {
+			exit("role хасахад алдаа: %v", err)
+		}
+		if !revoked {
+			exit("%s хэрэглэгчид %s role оноогдоогүй байна", user.Email, role.Code)
+		}
+		fmt.Printf("%s хэрэглэгчээс %s role хасагдлаа\n", user.Email, role.Code)
+		return
+	}
+
+	assigned, err := roles.IsAssigned(user.ID, role.ID)
+	if err != nil {
+		exit("шалгахад алдаа: %v", err)
+	}
+	if assigned {
+		fmt.Printf("%s хэрэглэгчид %s role аль хэдийн оноогдсон байна\n", user.Email, role.Code)
+		return
+	}
+
+	if err := roles.Assign(&model.RoleOwners{
+		RoleID:    role.ID,
+		OwnerID:   user.ID,
+		Status:    rbacRepo.OwnerStatusActive,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		exit("role оноохад алдаа: %v", err)
+	}
+	fmt.Printf("%s хэрэглэгчид %s role оноогдлоо. Дахин нэвтэрсний дараа хэрэгжинэ\n", user.Email, role.Code)
+}
+
+func exit(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/joho/godotenv"
@@ -18,8 +19,19 @@ type database struct {
 }
 
 type auth struct {
-	JwtPrivateKey string
-	JwtPublicKey  string
+	JwtPrivateKey    string // env дэх хуучин түлхүүр (заавал биш)
+	JwtPublicKey     string
+	JwtKeyDir        string // cmd/jwtkeys-ийн үүсгэсэн <kid>.pem файлууд
+	UnverifiedAccess string // имэйлээ баталгаажуулаагүй хэрэглэгчийн эрх: read_only эсвэл full
+	MfaKey           string // TOTP нууцыг шифрлэх 32 байт түлхүүр (base64)
+}
+
+// password нь нууц үгийн бодлого. Register, ResetPassword, ChangePassword-д хэрэгжинэ.
+type password struct {
+	MinLength   int
+	MinClasses  int    // жижиг, том үсэг, тоо, тусгай тэмдэгтээс хамгийн багадаа хэдийг агуулах
+	HistorySize int    // сүүлийн хэдэн нууц үгийг дахин ашиглахыг хориглох
+	CommonList  string // түгээмэл нууц үгийн нэмэлт жагсаалт (мөр бүрт нэг), заавал биш
 }
 type api struct {
 	Port       int
@@ -44,11 +56,31 @@ type smtp struct {
 	SMTPPort     int
 	SMTPUser     string
 	SMTPPassword string
+	From         string
+	AuthMethod   string // plain эсвэл xoauth2
 	ClientID     string
 	TenantID     string
 	ClientSecret string
 }
 
+type mail struct {
+	Driver    string // smtp эсвэл file (production-д анхдагч нь smtp)
+	FileDir   string // file driver-ийн maildir хавтас
+	PublicURL string // имэйл доторх холбоосын үндсэн хаяг (frontend)
+}
+
+// oidc нь Google/Apple-аар нэвтрэх тохиргоо. Client ID өгөгдөөгүй provider идэвхгүй.
+type oidc struct {
+	RedirectBaseURL    string // backend-ийн гаднаас хандах хаяг (callback: <base>/api/v1/auth/oidc/<provider>/callback)
+	GoogleClientID     string
+	GoogleClientSecret string
+	AppleClientID      string // Services ID
+	AppleTeamID        string
+	AppleKeyID         string
+	ApplePrivateKey    string // .p8 түлхүүрийн PEM агуулга
+	LocalStub          bool   // хөгжүүлэлтэд зориулсан хуурамч issuer
+}
+
 type cloudApi struct { //cloudflare
 	Endpoint   string
 	AccessKey  string
@@ -61,9 +93,12 @@ type config struct {
 	IsProduction bool
 	DB           *database
 	Auth         *auth
+	Password     *password
 	//Firebase     *firebase
 	Api      *api
 	Smtp     *smtp
+	Mail     *mail
+	OIDC     *oidc
 	CloudApi *cloudApi
 }
 
@@ -78,8 +113,16 @@ func MustLoad() {
 	// 	godotenv.Load()
 	// }
 	godotenv.Load()
+
+	// APP_ENV=production үед имэйлийг файлд бичихгүй, анхдагчаар SMTP-ээр илгээнэ
+	isProduction := strings.EqualFold(os.Getenv("APP_ENV"), "production")
+	mailDriver := "file"
+	if isProduction {
+		mailDriver = "smtp"
+	}
+
 	cfg = &config{
-		//IsProduction: isProduction,
+		IsProduction: isProduction,
 		DB: &database{
 			Host:     loadString("DB_HOST"),
 			Port:     loadInt("DB_PORT"),
@@ -90,8 +133,18 @@ func MustLoad() {
 		},
 
 		Auth: &auth{
-			JwtPublicKey:  loadString("AUTH_JWT_PUBLIC_KEY"),
-			JwtPrivateKey: loadString("AUTH_JWT_PRIVATE_KEY"),
+			JwtPublicKey:     os.Getenv("AUTH_JWT_PUBLIC_KEY"),
+			JwtPrivateKey:    os.Getenv("AUTH_JWT_PRIVATE_KEY"),
+			JwtKeyDir:        loadStringDefault("AUTH_JWT_KEY_DIR", "keys/jwt"),
+			UnverifiedAccess: loadStringDefault("AUTH_UNVERIFIED_ACCESS", "read_only"),
+			MfaKey:           os.Getenv("AUTH_MFA_KEY"),
+		},
+
+		Password: &password{
+			MinLength:   loadIntDefault("PASSWORD_MIN_LENGTH", 10),
+			MinClasses:  loadIntDefault("PASSWORD_MIN_CLASSES", 3),
+			HistorySize: loadIntDefault("PASSWORD_HISTORY_SIZE", 5),
+			CommonList:  os.Getenv("PASSWORD_COMMON_LIST"),
 		},
 
 		CloudApi: &cloudApi{
@@ -102,15 +155,22 @@ func MustLoad() {
 			CdnURL:     loadString("CDN_URL"),
 		},
 
-		// Smtp: &smtp{
-		// 	SMTPServer:   loadString("SMTP_SERVER"),
-		// 	SMTPPort:     loadInt("SMTP_PORT"),
-		// 	SMTPUser:     loadString("SMTP_USER_NAME"),
-		// 	SMTPPassword: loadString("SMTP_PASSWORD"),
-		// 	ClientID:     loadString("SMTP_CLIENT_ID"),
-		// 	TenantID:     loadString("SMTP_TENANT_ID"),
-		// 	ClientSecret: loadString("SMTP_CLIENT_SECRET"),
-		// },
+		Mail: &mail{
+			Driver:    loadStringDefault("MAIL_DRIVER", mailDriver),
+			FileDir:   loadStringDefault("MAIL_FILE_DIR", "tmp/maildir"),
+			PublicURL: loadStringDefault("APP_PUBLIC_URL", "http://localhost:3000"),
+		},
+
+		OIDC: &oidc{
+			RedirectBaseURL:    loadStringDefault("OIDC_REDIRECT_BASE_URL", "http://localhost:8080"),
+			GoogleClientID:     os.Getenv("OIDC_GOOGLE_CLIENT_ID"),
+			GoogleClientSecret: os.Getenv("OIDC_GOOGLE_CLIENT_SECRET"),
+			AppleClientID:      os.Getenv("OIDC_APPLE_CLIENT_ID"),
+			AppleTeamID:        os.Getenv("OIDC_APPLE_TEAM_ID"),
+			AppleKeyID:         os.Getenv("OIDC_APPLE_KEY_ID"),
+			ApplePrivateKey:    os.Getenv("OIDC_APPLE_PRIVATE_KEY"),
+			LocalStub:          os.Getenv("OIDC_LOCAL_STUB") == "true",
+		},
 
 		// Firebase: &firebase{
 		// 	Type:                    loadString("FIREBASE_TYPE"),
@@ -126,6 +186,20 @@ func MustLoad() {
 		// 	UniverseDomain:          loadString("FIREBASE_UNIVERSE_DOMAIN"),
 		// },
 	}
+
+	if cfg.Mail.Driver == "smtp" {
+		cfg.Smtp = &smtp{
+			SMTPServer:   loadString("SMTP_SERVER"),
+			SMTPPort:     loadInt("SMTP_PORT"),
+			SMTPUser:     loadString("SMTP_USER_NAME"),
+			SMTPPassword: loadString("SMTP_PASSWORD"),
+			From:         loadStringDefault("SMTP_FROM", os.Getenv("SMTP_USER_NAME")),
+			AuthMethod:   loadStringDefault("SMTP_AUTH", "plain"),
+			ClientID:     os.Getenv("SMTP_CLIENT_ID"),
+			TenantID:     os.Getenv("SMTP_TENANT_ID"),
+			ClientSecret: os.Getenv("SMTP_CLIENT_SECRET"),
+		}
+	}
 }
 
 func Get() *config {
@@ -145,6 +219,13 @@ func loadString(key string) string {
 	return val
 }
 
+func loadStringDefault(key, defaultValue string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
 func loadInt(key string) int {
 	s := loadString(key)
 
@@ -155,3 +236,10 @@ func loadInt(key string) int {
 
 	return val
 }
+
+func loadIntDefault(key string, defaultValue int) int {
+	if os.Getenv(key) == "" {
+		return defaultValue
+	}
+	return loadInt(key)
+}
@@ -0,0 +1,9 @@
+-- Redis ажиллахгүй үед rate limit / оролдлогын тоолуурыг хадгалах хүснэгт
+CREATE TABLE IF NOT EXISTS mindstep.rate_limit_counters (
+    key        VARCHAR(255) PRIMARY KEY,
+    count      INTEGER      NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_expires_at
+    ON mindstep.rate_limit_counters (expires_at);
@@ -0,0 +1,12 @@
+-- RBAC-ийн анхны role-ууд. Permissions нь {"<resource>": ["<action>", ...]} хэлбэртэй.
+-- Анхны admin-ийг: go run ./cmd/rbac -email <email> -role super_admin
+INSERT INTO mindstep.roles (level, code, name, permissions)
+SELECT 100, 'super_admin', 'Супер админ', '{"*": ["*"]}'::jsonb
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.roles WHERE code = 'super_admin');
+
+INSERT INTO mindstep.roles (level, code, name, permissions)
+SELECT 50, 'content_admin', 'Контент админ', '{"lesson": ["write"], "plutchik": ["write"], "cache": ["manage"]}'::jsonb
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.roles WHERE code = 'content_admin');
+
+CREATE INDEX IF NOT EXISTS idx_role_owners_owner_status
+    ON mindstep.role_owners (owner_id, status);
@@ -0,0 +1,14 @@
+-- Google, Apple зэрэг OIDC provider-ийн бүртгэлийг хэрэглэгчтэй холбоно
+CREATE TABLE IF NOT EXISTS mindstep.user_identities (
+    id            BIGSERIAL    PRIMARY KEY,
+    user_id       BIGINT       NOT NULL REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    provider      VARCHAR(30)  NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255),
+    created_at    TIMESTAMP    NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMP,
+    CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id
+    ON mindstep.user_identities (user_id);
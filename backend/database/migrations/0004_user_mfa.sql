@@ -0,0 +1,21 @@
+-- TOTP хоёр шатат баталгаажуулалт. enabled_at хоосон бол тохируулж дуусаагүй байна.
+CREATE TABLE IF NOT EXISTS mindstep.user_mfa (
+    user_id          BIGINT       PRIMARY KEY REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    enabled_at       TIMESTAMP,
+    last_used_step   BIGINT       NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP    NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+-- Нэг удаагийн сэргээх кодууд. Зөвхөн SHA-256 hash хадгална.
+CREATE TABLE IF NOT EXISTS mindstep.user_recovery_codes (
+    id         BIGSERIAL   PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id
+    ON mindstep.user_recovery_codes (user_id);
@@ -0,0 +1,15 @@
+-- Email солих хүсэлтүүд. Шинэ хаягт илгээсэн OTP (auth_otp.otp_type = 'email_change')
+-- баталгаажсаны дараа л users.email солигдоно. Хуучин хаяг руу илгээсэн холбоосоор цуцалж болно.
+CREATE TABLE IF NOT EXISTS mindstep.user_email_changes (
+    id           BIGSERIAL    PRIMARY KEY,
+    user_id      BIGINT       NOT NULL REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    old_email    VARCHAR(255) NOT NULL,
+    new_email    VARCHAR(255) NOT NULL,
+    expired_at   TIMESTAMP    NOT NULL,
+    confirmed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at   TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_email_changes_user_id
+    ON mindstep.user_email_changes (user_id);
@@ -0,0 +1,11 @@
+-- Хэрэглэгчийн өмнөх нууц үгийн bcrypt hash-ууд. Нууц үг солих бүрт солигдож буй
+-- hash-ийг нэмж, хамгийн сүүлийн 24-өөс бусдыг устгана (PASSWORD_HISTORY_SIZE <= 24).
+CREATE TABLE IF NOT EXISTS mindstep.password_history (
+    id            BIGSERIAL    PRIMARY KEY,
+    user_id       BIGINT       NOT NULL REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id
+    ON mindstep.password_history (user_id, id DESC);
@@ -0,0 +1,17 @@
+-- Системүүдийн (ML service, тайлангийн job) хэрэглэгчгүйгээр backend руу хандах API key.
+-- Түлхүүрийг өөрийг нь биш зөвхөн SHA-256 hash-ийг хадгална. prefix нь хайлт болон жагсаалтад харагдана.
+CREATE TABLE IF NOT EXISTS mindstep.api_keys (
+    id                    BIGSERIAL    PRIMARY KEY,
+    name                  VARCHAR(50)  NOT NULL,
+    prefix                VARCHAR(12)  NOT NULL UNIQUE,
+    key_hash              VARCHAR(64)  NOT NULL,
+    scopes                JSONB        NOT NULL DEFAULT '{}',
+    rate_limit_per_minute INTEGER      NOT NULL DEFAULT 60,
+    expires_at            TIMESTAMP,
+    last_used_at          TIMESTAMP,
+    last_used_ip          VARCHAR(45),
+    revoked_at            TIMESTAMP,
+    rotated_from_id       BIGINT       REFERENCES mindstep.api_keys (id),
+    created_by_id         BIGINT       REFERENCES mindstep.users (id),
+    created_at            TIMESTAMP    NOT NULL DEFAULT now()
+);
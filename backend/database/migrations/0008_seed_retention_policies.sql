@@ -0,0 +1,18 @@
+-- Анхны retention policy-ууд. Хэрэглэгддэг хүснэгтүүд: internal/retention/repository Targets.
+-- retention_days: мөрийг устгах (эсвэл нууцлах) нас, archive_after_days: deleted_data_log руу архивлах нас.
+-- auth_otp-ийг otp-cleanup job цэвэрлэдэг тул энд оруулаагүй.
+INSERT INTO mindstep.data_retention_policies (table_name, retention_days, archive_after_days, auto_delete, deletion_method, is_active)
+SELECT 'revoked_tokens', 7, 0, true, 'delete', true
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.data_retention_policies WHERE table_name = 'revoked_tokens');
+
+INSERT INTO mindstep.data_retention_policies (table_name, retention_days, archive_after_days, auto_delete, deletion_method, is_active)
+SELECT 'user_data_access_log', 365, 0, true, 'delete', true
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.data_retention_policies WHERE table_name = 'user_data_access_log');
+
+INSERT INTO mindstep.data_retention_policies (table_name, retention_days, archive_after_days, auto_delete, deletion_method, is_active)
+SELECT 'user_sessions', 90, 0, true, 'delete', true
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.data_retention_policies WHERE table_name = 'user_sessions');
+
+INSERT INTO mindstep.data_retention_policies (table_name, retention_days, archive_after_days, auto_delete, deletion_method, is_active)
+SELECT 'error_logs', 90, 0, true, 'anonymize', true
+WHERE NOT EXISTS (SELECT 1 FROM mindstep.data_retention_policies WHERE table_name = 'error_logs');
@@ -0,0 +1,16 @@
+-- error_logs-ийн давхардлыг fingerprint-ээр нэгтгэнэ. Шийдэгдээгүй нэг fingerprint-д нэг л мөр
+-- байх бөгөөд дахин гарах бүрт occurrences нэмэгдэж last_seen_at шинэчлэгдэнэ.
+-- Шийдэгдсэний дараа дахин гарвал шинэ мөр үүснэ.
+ALTER TABLE mindstep.error_logs
+    ADD COLUMN IF NOT EXISTS fingerprint  VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS occurrences  INTEGER   NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+
+UPDATE mindstep.error_logs SET is_resolved = false WHERE is_resolved IS NULL;
+UPDATE mindstep.error_logs SET last_seen_at = created_at WHERE last_seen_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_error_logs_open_fingerprint
+    ON mindstep.error_logs (fingerprint) WHERE is_resolved = false;
+
+CREATE INDEX IF NOT EXISTS idx_error_logs_last_seen_at
+    ON mindstep.error_logs (last_seen_at DESC);
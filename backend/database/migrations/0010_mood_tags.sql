@@ -0,0 +1,63 @@
+-- Сэтгэл санааны бүртгэлийн trigger_event, coping_strategy-ийн чөлөөт текстээс үүссэн
+-- хэрэглэгчийн өөрийн шошгууд. normalized нь жижиг үсэгтэй, илүү зайгүй хэлбэр.
+CREATE TABLE IF NOT EXISTS mindstep.mood_tags (
+    id           BIGSERIAL    PRIMARY KEY,
+    user_id      BIGINT       NOT NULL REFERENCES mindstep.users (id) ON DELETE CASCADE,
+    kind         VARCHAR(10)  NOT NULL CHECK (kind IN ('trigger', 'coping')),
+    name         VARCHAR(100) NOT NULL,
+    normalized   VARCHAR(100) NOT NULL,
+    usage_count  INTEGER      NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP,
+    created_at   TIMESTAMP    NOT NULL DEFAULT now(),
+    UNIQUE (user_id, kind, normalized)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mood_tags_autocomplete
+    ON mindstep.mood_tags (user_id, kind, usage_count DESC);
+
+CREATE TABLE IF NOT EXISTS mindstep.mood_entry_tags (
+    mood_entry_id BIGINT NOT NULL REFERENCES mindstep.mood_entries (id) ON DELETE CASCADE,
+    tag_id        BIGINT NOT NULL REFERENCES mindstep.mood_tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (mood_entry_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mood_entry_tags_tag_id
+    ON mindstep.mood_entry_tags (tag_id);
+
+-- ai_mood_analysis нь бүртгэл тутамд нэг мөр (trigger, coping оноог upsert хийнэ)
+CREATE UNIQUE INDEX IF NOT EXISTS uq_ai_mood_analysis_mood_entry_id
+    ON mindstep.ai_mood_analysis (mood_entry_id);
+
+-- Одоо байгаа бүртгэлүүдийн текстийг таслал, цэгтэй таслал, мөрөөр хувааж шошго болгоно
+-- (internal/mood/form SplitTags, repository NormalizeTag-тэй ижил дүрэм).
+WITH parts AS (
+    SELECT me.id AS entry_id, me.user_id, 'trigger' AS kind, me.created_at,
+           trim(left(trim(regexp_replace(p, '\s+', ' ', 'g')), 100)) AS name
+    FROM mindstep.mood_entries me, regexp_split_to_table(coalesce(me.trigger_event, ''), '[,;\n]') AS p
+    UNION ALL
+    SELECT me.id, me.user_id, 'coping', me.created_at,
+           trim(left(trim(regexp_replace(p, '\s+', ' ', 'g')), 100))
+    FROM mindstep.mood_entries me, regexp_split_to_table(coalesce(me.coping_strategy, ''), '[,;\n]') AS p
+)
+INSERT INTO mindstep.mood_tags (user_id, kind, name, normalized, usage_count, last_used_at)
+SELECT user_id, kind, min(name), lower(name), count(DISTINCT entry_id), max(created_at)
+FROM parts
+WHERE name <> ''
+GROUP BY user_id, kind, lower(name)
+ON CONFLICT (user_id, kind, normalized) DO NOTHING;
+
+WITH parts AS (
+    SELECT me.id AS entry_id, me.user_id, 'trigger' AS kind,
+           lower(trim(left(trim(regexp_replace(p, '\s+', ' ', 'g')), 100))) AS normalized
+    FROM mindstep.mood_entries me, regexp_split_to_table(coalesce(me.trigger_event, ''), '[,;\n]') AS p
+    UNION ALL
+    SELECT me.id, me.user_id, 'coping',
+           lower(trim(left(trim(regexp_replace(p, '\s+', ' ', 'g')), 100)))
+    FROM mindstep.mood_entries me, regexp_split_to_table(coalesce(me.coping_strategy, ''), '[,;\n]') AS p
+)
+INSERT INTO mindstep.mood_entry_tags (mood_entry_id, tag_id)
+SELECT DISTINCT parts.entry_id, t.id
+FROM parts
+JOIN mindstep.mood_tags t ON t.user_id = parts.user_id AND t.kind = parts.kind AND t.normalized = parts.normalized
+WHERE parts.normalized <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+-- mood_entries.entry_date нь хэрэглэгчийн цагийн бүсээрх (users.timezone) өдөр болно.
+-- Өмнө нь серверийн цагаар бичигддэг байсан тул created_at (Asia/Ulaanbaatar)-аас дахин тооцно.
+-- Нөхөж оруулах боломж өмнө нь байгаагүй тул created_at-ийн өдөр нь мэдэрсэн өдөр. Нөхөж оруулсан
+-- бүртгэлийг дарж бичих тул entry_date-ийг хүлээн авахаас өмнө нэг удаа ажиллуулна.
+UPDATE mindstep.mood_entries me
+SET entry_date = ((me.created_at AT TIME ZONE 'Asia/Ulaanbaatar') AT TIME ZONE u.timezone)::date
+FROM mindstep.users u
+WHERE u.id = me.user_id
+  AND me.created_at IS NOT NULL
+  AND u.timezone IN (SELECT name FROM pg_timezone_names);
+
+-- Статистик, дугуй, календарь entry_date-ээр шүүнэ
+CREATE INDEX IF NOT EXISTS idx_mood_entries_user_entry_date
+    ON mindstep.mood_entries (user_id, entry_date);
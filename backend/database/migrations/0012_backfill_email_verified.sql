@@ -0,0 +1,7 @@
+-- Өмнө нь баталгаажуулах имэйл илгээдэггүй байсан тул одоо байгаа бүх бүртгэл
+-- is_email_verified = false байна. AUTH_UNVERIFIED_ACCESS=read_only асахад тэд зөвхөн
+-- уншдаг болохгүйн тул шинэ хувилбарыг гаргахаас өмнө нэг удаа ажиллуулна.
+-- Үүнээс хойш бүртгүүлсэн хэрэглэгч OTP-оор баталгаажуулна.
+UPDATE mindstep.users
+SET is_email_verified = true
+WHERE is_email_verified IS NOT TRUE;
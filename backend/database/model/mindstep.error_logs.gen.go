@@ -27,6 +27,9 @@ type ErrorLogs struct {
 	ResolvedByID    uint      `gorm:"column:resolved_by_id;type:bigint" json:"resolved_by_id"`
 	ResolutionNotes string    `gorm:"column:resolution_notes;type:text" json:"resolution_notes"`
 	CreatedAt       time.Time `gorm:"column:created_at;type:timestamp without time zone;default:now()" json:"created_at"`
+	Fingerprint     string    `gorm:"column:fingerprint;type:character varying(64)" json:"fingerprint"`
+	Occurrences     int       `gorm:"column:occurrences;type:integer;not null;default:1" json:"occurrences"`
+	LastSeenAt      time.Time `gorm:"column:last_seen_at;type:timestamp without time zone" json:"last_seen_at"`
 	User            *Users    `gorm:"foreignKey:user_id;references:id" json:"User"`
 	ResolvedBy      *Users    `gorm:"foreignKey:resolved_by_id;references:id" json:"ResolvedBy"`
 }
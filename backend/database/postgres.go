@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"mindsteps/config"
+	"mindsteps/internal/shared"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -35,7 +36,7 @@ func MustConnect(logLevel logger.LogLevel) {
 		&gorm.Config{
 			Logger: logger.Default.LogMode(logLevel),
 			NowFunc: func() time.Time {
-				return time.Now().In(time.FixedZone("Asia/Ulaanbaatar", 8*60*60))
+				return time.Now().In(shared.StoredLocation)
 			},
 		},
 	)
@@ -0,0 +1,58 @@
+package form
+
+import (
+	"fmt"
+
+	"mindsteps/internal/rbac"
+)
+
+const (
+	DefaultRateLimitPerMinute = 60
+	MaxRateLimitPerMinute     = 10000
+	MaxGraceMinutes           = 1440
+)
+
+// APIKeyForm нь API key үүсгэх болон солих (rotate) үед ашиглагдах формын бүтэц.
+// - Name: Key ашиглах системийн нэр (жишээ нь "ml-service")
+// - Scopes: {"lesson": ["write"]} хэлбэрийн эрхүүд. Өөрт байхгүй эрх олгох боломжгүй
+// - RateLimitPerMinute: Минутад зөвшөөрөх хүсэлтийн тоо (0 бол 60)
+// - ExpiresInDays: Хүчинтэй хоног (0 бол хугацаагүй)
+type APIKeyForm struct {
+	Name               string           `json:"name" validate:"required,max=50"`
+	Scopes             rbac.Permissions `json:"scopes" validate:"required"`
+	RateLimitPerMinute int              `json:"rate_limit_per_minute" validate:"omitempty,min=1,max=10000"`
+	ExpiresInDays      int              `json:"expires_in_days" validate:"omitempty,min=1"`
+}
+
+// Validate нь APIKeyForm дээрх өгөгдлийг шалгаж, RateLimitPerMinute-д анхны утга онооно.
+func (f *APIKeyForm) Validate() error {
+	if f.Name == "" || len([]rune(f.Name)) > 50 {
+		return fmt.Errorf("name 1-50 тэмдэгт байх ёстой")
+	}
+	if f.RateLimitPerMinute == 0 {
+		f.RateLimitPerMinute = DefaultRateLimitPerMinute
+	}
+	if f.RateLimitPerMinute < 1 || f.RateLimitPerMinute > MaxRateLimitPerMinute {
+		return fmt.Errorf("rate_limit_per_minute 1-%d байх ёстой", MaxRateLimitPerMinute)
+	}
+	if f.ExpiresInDays < 0 {
+		return fmt.Errorf("expires_in_days сөрөг байж болохгүй")
+	}
+	if len(f.Scopes) == 0 {
+		return fmt.Errorf("scopes хоосон байна")
+	}
+	return f.Scopes.Validate()
+}
+
+// RotateAPIKeyForm нь key солих формын бүтэц.
+// - GraceMinutes: Хуучин key хэдэн минут зэрэг ажиллах (0 бол шууд зогсоно)
+type RotateAPIKeyForm struct {
+	GraceMinutes int `json:"grace_minutes" validate:"min=0,max=1440"`
+}
+
+func (f RotateAPIKeyForm) Validate() error {
+	if f.GraceMinutes < 0 || f.GraceMinutes > MaxGraceMinutes {
+		return fmt.Errorf("grace_minutes 0-%d байх ёстой", MaxGraceMinutes)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"mindsteps/database/model"
+	"mindsteps/internal/apikey/form"
+	"mindsteps/internal/apikey/service"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/rbac"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(s service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: s}
+}
+
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	keys, err := h.service.List()
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	result := make([]fiber.Map, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, keyResponse(&key))
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"api_keys": result,
+	})
+}
+
+// Create: бүтэн key зөвхөн энэ хариунд харагдана, дахин авах боломжгүй.
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.APIKeyForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	record, key, err := h.service.Create(tokenInfo, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "API key амжилттай үүслээ. Key-г одоо хадгална уу, дахин харуулахгүй",
+		"key":     key,
+		"api_key": keyResponse(record),
+	})
+}
+
+func (h *APIKeyHandler) Rotate(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "API key id буруу байна")
+	}
+
+	var f form.RotateAPIKeyForm
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&f); err != nil {
+			return shared.ResponseBadRequest(c, err.Error())
+		}
+	}
+
+	record, key, err := h.service.Rotate(tokenInfo, uint(id), &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "API key амжилттай солигдлоо. Key-г одоо хадгална уу, дахин харуулахгүй",
+		"key":     key,
+		"api_key": keyResponse(record),
+	})
+}
+
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "API key id буруу байна")
+	}
+
+	if err := h.service.Revoke(tokenInfo, uint(id)); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "API key амжилттай цуцлагдлаа",
+	})
+}
+
+func keyResponse(key *model.APIKeys) fiber.Map {
+	scopes, err := rbac.ParsePermissions(key.Scopes)
+	if err != nil {
+		scopes = rbac.Permissions{}
+	}
+	return fiber.Map{
+		"id":                    key.ID,
+		"name":                  key.Name,
+		"prefix":                key.Prefix,
+		"scopes":                scopes,
+		"rate_limit_per_minute": key.RateLimitPerMinute,
+		"expires_at":            key.ExpiresAt,
+		"last_used_at":          key.LastUsedAt,
+		"last_used_ip":          key.LastUsedIP,
+		"revoked_at":            key.RevokedAt,
+		"rotated_from_id":       key.RotatedFromID,
+		"created_by_id":         key.CreatedByID,
+		"created_at":            key.CreatedAt,
+	}
+}
+
+func respondError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return shared.ResponseNotFound(c)
+	case errors.Is(err, service.ErrScopeNotGranted), errors.Is(err, service.ErrUserActorRequired):
+		return shared.ResponseForbidden(c)
+	default:
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	List() ([]model.APIKeys, error)
+	FindByID(id uint) (*model.APIKeys, error)
+	FindByPrefix(prefix string) (*model.APIKeys, error)
+	Create(key *model.APIKeys) error
+	Rotate(old *model.APIKeys, key *model.APIKeys, graceUntil time.Time) error
+	Revoke(id uint) (bool, error)
+	TouchLastUsed(id uint, ipAddress string) error
+}
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) List() ([]model.APIKeys, error) {
+	var keys []model.APIKeys
+	err := r.db.Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepo) FindByID(id uint) (*model.APIKeys, error) {
+	var key model.APIKeys
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepo) FindByPrefix(prefix string) (*model.APIKeys, error) {
+	var key model.APIKeys
+	if err := r.db.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create: хоосон nullable баганууд (expires_at, rotated_from_id гэх мэт) NULL хэвээр үлдэнэ.
+func (r *apiKeyRepo) Create(key *model.APIKeys) error {
+	return create(r.db, key)
+}
+
+// Rotate нь шинэ key үүсгэж, хуучныг graceUntil хүртэл хүчинтэй үлдээнэ.
+// Хуучин key-ийн хугацаа үүнээс өмнө дуусах байсан бол өөрчлөхгүй.
+func (r *apiKeyRepo) Rotate(old *model.APIKeys, key *model.APIKeys, graceUntil time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := create(tx, key); err != nil {
+			return err
+		}
+		return tx.Model(&model.APIKeys{}).
+			Where("id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", old.ID, graceUntil).
+			Update("expires_at", graceUntil).Error
+	})
+}
+
+func (r *apiKeyRepo) Revoke(id uint) (bool, error) {
+	result := r.db.Model(&model.APIKeys{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *apiKeyRepo) TouchLastUsed(id uint, ipAddress string) error {
+	return r.db.Model(&model.APIKeys{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_used_at": time.Now(),
+		"last_used_ip": ipAddress,
+	}).Error
+}
+
+func create(db *gorm.DB, key *model.APIKeys) error {
+	omit := []string{"LastUsedAt", "LastUsedIP", "RevokedAt"}
+	if key.ExpiresAt.IsZero() {
+		omit = append(omit, "ExpiresAt")
+	}
+	if key.RotatedFromID == 0 {
+		omit = append(omit, "RotatedFromID")
+	}
+	if key.CreatedByID == 0 {
+		omit = append(omit, "CreatedByID")
+	}
+	return db.Omit(omit...).Create(key).Error
+}
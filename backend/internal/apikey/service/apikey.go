@@ -0,0 +1,257 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/apikey/form"
+	"mindsteps/internal/apikey/repository"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/rbac"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// keyPrefix нь логт эсвэл git-д алдагдсан key-г танихад тусална.
+// Бүтэн key: msk_<prefix>_<secret>
+const keyPrefix = "msk"
+
+type APIKeyService interface {
+	List() ([]model.APIKeys, error)
+	Create(actor *auth.Token, f *form.APIKeyForm) (*model.APIKeys, string, error)
+	Rotate(actor *auth.Token, id uint, f *form.RotateAPIKeyForm) (*model.APIKeys, string, error)
+	Revoke(actor *auth.Token, id uint) error
+	Verify(key, ipAddress string) (*auth.Token, error)
+}
+
+var (
+	ErrAPIKeyNotFound    = errors.New("API key олдсонгүй")
+	ErrAPIKeyInactive    = errors.New("API key цуцлагдсан эсвэл хугацаа нь дууссан байна")
+	ErrScopeNotGranted   = errors.New("өөрт байхгүй эрхийг API key-д олгох боломжгүй")
+	ErrUserActorRequired = errors.New("API key-г зөвхөн хэрэглэгч удирдана")
+)
+
+type apiKeyService struct {
+	repo    repository.APIKeyRepository
+	limiter ratelimit.Store
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, limiter ratelimit.Store) APIKeyService {
+	return &apiKeyService{repo: repo, limiter: limiter}
+}
+
+func (s *apiKeyService) List() ([]model.APIKeys, error) {
+	return s.repo.List()
+}
+
+// Create нь шинэ key үүсгэнэ. Бүтэн key-г зөвхөн энд нэг удаа буцаана.
+func (s *apiKeyService) Create(actor *auth.Token, f *form.APIKeyForm) (*model.APIKeys, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+	if err := checkActor(actor, f.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	scopes, err := json.Marshal(f.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	record := &model.APIKeys{
+		Name:               f.Name,
+		Scopes:             datatypes.JSON(scopes),
+		RateLimitPerMinute: f.RateLimitPerMinute,
+		CreatedByID:        actor.UserID,
+		CreatedAt:          time.Now(),
+	}
+	if f.ExpiresInDays > 0 {
+		record.ExpiresAt = record.CreatedAt.AddDate(0, 0, f.ExpiresInDays)
+	}
+
+	key, err := generateKey(record)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Create(record); err != nil {
+		return nil, "", err
+	}
+	return record, key, nil
+}
+
+// Rotate нь ижил нэр, эрх, хязгаартай шинэ key үүсгэнэ. Хуучин key GraceMinutes
+// хугацаанд зэрэг ажиллаж, системүүд шинэ key-д шилжих боломж олгоно.
+func (s *apiKeyService) Rotate(actor *auth.Token, id uint, f *form.RotateAPIKeyForm) (*model.APIKeys, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	old, err := s.findKey(id)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if !active(old, now) {
+		return nil, "", ErrAPIKeyInactive
+	}
+
+	scopes, err := rbac.ParsePermissions(old.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkActor(actor, scopes); err != nil {
+		return nil, "", err
+	}
+
+	record := &model.APIKeys{
+		Name:               old.Name,
+		Scopes:             old.Scopes,
+		RateLimitPerMinute: old.RateLimitPerMinute,
+		RotatedFromID:      old.ID,
+		CreatedByID:        actor.UserID,
+		CreatedAt:          now,
+	}
+	// Хугацаатай key бол анхны хугацааны уртыг хадгална
+	if !old.ExpiresAt.IsZero() {
+		record.ExpiresAt = now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+	}
+
+	key, err := generateKey(record)
+	if err != nil {
+		return nil, "", err
+	}
+	graceUntil := now.Add(time.Duration(f.GraceMinutes) * time.Minute)
+	if err := s.repo.Rotate(old, record, graceUntil); err != nil {
+		return nil, "", err
+	}
+	return record, key, nil
+}
+
+func (s *apiKeyService) Revoke(actor *auth.Token, id uint) error {
+	if actor.UserID == 0 {
+		return ErrUserActorRequired
+	}
+	if _, err := s.findKey(id); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.Revoke(id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyInactive
+	}
+	log.Infof("API key %d цуцлагдлаа (user %d)", id, actor.UserID)
+	return nil
+}
+
+// Verify нь X-Api-Key-ийг шалгаж, минутын хязгаарыг тоолоод системийн identity буцаана.
+// Scope-ууд Permissions болж RequirePermission-оор шалгагдана.
+func (s *apiKeyService) Verify(key, ipAddress string) (*auth.Token, error) {
+	prefix, ok := parseKey(key)
+	if !ok {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	record, err := s.repo.FindByPrefix(prefix)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Errorf("API key хайхад алдаа: %v", err)
+		}
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(authRepo.HashToken(key)), []byte(record.KeyHash)) != 1 {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if !active(record, time.Now()) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	count, ttl, err := s.limiter.Hit("apikey:"+prefix, time.Minute)
+	if err != nil {
+		log.Errorf("API key тоолуур нэмэхэд алдаа: %v", err)
+	} else if count > int64(record.RateLimitPerMinute) {
+		return nil, ratelimit.NewLimitError(ttl)
+	}
+	// Хүсэлт бүрт бичихгүйн тулд минутын эхний хүсэлтэд л шинэчилнэ
+	if count <= 1 {
+		if err := s.repo.TouchLastUsed(record.ID, ipAddress); err != nil {
+			log.Errorf("API key-ийн last_used шинэчлэхэд алдаа: %v", err)
+		}
+	}
+
+	scopes, err := rbac.ParsePermissions(record.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.Token{
+		SystemCode:  record.Name,
+		APIKeyID:    record.ID,
+		Permissions: scopes.Flatten(),
+	}, nil
+}
+
+func (s *apiKeyService) findKey(id uint) (*model.APIKeys, error) {
+	record, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// checkActor: key-г зөвхөн хэрэглэгч удирдаж, өөрт байгаа эрхээ л олгоно.
+func checkActor(actor *auth.Token, scopes rbac.Permissions) error {
+	if actor.UserID == 0 {
+		return ErrUserActorRequired
+	}
+	for _, scope := range scopes.Flatten() {
+		if !auth.HasPermission(actor.Permissions, scope) {
+			return ErrScopeNotGranted
+		}
+	}
+	return nil
+}
+
+func active(record *model.APIKeys, now time.Time) bool {
+	return record.RevokedAt.IsZero() && (record.ExpiresAt.IsZero() || record.ExpiresAt.After(now))
+}
+
+// generateKey нь record-д prefix, hash онооно. Бүтэн key-г буцаана.
+func generateKey(record *model.APIKeys) (string, error) {
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	record.Prefix = hex.EncodeToString(prefixBytes)
+	key := keyPrefix + "_" + record.Prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	record.KeyHash = authRepo.HashToken(key)
+	return key, nil
+}
+
+func parseKey(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -0,0 +1,63 @@
+package form
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// AuditLogFilter нь system_audit_log-оос хайх шүүлтүүр. Хоосон талбар шүүхгүй.
+// - UserID: Үйлдэл хийсэн хэрэглэгч (impersonation үед ажилтан)
+// - EntityType, EntityID: Өөрчлөгдсөн бичлэг (жишээ нь goals, 12)
+// - ActionType: create, update, delete, impersonation_start, impersonation_request
+// - From, To: Огнооны хязгаар (YYYY-MM-DD, хоёр тал нь багтана)
+type AuditLogFilter struct {
+	UserID     uint
+	EntityType string
+	EntityID   uint
+	ActionType string
+	From       string
+	To         string
+	Page       int
+	Limit      int
+}
+
+// Validate нь огноог шалгаж, Page, Limit-д анхны утга онооно.
+func (f *AuditLogFilter) Validate() error {
+	if _, _, err := f.Range(); err != nil {
+		return err
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		return fmt.Errorf("limit %d-оос их байж болохгүй", MaxLimit)
+	}
+	return nil
+}
+
+// Range нь огнооны хязгаарыг буцаана. to нь дараагийн өдрийн эхлэл (хамаарахгүй) байна.
+func (f *AuditLogFilter) Range() (from, to time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.Parse(time.DateOnly, f.From); err != nil {
+			return from, to, fmt.Errorf("from YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+	}
+	if f.To != "" {
+		if to, err = time.Parse(time.DateOnly, f.To); err != nil {
+			return from, to, fmt.Errorf("to YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("to нь from-оос өмнө байж болохгүй")
+	}
+	return from, to, nil
+}
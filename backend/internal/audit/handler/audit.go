@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"mindsteps/internal/audit/form"
+	"mindsteps/internal/audit/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(s service.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// List: ?user_id=&entity_type=&entity_id=&action_type=&from=&to=&page=&limit=
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	filter := form.AuditLogFilter{
+		UserID:     uint(max(c.QueryInt("user_id"), 0)),
+		EntityType: c.Query("entity_type"),
+		EntityID:   uint(max(c.QueryInt("entity_id"), 0)),
+		ActionType: c.Query("action_type"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Page:       c.QueryInt("page", 1),
+		Limit:      c.QueryInt("limit", form.DefaultLimit),
+	}
+
+	logs, total, err := h.service.List(&filter)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"page":    filter.Page,
+		"limit":   filter.Limit,
+		"total":   total,
+		"logs":    logs,
+	})
+}
+
+func (h *AuditHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "id буруу байна")
+	}
+
+	entry, err := h.service.Get(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrAuditLogNotFound) {
+			return shared.ResponseNotFound(c)
+		}
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"log":     entry,
+	})
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"mindsteps/database/model"
+	"mindsteps/internal/audit/form"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	List(filter *form.AuditLogFilter) ([]model.SystemAuditLog, int64, error)
+	FindByID(id uint) (*model.SystemAuditLog, error)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepo{db: db}
+}
+
+// List нь шүүлтүүрт таарах бичлэгүүдийг шинээс нь эхлэн хуудаслаж, нийт тоог хамт буцаана.
+func (r *auditRepo) List(filter *form.AuditLogFilter) ([]model.SystemAuditLog, int64, error) {
+	query := r.db.Model(&model.SystemAuditLog{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActionType != "" {
+		query = query.Where("action_type = ?", filter.ActionType)
+	}
+	from, to, err := filter.Range()
+	if err != nil {
+		return nil, 0, err
+	}
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []model.SystemAuditLog
+	err = query.Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+func (r *auditRepo) FindByID(id uint) (*model.SystemAuditLog, error) {
+	var entry model.SystemAuditLog
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/audit/form"
+	"mindsteps/internal/audit/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrAuditLogNotFound = errors.New("audit log олдсонгүй")
+
+type AuditService interface {
+	List(filter *form.AuditLogFilter) ([]model.SystemAuditLog, int64, error)
+	Get(id uint) (*model.SystemAuditLog, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) List(filter *form.AuditLogFilter) ([]model.SystemAuditLog, int64, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(filter)
+}
+
+func (s *auditService) Get(id uint) (*model.SystemAuditLog, error) {
+	entry, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAuditLogNotFound
+	}
+	return entry, err
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHeader нь системүүд (ML service, тайлангийн job) API key дамжуулах header
+const APIKeyHeader = "X-Api-Key"
+
+var ErrInvalidAPIKey = errors.New("API key буруу, хугацаа дууссан эсвэл цуцлагдсан байна")
+
+// APIKeyVerifier нь API key-г шалгаж системийн identity (UserID = 0) буцаана.
+// Scope-ууд Permissions-д орох тул RequirePermission шууд ажиллана.
+type APIKeyVerifier interface {
+	Verify(key, ipAddress string) (*Token, error)
+}
+
+// APIKeys нь apikey модулийн бүртгэх verifier. Nil бол API key хүлээж авахгүй.
+var APIKeys APIKeyVerifier
+
+func authenticateAPIKey(c *fiber.Ctx) (*Token, error) {
+	key := c.Get(APIKeyHeader)
+	if key == "" || APIKeys == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	return APIKeys.Verify(key, c.IP())
+}
+
+// APIKeyMiddleware нь зөвхөн X-Api-Key-ээр нэвтрэх системийн endpoint-д хэрэглэнэ.
+// Scope-ийг route бүр дээр RequireScope-оор шалгана.
+func APIKeyMiddleware(c *fiber.Ctx) error {
+	claims, err := authenticateAPIKey(c)
+	if err != nil {
+		return respondAuthError(c, err)
+	}
+
+	c.Locals("tokenInfo", claims)
+	return c.Next()
+}
+
+// RequireScope нь өмнөх middleware-ийн тавьсан tokenInfo-д эрх байгаа эсэхийг шалгана.
+func RequireScope(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenInfo := GetTokenInfo(c)
+		if tokenInfo == nil {
+			return shared.ResponseUnauthorized(c)
+		}
+		if !HasPermission(tokenInfo.Permissions, permission) {
+			return shared.ResponseForbidden(c)
+		}
+		return c.Next()
+	}
+}
+
+// respondAuthError нь API key-ийн минутын хязгаар хэтэрсэн бол 429, бусад үед 401 буцаана.
+func respondAuthError(c *fiber.Ctx, err error) error {
+	var limitErr *ratelimit.LimitError
+	if errors.As(err, &limitErr) {
+		return shared.ResponseTooManyRequests(c, limitErr.Message, limitErr.RetryAfterSeconds())
+	}
+	return shared.ResponseUnauthorized(c)
+}
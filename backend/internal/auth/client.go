@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientInfo нь session үүсгэх үед хадгалах төхөөрөмжийн мэдээлэл
+type ClientInfo struct {
+	IPAddress  string
+	UserAgent  string
+	DeviceInfo string
+}
+
+// GetClientInfo нь fiber context-оос IP, User-Agent болон төхөөрөмжийн нэрийг авна.
+// Client "X-Device-Info" header илгээсэн бол түүнийг шууд ашиглана.
+func GetClientInfo(c *fiber.Ctx) ClientInfo {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+
+	device := strings.TrimSpace(c.Get("X-Device-Info"))
+	if device == "" {
+		device = describeDevice(userAgent)
+	}
+
+	return ClientInfo{
+		IPAddress:  c.IP(),
+		UserAgent:  userAgent,
+		DeviceInfo: truncate(device, 255),
+	}
+}
+
+// describeDevice нь User-Agent мөрөөс "Chrome on Windows" гэх мэт товч тайлбар гаргана.
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Тодорхойгүй төхөөрөмж"
+	}
+
+	ua := strings.ToLower(userAgent)
+
+	os := "Тодорхойгүй OS"
+	switch {
+	case strings.Contains(ua, "iphone"):
+		os = "iPhone"
+	case strings.Contains(ua, "ipad"):
+		os = "iPad"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	browser := ""
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/"):
+		browser = "Opera"
+	case strings.Contains(ua, "chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	}
+
+	if browser == "" {
+		return os
+	}
+	return browser + " on " + os
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -3,6 +3,7 @@ package form
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -142,3 +143,162 @@ func (f VerifyOTPForm) Validate() error {
 	}
 	return nil
 }
+
+type RefreshTokenForm struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func (f RefreshTokenForm) Validate() error {
+	if f.RefreshToken == "" {
+		return fmt.Errorf("refresh_token хоосон байна")
+	}
+	return nil
+}
+
+type ResendOTPForm struct {
+	Email   string `json:"email" validate:"required,email"`
+	OTPType string `json:"otp_type" validate:"required,oneof=email_verification password_reset"`
+}
+
+func (f ResendOTPForm) Validate() error {
+	if f.Email == "" {
+		return fmt.Errorf("email хоосон байна")
+	}
+	if f.OTPType != "email_verification" && f.OTPType != "password_reset" {
+		return fmt.Errorf("otp_type email_verification эсвэл password_reset байх ёстой")
+	}
+	return nil
+}
+
+// NotMeForm нь "Энэ би биш" имэйлийн холбоосоор ирсэн token
+type NotMeForm struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (f NotMeForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("token хоосон байна")
+	}
+	return nil
+}
+
+// MFACodeForm нь authenticator app-ийн 6 оронтой код эсвэл сэргээх код
+type MFACodeForm struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func (f MFACodeForm) Validate() error {
+	if f.Code == "" {
+		return fmt.Errorf("code хоосон байна")
+	}
+	return nil
+}
+
+// MFAVerifyForm нь нэвтрэх үед буцаасан mfa_token болон кодыг илгээнэ.
+type MFAVerifyForm struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+func (f MFAVerifyForm) Validate() error {
+	if f.MFAToken == "" {
+		return fmt.Errorf("mfa_token хоосон байна")
+	}
+	if f.Code == "" {
+		return fmt.Errorf("code хоосон байна")
+	}
+	return nil
+}
+
+type MagicLinkForm struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (f MagicLinkForm) Validate() error {
+	if f.Email == "" {
+		return fmt.Errorf("email хоосон байна")
+	}
+	return nil
+}
+
+// MagicLinkLoginForm нь имэйлээр ирсэн нэвтрэх холбоосын token
+type MagicLinkLoginForm struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (f MagicLinkLoginForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("token хоосон байна")
+	}
+	return nil
+}
+
+// EmailChangeForm нь email солих хүсэлт. Одоогийн нууц үгээр дахин баталгаажуулна.
+type EmailChangeForm struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (f EmailChangeForm) Validate() error {
+	if f.NewEmail == "" {
+		return fmt.Errorf("new_email хоосон байна")
+	}
+	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	if !emailRegex.MatchString(f.NewEmail) {
+		return fmt.Errorf("new_email буруу форматтай байна")
+	}
+	if f.Password == "" {
+		return fmt.Errorf("password хоосон байна")
+	}
+	return nil
+}
+
+// EmailChangeConfirmForm нь шинэ хаяг руу илгээсэн 6 оронтой код
+type EmailChangeConfirmForm struct {
+	OTPCode string `json:"otp_code" validate:"required,len=6"`
+}
+
+func (f EmailChangeConfirmForm) Validate() error {
+	if f.OTPCode == "" {
+		return fmt.Errorf("otp_code хоосон байна")
+	}
+	if len(f.OTPCode) != 6 {
+		return fmt.Errorf("otp_code 6 оронтой байх ёстой")
+	}
+	return nil
+}
+
+// EmailChangeCancelForm нь хуучин хаяг руу илгээсэн цуцлах холбоосын token
+type EmailChangeCancelForm struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (f EmailChangeCancelForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("token хоосон байна")
+	}
+	return nil
+}
+
+// ImpersonateForm нь тусламжийн ажилтан хэрэглэгчийн нэрийн өмнөөс нэвтрэх хүсэлт.
+// - Reason: Шалтгаан (тикетийн дугаар гэх мэт). Audit log болон хэрэглэгчийн мэдэгдэлд орно
+// - Minutes: Хүчинтэй хугацаа (0 бол 30, дээд тал нь 60)
+// - AllowWrite: Өөрчлөх хүсэлт илгээх эсэх. Анхдагчаар зөвхөн уншина
+type ImpersonateForm struct {
+	Reason     string `json:"reason" validate:"required,max=255"`
+	Minutes    int    `json:"minutes" validate:"omitempty,min=1,max=60"`
+	AllowWrite bool   `json:"allow_write"`
+}
+
+func (f ImpersonateForm) Validate() error {
+	if strings.TrimSpace(f.Reason) == "" {
+		return fmt.Errorf("reason хоосон байна")
+	}
+	if utf8.RuneCountInString(f.Reason) > 255 {
+		return fmt.Errorf("reason 255 тэмдэгтээс урт байж болохгүй")
+	}
+	if f.Minutes < 0 || f.Minutes > 60 {
+		return fmt.Errorf("minutes 1-60 байх ёстой")
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
 	"mindsteps/internal/auth/form"
 	"mindsteps/internal/auth/service"
+	"mindsteps/internal/ratelimit"
 	"mindsteps/internal/shared"
 	"strings"
 
@@ -27,15 +31,17 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	token, user, err := h.service.Register(&f)
+	pair, user, err := h.service.Register(&f, auth.GetClientInfo(c))
 	if err != nil {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"message": "Амжилттай бүртгэгдлээ",
-		"token":   token,
+		"success":       true,
+		"message":       "Амжилттай бүртгэгдлээ",
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
 		"user": fiber.Map{
 			"id":    user.ID,
 			"uuid":  user.UUID,
@@ -55,15 +61,25 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	token, user, err := h.service.Login(&f)
+	pair, user, err := h.service.Login(&f, auth.GetClientInfo(c))
 	if err != nil {
-		return shared.ResponseBadRequest(c, err.Error())
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			return respondMFARequired(c, mfaErr)
+		}
+		return respondError(c, err)
 	}
 
+	return respondLogin(c, pair, user)
+}
+
+func respondLogin(c *fiber.Ctx, pair *auth.TokenPair, user *model.Users) error {
 	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Амжилттай нэвтэрлээ",
-		"token":   token,
+		"success":       true,
+		"message":       "Амжилттай нэвтэрлээ",
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
 		"user": fiber.Map{
 			"id":                user.ID,
 			"uuid":              user.UUID,
@@ -76,6 +92,29 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	})
 }
 
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var f form.RefreshTokenForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	pair, err := h.service.Refresh(&f)
+	if err != nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":       true,
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
+	})
+}
+
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
@@ -104,8 +143,8 @@ func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	if err := h.service.ForgotPassword(&f); err != nil {
-		return shared.ResponseBadRequest(c, err.Error())
+	if err := h.service.ForgotPassword(&f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -124,8 +163,8 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	if err := h.service.ResetPassword(&f); err != nil {
-		return shared.ResponseBadRequest(c, err.Error())
+	if err := h.service.ResetPassword(&f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -144,8 +183,8 @@ func (h *AuthHandler) VerifyOTP(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	if err := h.service.VerifyOTP(&f); err != nil {
-		return shared.ResponseBadRequest(c, err.Error())
+	if err := h.service.VerifyOTP(&f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -153,3 +192,94 @@ func (h *AuthHandler) VerifyOTP(c *fiber.Ctx) error {
 		"message": "Email амжилттай баталгаажлаа",
 	})
 }
+
+func (h *AuthHandler) ResendOTP(c *fiber.Ctx) error {
+	var f form.ResendOTPForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.ResendOTP(&f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "OTP код таны email хаяг руу дахин илгээгдлээ",
+	})
+}
+
+func (h *AuthHandler) NotMe(c *fiber.Ctx) error {
+	var f form.NotMeForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.RevokeSessionByLink(&f, auth.GetClientInfo(c)); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Тухайн нэвтрэлтийг хаалаа. Нууц үгээ даруй солино уу",
+	})
+}
+
+func (h *AuthHandler) SendMagicLink(c *fiber.Ctx) error {
+	var f form.MagicLinkForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.SendMagicLink(&f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Нэвтрэх холбоос таны email хаяг руу илгээгдлээ",
+	})
+}
+
+func (h *AuthHandler) LoginWithMagicLink(c *fiber.Ctx) error {
+	var f form.MagicLinkLoginForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	pair, user, err := h.service.LoginWithMagicLink(&f, auth.GetClientInfo(c))
+	if err != nil {
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			return respondMFARequired(c, mfaErr)
+		}
+		return respondError(c, err)
+	}
+
+	return respondLogin(c, pair, user)
+}
+
+// respondError нь хязгаар хэтэрсэн алдааг 429, бусдыг 400 болгон буцаана.
+func respondError(c *fiber.Ctx, err error) error {
+	var limitErr *ratelimit.LimitError
+	if errors.As(err, &limitErr) {
+		return shared.ResponseTooManyRequests(c, limitErr.Message, limitErr.RetryAfterSeconds())
+	}
+	return shared.ResponseBadRequest(c, err.Error())
+}
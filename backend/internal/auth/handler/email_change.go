@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"mindsteps/internal/auth"
+	"mindsteps/internal/auth/form"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) RequestEmailChange(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.EmailChangeForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.RequestEmailChange(tokenInfo.UserID, &f, auth.GetClientInfo(c)); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Баталгаажуулах код шинэ email хаяг руу илгээгдлээ",
+	})
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.EmailChangeConfirmForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	user, err := h.service.ConfirmEmailChange(tokenInfo.UserID, tokenInfo.SessionID, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Email амжилттай солигдлоо",
+		"user": fiber.Map{
+			"id":                user.ID,
+			"email":             user.Email,
+			"is_email_verified": user.IsEmailVerified,
+		},
+	})
+}
+
+func (h *AuthHandler) CancelEmailChange(c *fiber.Ctx) error {
+	var f form.EmailChangeCancelForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.CancelEmailChange(&f, auth.GetClientInfo(c)); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Email солих хүсэлтийг цуцаллаа",
+	})
+}
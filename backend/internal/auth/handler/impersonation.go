@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+
+	"mindsteps/internal/auth"
+	"mindsteps/internal/auth/form"
+	"mindsteps/internal/auth/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) Impersonate(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return shared.ResponseBadRequest(c, "user id буруу байна")
+	}
+
+	var f form.ImpersonateForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	result, err := h.service.Impersonate(tokenInfo, uint(userID), &f, auth.GetClientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImpersonationTarget):
+			return shared.ResponseNotFound(c)
+		case errors.Is(err, service.ErrImpersonationForbidden):
+			return shared.ResponseForbidden(c)
+		}
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":       true,
+		"message":       "Хэрэглэгчийн нэрийн өмнөөс нэвтрэх token олгогдлоо. Бүх хүсэлт бүртгэгдэнэ",
+		"impersonation": result,
+	})
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"mindsteps/internal/auth"
+	"mindsteps/internal/auth/form"
+	"mindsteps/internal/auth/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// respondMFARequired: password зөв боловч session нээхийн өмнө кодыг /auth/mfa/verify-ээр шалгана.
+func respondMFARequired(c *fiber.Ctx, mfaErr *service.MFARequiredError) error {
+	return c.JSON(fiber.Map{
+		"success":      true,
+		"message":      "Authenticator app-ийн кодоо оруулна уу",
+		"mfa_required": true,
+		"mfa_token":    mfaErr.Token,
+		"expires_in":   mfaErr.ExpiresIn,
+	})
+}
+
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	var f form.MFAVerifyForm
+
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	pair, user, err := h.service.VerifyMFA(&f, auth.GetClientInfo(c))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return respondLogin(c, pair, user)
+}
+
+func (h *AuthHandler) SetupMFA(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	setup, err := h.service.SetupMFA(tokenInfo.UserID)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":          true,
+		"message":          "Authenticator app-аар QR кодыг уншуулаад гарсан кодоор баталгаажуулна уу",
+		"secret":           setup.Secret,
+		"provisioning_uri": setup.ProvisioningURI,
+	})
+}
+
+func (h *AuthHandler) EnableMFA(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.MFACodeForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	codes, err := h.service.EnableMFA(tokenInfo.UserID, tokenInfo.SessionID, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":        true,
+		"message":        "Хоёр шатат баталгаажуулалт идэвхжлээ. Сэргээх кодуудаа аюулгүй газар хадгална уу",
+		"recovery_codes": codes,
+	})
+}
+
+func (h *AuthHandler) DisableMFA(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.MFACodeForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.DisableMFA(tokenInfo.UserID, &f); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Хоёр шатат баталгаажуулалтыг унтраалаа",
+	})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.MFACodeForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(tokenInfo.UserID, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":        true,
+		"message":        "Шинэ сэргээх кодууд үүслээ. Хуучин кодууд хүчингүй боллоо",
+		"recovery_codes": codes,
+	})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"mindsteps/internal/auth"
+	"mindsteps/internal/auth/service"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/oidc"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	oidcStateCookie  = "oidc_state"
+	oidcCookiePath   = "/api/v1/auth/oidc"
+	oidcStateMinutes = 10
+	// oidcResultPath нь frontend дээр token-ийг fragment-аас авах хуудас
+	oidcResultPath = "/auth/oidc/callback"
+)
+
+type OIDCHandler struct {
+	service   service.AuthService
+	providers *oidc.Registry
+}
+
+func NewOIDCHandler(s service.AuthService, providers *oidc.Registry) *OIDCHandler {
+	return &OIDCHandler{service: s, providers: providers}
+}
+
+// Start нь state, nonce, PKCE verifier-ийг cookie-д хадгалаад хэрэглэгчийг provider руу шилжүүлнэ.
+func (h *OIDCHandler) Start(c *fiber.Ctx) error {
+	provider, err := h.providers.Get(c.Params("provider"))
+	if err != nil {
+		return shared.ResponseNotFound(c)
+	}
+
+	req, err := oidc.NewAuthRequest()
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	authURL, err := provider.AuthCodeURL(c.UserContext(), req.State, req.Nonce, req.Verifier)
+	if err != nil {
+		log.Errorf("%s OIDC эхлүүлэхэд алдаа: %v", provider.Name(), err)
+		return shared.ResponseErr(c, "нэвтрэх үйлчилгээтэй холбогдож чадсангүй")
+	}
+
+	state, err := auth.CreatePurposeToken(&auth.Token{
+		OIDC: &auth.OIDCState{
+			Provider: provider.Name(),
+			State:    req.State,
+			Nonce:    req.Nonce,
+			Verifier: req.Verifier,
+		},
+	}, auth.PurposeOIDCState, oidcStateMinutes)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	// Apple callback нь cross-site POST тул SameSite=None шаардлагатай
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcCookiePath,
+		MaxAge:   oidcStateMinutes * 60,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteNoneMode,
+	})
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Callback нь provider-оос ирсэн code-ийг сольж, хэрэглэгчийг нэвтрүүлээд
+// frontend руу token-уудыг URL fragment-аар дамжуулна.
+func (h *OIDCHandler) Callback(c *fiber.Ctx) error {
+	provider, err := h.providers.Get(c.Params("provider"))
+	if err != nil {
+		return shared.ResponseNotFound(c)
+	}
+
+	param := c.Query
+	if provider.FormPost() && c.Method() == fiber.MethodPost {
+		param = c.FormValue
+	}
+
+	stateCookie := c.Cookies(oidcStateCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcCookiePath,
+		Expires:  time.Unix(0, 0),
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteNoneMode,
+	})
+
+	if providerErr := param("error"); providerErr != "" {
+		return redirectOIDCResult(c, url.Values{"error": {providerErr}})
+	}
+
+	claims, err := auth.ReadPurposeToken(stateCookie, auth.PurposeOIDCState)
+	if err != nil || claims.OIDC == nil || claims.OIDC.Provider != provider.Name() ||
+		subtle.ConstantTimeCompare([]byte(claims.OIDC.State), []byte(param("state"))) != 1 {
+		return redirectOIDCResult(c, url.Values{"error": {"invalid_state"}})
+	}
+
+	identity, err := provider.Exchange(c.UserContext(), param("code"), claims.OIDC.Verifier, claims.OIDC.Nonce)
+	if err != nil {
+		log.Warnf("%s OIDC callback алдаа: %v", provider.Name(), err)
+		return redirectOIDCResult(c, url.Values{"error": {"invalid_token"}})
+	}
+
+	pair, _, err := h.service.LoginWithIdentity(identity, auth.GetClientInfo(c))
+	if err != nil {
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			return redirectOIDCResult(c, url.Values{
+				"mfa_token":  {mfaErr.Token},
+				"expires_in": {strconv.FormatInt(mfaErr.ExpiresIn, 10)},
+			})
+		}
+
+		code := "login_failed"
+		if errors.Is(err, service.ErrOIDCEmailNotVerified) {
+			code = "email_not_verified"
+		}
+		log.Warnf("%s OIDC нэвтрэлт амжилтгүй (sub %s): %v", provider.Name(), identity.Subject, err)
+		return redirectOIDCResult(c, url.Values{"error": {code}})
+	}
+
+	return redirectOIDCResult(c, url.Values{
+		"token":         {pair.AccessToken},
+		"refresh_token": {pair.RefreshToken},
+		"expires_in":    {strconv.FormatInt(pair.ExpiresIn, 10)},
+	})
+}
+
+// redirectOIDCResult: fragment нь серверийн лог болон Referer-т үлддэггүй.
+func redirectOIDCResult(c *fiber.Ctx, values url.Values) error {
+	return c.Redirect(mailer.Link(oidcResultPath, nil)+"#"+values.Encode(), fiber.StatusFound)
+}
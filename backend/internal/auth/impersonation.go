@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mindsteps/database/model"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// system_audit_log.action_type-д бичигдэх impersonation-ий утгууд
+const (
+	AuditImpersonationStart   = "impersonation_start"
+	AuditImpersonationRequest = "impersonation_request"
+)
+
+var ErrAuditUnavailable = errors.New("audit log бүртгэх боломжгүй байна")
+
+// AuditRecorder нь system_audit_log-д бичнэ. AuditSnapshot нь AuditMiddleware-д
+// өөрчлөлтийн өмнөх, дараах бичлэгийг авна.
+type AuditRecorder interface {
+	CreateAuditLog(entry *model.SystemAuditLog) error
+	AuditSnapshot(table string, id uint) (map[string]interface{}, error)
+}
+
+var Audit AuditRecorder
+
+func InitAudit(db *gorm.DB) {
+	Audit = authRepo.NewAuthRepository(db)
+}
+
+// recordImpersonation нь impersonation token-оор ирсэн хүсэлтийг (татгалзсан ч) бүртгэнэ.
+// user_id нь ажилтан, entity_id нь нэрийн өмнөөс нь хандсан хэрэглэгч.
+func recordImpersonation(c *fiber.Ctx, claims *Token) error {
+	if Audit == nil {
+		return ErrAuditUnavailable
+	}
+
+	details, err := json.Marshal(fiber.Map{
+		"method":     c.Method(),
+		"path":       c.OriginalURL(),
+		"session_id": claims.SessionID,
+		"write":      claims.ImpersonationWrite,
+	})
+	if err != nil {
+		return err
+	}
+
+	return Audit.CreateAuditLog(&model.SystemAuditLog{
+		UserID:     claims.ImpersonatorID,
+		ActionType: AuditImpersonationRequest,
+		EntityType: "users",
+		EntityID:   claims.UserID,
+		NewValue:   datatypes.JSON(details),
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		CreatedAt:  time.Now(),
+	})
+}
+
+// allowImpersonation: impersonation token анхдагчаар зөвхөн унших хүсэлт илгээнэ.
+func allowImpersonation(c *fiber.Ctx, claims *Token) bool {
+	return claims.ImpersonatorID == 0 || claims.ImpersonationWrite || isReadMethod(c)
+}
+
+func responseImpersonationReadOnly(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"message":       "Тусламжийн хандалт зөвхөн унших эрхтэй",
+		"impersonation": true,
+	})
+}
+
+// DenyImpersonation нь нууц үг, email, MFA, session зэрэг бүртгэлийг хамгаалах
+// үйлдлийг тусламжийн ажилтан засах эрхтэй байсан ч хийхээс сэргийлнэ.
+// Token шалгах middleware-ийн дараа тавина.
+func DenyImpersonation(c *fiber.Ctx) error {
+	tokenInfo := GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+	if tokenInfo.ImpersonatorID != 0 {
+		return shared.ResponseForbidden(c)
+	}
+	return c.Next()
+}
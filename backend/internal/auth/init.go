@@ -1,32 +1,90 @@
 package auth
 
 import (
+	"context"
+	"encoding/base64"
+	"time"
+
 	"mindsteps/config"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/scheduler"
+	"mindsteps/pkg/totp"
 
 	"github.com/gofiber/fiber/v2/log"
-	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
 )
 
-func MustInitGjwt() {
-	config := config.Get().Auth
+// keyReloadInterval нь өөр процесс (cmd/jwtkeys) хийсэн rotation-ийг сервер хэр хурдан авахыг заана.
+const keyReloadInterval = 5 * time.Minute
 
-	var (
-		result GJWT
-	)
+func MustInitGjwt(db *gorm.DB) {
+	repo := authRepo.NewKeyRepository(db)
 
-	private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.JwtPrivateKey))
+	ring, err := loadConfiguredKeyring(repo)
 	if err != nil {
-		log.Fatal("error parsing private key", err)
+		log.Fatal("error loading jwt keys: ", err)
 		return
 	}
-	result.Private = private
 
-	public, err := jwt.ParseRSAPublicKeyFromPEM([]byte(config.JwtPublicKey))
-	if err != nil {
-		log.Fatal("error parsing public key", err)
+	gjwt := NewGJWT(ring.SigningKeyID, ring.Private, ring.PublicKeys)
+	Gjwt = gjwt
+
+	scheduler.Register(scheduler.Job{
+		Name:     "jwt-keys-reload",
+		Interval: keyReloadInterval,
+		Run: func(ctx context.Context) error {
+			ring, err := loadConfiguredKeyring(repo)
+			if err != nil {
+				return err
+			}
+			gjwt.SetKeys(ring.SigningKeyID, ring.Private, ring.PublicKeys)
+			return nil
+		},
+	})
+}
+
+// InitUnverifiedAccess нь AUTH_UNVERIFIED_ACCESS тохиргоог middleware-д ачаална.
+func InitUnverifiedAccess() {
+	mode := config.Get().Auth.UnverifiedAccess
+	if mode != UnverifiedReadOnly && mode != UnverifiedFull {
+		log.Fatal("AUTH_UNVERIFIED_ACCESS нь read_only эсвэл full байх ёстой")
+	}
+	unverifiedAccess = mode
+}
+
+// MFAKey нь TOTP нууцыг шифрлэх түлхүүр (totp.SealSecret). InitMFAKey ачаална.
+// Хоосон бол хоёр шатат баталгаажуулалтыг тохируулах, шалгах боломжгүй.
+var MFAKey []byte
+
+// InitMFAKey нь AUTH_MFA_KEY (base64, 32 байт)-г ачаална. Үүсгэх: openssl rand -base64 32
+// Тохируулаагүй эсвэл буруу бол сервер ажиллаж, зөвхөн MFA идэвхгүй болно.
+func InitMFAKey() {
+	raw := config.Get().Auth.MfaKey
+	if raw == "" {
+		log.Warn("AUTH_MFA_KEY тохируулаагүй тул хоёр шатат баталгаажуулалт идэвхгүй байна")
+		return
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) != totp.KeySize {
+		log.Warn("AUTH_MFA_KEY нь base64 хэлбэрийн 32 байт түлхүүр биш тул хоёр шатат баталгаажуулалт идэвхгүй байна")
 		return
 	}
-	result.Public = public
+	MFAKey = key
+}
+
+// SecondFactorVerifier нь хэрэглэгчийн TOTP эсвэл сэргээх кодыг шалгана. Бүртгэл устгах
+// зэрэг бусад модулийн дахин баталгаажуулалтад хэрэглэнэ.
+type SecondFactorVerifier interface {
+	VerifySecondFactor(userID uint, code string) error
+}
+
+// SecondFactor нь auth модулийн бүртгэх verifier. Nil бол MFA-тай хэрэглэгчийг баталгаажуулахгүй.
+var SecondFactor SecondFactorVerifier
 
-	Gjwt = &result
+func loadConfiguredKeyring(repo authRepo.KeyRepository) (*Keyring, error) {
+	cfg := config.Get().Auth
+	return LoadKeyring(repo, cfg.JwtKeyDir, LegacyKeys{
+		PrivatePEM: cfg.JwtPrivateKey,
+		PublicPEM:  cfg.JwtPublicKey,
+	})
 }
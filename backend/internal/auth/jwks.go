@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWK нь RFC 7517-ийн RSA public key
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKSet нь шалгалтын түлхүүрүүдийг kid-ээр эрэмбэлсэн JWKS болгоно.
+func NewJWKSet(keys map[string]*rsa.PublicKey) JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// JWKSHandler нь /.well-known/jwks.json - ML service болон frontend token шалгахад ашиглана.
+func JWKSHandler(c *fiber.Ctx) error {
+	gjwt, ok := Gjwt.(*GJWT)
+	if !ok {
+		return c.JSON(JWKSet{Keys: []JWK{}})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(NewJWKSet(gjwt.PublicKeys()))
+}
@@ -3,14 +3,23 @@ package auth
 import (
 	"crypto/rsa"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// LegacyKeyID нь env (AUTH_JWT_PRIVATE_KEY)-ээс уншсан түлхүүрийн kid.
+// kid header-гүй хуучин token-уудыг энэ түлхүүрээр шалгана.
+const LegacyKeyID = "env"
+
+// GJWT нь token гарын үсэг зурах идэвхтэй түлхүүр болон
+// kid-ээр сонгогдох шалгалтын түлхүүрүүдийг хадгална.
 type GJWT struct {
-	Private *rsa.PrivateKey
-	Public  *rsa.PublicKey
+	mu      sync.RWMutex
+	keyID   string
+	private *rsa.PrivateKey
+	keys    map[string]*rsa.PublicKey
 }
 
 type TokenGenerator interface {
@@ -20,12 +29,48 @@ type TokenGenerator interface {
 
 var Gjwt TokenGenerator
 
+// NewGJWT нь signingKeyID-тай түлхүүрээр гарын үсэг зурж, keys доторх бүх түлхүүрээр шалгах GJWT үүсгэнэ.
+func NewGJWT(signingKeyID string, private *rsa.PrivateKey, keys map[string]*rsa.PublicKey) *GJWT {
+	g := &GJWT{}
+	g.SetKeys(signingKeyID, private, keys)
+	return g
+}
+
+// SetKeys нь түлхүүрүүдийг ажиллаж буй серверт солино (key rotation-ий дараа reload хийхэд).
+func (g *GJWT) SetKeys(signingKeyID string, private *rsa.PrivateKey, keys map[string]*rsa.PublicKey) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.keyID = signingKeyID
+	g.private = private
+	g.keys = keys
+}
+
+// PublicKeys нь JWKS-д нийтлэх шалгалтын түлхүүрүүдийн хуулбар
+func (g *GJWT) PublicKeys() map[string]*rsa.PublicKey {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys := make(map[string]*rsa.PublicKey, len(g.keys))
+	for kid, key := range g.keys {
+		keys[kid] = key
+	}
+	return keys
+}
+
 func (g *GJWT) GenerateToken(claims *Token, expiresMinut time.Duration) (string, error) {
+	g.mu.RLock()
+	keyID, private := g.keyID, g.private
+	g.mu.RUnlock()
+
 	claims.RegisteredClaims = jwt.RegisteredClaims{
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * expiresMinut)),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(g.Private)
+	if keyID != LegacyKeyID {
+		token.Header["kid"] = keyID
+	}
+	return token.SignedString(private)
 }
 
 func (g *GJWT) ReadToken(inToken string) (*Token, error) {
@@ -37,13 +82,33 @@ func (g *GJWT) ReadToken(inToken string) (*Token, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return g.Public, nil
+		return g.verificationKey(token.Header["kid"])
 	}); err != nil {
 		return nil, err
 	}
 	return &t, nil
 }
 
+func (g *GJWT) verificationKey(kid interface{}) (*rsa.PublicKey, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keyID := LegacyKeyID
+	if kid != nil {
+		id, ok := kid.(string)
+		if !ok {
+			return nil, fmt.Errorf("kid header буруу байна")
+		}
+		keyID = id
+	}
+
+	key, ok := g.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("тодорхойгүй kid: %s", keyID)
+	}
+	return key, nil
+}
+
 type ResToken struct {
 	Token string `json:"token"`
 }
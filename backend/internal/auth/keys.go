@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mindsteps/database/model"
+	authRepo "mindsteps/internal/auth/repository"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// jwtKeyBits нь шинээр үүсгэх RSA түлхүүрийн урт
+const jwtKeyBits = 2048
+
+// JWTKeyID нь encryption_keys-ийн хувилбараас JWT header-ийн kid үүсгэнэ.
+func JWTKeyID(version int) string {
+	return fmt.Sprintf("jwt-v%d", version)
+}
+
+// Keyring нь ачаалсан түлхүүрүүд
+type Keyring struct {
+	SigningKeyID string
+	Private      *rsa.PrivateKey
+	PublicKeys   map[string]*rsa.PublicKey
+}
+
+// LegacyKeys нь env-ээс ирсэн PEM түлхүүрийн хос. Хоосон байж болно.
+type LegacyKeys struct {
+	PrivatePEM string
+	PublicPEM  string
+}
+
+// LoadKeyring нь encryption_keys (key_name=jwt)-д бүртгэлтэй, хугацаа нь дуусаагүй түлхүүрүүдийг
+// keyDir/<kid>.pem файлаас уншина. Файл нь байхгүй идэвхгүй түлхүүрийг анхааруулгатай алгасна.
+// Идэвхтэй түлхүүр бүртгэлгүй бол env түлхүүрээр гарын үсэг зурна.
+func LoadKeyring(repo authRepo.KeyRepository, keyDir string, legacy LegacyKeys) (*Keyring, error) {
+	ring := &Keyring{PublicKeys: map[string]*rsa.PublicKey{}}
+
+	var legacyPrivate *rsa.PrivateKey
+	if legacy.PrivatePEM != "" && legacy.PublicPEM != "" {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(legacy.PrivatePEM))
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_JWT_PRIVATE_KEY задлахад алдаа: %w", err)
+		}
+		public, err := jwt.ParseRSAPublicKeyFromPEM([]byte(legacy.PublicPEM))
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_JWT_PUBLIC_KEY задлахад алдаа: %w", err)
+		}
+		legacyPrivate = private
+		ring.PublicKeys[LegacyKeyID] = public
+	}
+
+	keys, err := repo.ListKeys(authRepo.JWTKeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if !isKeyUsable(key, now) {
+			continue
+		}
+
+		kid := JWTKeyID(key.KeyVersion)
+		private, err := ReadKeyFile(keyDir, kid)
+		if err != nil {
+			// Өөр host дээр үүсгэсэн шалгалтын түлхүүр энд байхгүй байж болно. Зөвхөн
+			// идэвхтэй түлхүүр дутвал гарын үсэг зурах боломжгүй тул алдаа буцаана.
+			if !key.IsActive && errors.Is(err, fs.ErrNotExist) {
+				log.Warnf("JWT түлхүүр алгаслаа: %v", err)
+				continue
+			}
+			return nil, err
+		}
+		ring.PublicKeys[kid] = &private.PublicKey
+
+		if key.IsActive {
+			ring.SigningKeyID = kid
+			ring.Private = private
+		}
+	}
+
+	if ring.Private == nil {
+		if legacyPrivate == nil {
+			return nil, errors.New("JWT гарын үсгийн идэвхтэй түлхүүр олдсонгүй")
+		}
+		ring.SigningKeyID = LegacyKeyID
+		ring.Private = legacyPrivate
+	}
+
+	return ring, nil
+}
+
+// isKeyUsable: идэвхтэй, эсвэл дараагийн rotation-д бэлтгэсэн (expires_at хоосон),
+// эсвэл rotation хийгдсэн ч шалгалтын хугацаа нь дуусаагүй түлхүүр.
+func isKeyUsable(key model.EncryptionKeys, now time.Time) bool {
+	return key.IsActive || key.ExpiresAt.IsZero() || key.ExpiresAt.After(now)
+}
+
+// GenerateKey нь шинэ RSA түлхүүр үүсгэнэ.
+func GenerateKey() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, jwtKeyBits)
+}
+
+// WriteKeyFile нь хувийн түлхүүрийг keyDir/<kid>.pem файлд зөвхөн эзэмшигч унших эрхтэйгээр бичнэ.
+func WriteKeyFile(keyDir, kid string, key *rsa.PrivateKey) error {
+	if err := os.MkdirAll(keyDir, 0o700); err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	return os.WriteFile(keyFilePath(keyDir, kid), data, 0o600)
+}
+
+func ReadKeyFile(keyDir, kid string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(keyFilePath(keyDir, kid))
+	if err != nil {
+		return nil, fmt.Errorf("%s түлхүүрийн файл уншихад алдаа: %w", kid, err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s түлхүүр задлахад алдаа: %w", kid, err)
+	}
+	return key, nil
+}
+
+func keyFilePath(keyDir, kid string) string {
+	return filepath.Join(keyDir, kid+".pem")
+}
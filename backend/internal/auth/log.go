@@ -1,131 +1,377 @@
 package auth
 
-// import (
-// 	"encoding/json"
-// 	"errors"
-// 	"fmt"
-// 	"mindsteps/database"
-// 	"mindsteps/database/model"
-// 	"strings"
-// 	"time"
-
-// 	"gorm.io/gorm"
-// )
-
-// type UserLog struct {
-// 	model.UserLogs
-// }
-
-// type RouteName struct {
-// 	Path  string `json:"path"`
-// 	Name  string `json:"name"`
-// 	Table string `json:"table"`
-// }
-
-// var routes = []RouteName{
-// 	{Path: "/bin/type", Name: "Хогийн савны төрөл", Table: model.TableNameGarbageBinTypes},
-// 	{Path: "/bin/bin", Name: "Хогийн сав", Table: model.TableNameGarbageBins},
-// 	{Path: "/auth/login", Name: "Нэвтрэх", Table: model.TableNameUsers},
-// 	{Path: "/auth/change-password", Name: "Нууц үг солих", Table: model.TableNameUsers},
-// 	{Path: "/system/user", Name: "Систем хэрэглэгч", Table: model.TableNameUsers},
-// 	{Path: "/organization", Name: " Байгууллага", Table: model.TableNameOrganizations},
-// 	{Path: "/role", Name: "Эрхийн тохиргоо", Table: model.TableNameRoles},
-// 	{Path: "/vehicle/type", Name: "Тээврийн хэрэгслийн төрөл", Table: model.TableNameVehicleTypes},
-// 	{Path: "/vehicle", Name: "Тээврийн хэрэгсэл", Table: model.TableNameVehicles},
-// 	{Path: "/driver/schedule", Name: "Жолоочтийн цагиин хуваарь", Table: model.TableNameSchedule},
-// 	{Path: "/driver/shipment", Name: "Жолоочийн ачилт", Table: model.TableNameShipments},
-// 	{Path: "/garbage/landfill", Name: "Хог хаях цэг", Table: model.TableNameGarbageLandFill},
-
-// 	{Path: "/device", Name: "Төхөөрөмж", Table: model.TableNameDevices},
-// 	{Path: "/order", Name: "Захиалга", Table: model.TableNameOrders},
-// 	{Path: "/route", Name: "Чиглэл", Table: model.TableNameRoutes},
-// 	{Path: "/plan", Name: "Төлөвлөгөө", Table: model.TableNamePlans},
-// }
-
-// func GetPathName(path string) string {
-// 	for _, route := range routes {
-// 		if strings.Contains(path, route.Path) {
-// 			return route.Name
-// 		}
-// 	}
-// 	return ""
-// }
-
-// func GetPath(name string) string {
-// 	for _, route := range routes {
-// 		if strings.Contains(name, route.Name) {
-// 			return route.Path
-// 		}
-// 	}
-// 	return ""
-// }
-
-// func GetModules() ([]RouteName, error) {
-// 	return routes, nil
-// }
-
-// func CreateUserLog(data *model.UserLogs) error {
-// 	// Check for a similar log within the last minute
-// 	var existingLog model.UserLogs
-// 	tenSecondsAgo := time.Now().Add(-10 * time.Second)
-
-// 	err := database.DB.Where("user_id = ? AND path = ? AND operation_type = ? AND created_at >= ?",
-// 		data.UserID, data.Path, data.OperationType, tenSecondsAgo).
-// 		First(&existingLog).Error
-
-// 	// If a similar log exists, return without saving
-// 	if err == nil {
-// 		return nil
-// 	}
-
-// 	// If the error is not "record not found", return the error
-// 	if !errors.Is(err, gorm.ErrRecordNotFound) {
-// 		return err
-// 	}
-
-// 	// Create a new log if no duplicate found
-// 	if err := database.DB.Omit("User, Status").Create(data).Error; err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
-
-// func CalculateChanges(previousData map[string]interface{}, requestBody []byte) (map[string]interface{}, error) {
-// 	// Parse request body into a map
-// 	var requestData map[string]interface{}
-// 	err := json.Unmarshal(requestBody, &requestData)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("failed to parse request body: %v", err)
-// 	}
-
-// 	// Prepare a map to store changes
-// 	changes := make(map[string]interface{})
-
-// 	// Compare previousData and requestData
-// 	for key, oldValue := range previousData {
-// 		if newValue, exists := requestData[key]; exists {
-// 			// Normalize data for comparison
-// 			normalizedOld := fmt.Sprintf("%v", oldValue)
-// 			normalizedNew := fmt.Sprintf("%v", newValue)
-// 			// Only add to changes if values are different
-// 			if normalizedOld != normalizedNew {
-// 				changes[key] = map[string]interface{}{
-// 					"old": oldValue,
-// 					"new": newValue,
-// 				}
-// 			}
-// 		}
-// 	}
-
-// 	// Check for new fields in requestData not present in previousData
-// 	for key, newValue := range requestData {
-// 		if _, exists := previousData[key]; !exists {
-// 			changes[key] = map[string]interface{}{
-// 				"old": nil,
-// 				"new": newValue,
-// 			}
-// 		}
-// 	}
-
-// 	return changes, nil
-// }
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mindsteps/database/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/datatypes"
+)
+
+// system_audit_log.action_type-д AuditMiddleware-ийн бичих утгууд
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// Redacted нь audit log-д нууцлагдсан талбарын утгыг орлоно.
+const Redacted = "[REDACTED]"
+
+// auditBodyLimit нь entity-д хамаарахгүй хүсэлтийн body-г хадгалах дээд хэмжээ
+const auditBodyLimit = 16 * 1024
+
+// AuditEntity нь API-ийн замыг (api/v1-ээс хойш) хүснэгттэй холбоно.
+// Замын дараах тоо нь entity_id, Self үед entity_id нь нэвтэрсэн хэрэглэгч.
+// Redact нь энэ хүснэгтэд нэмж нууцлах талбарууд.
+type AuditEntity struct {
+	Prefix string
+	Type   string
+	Table  string
+	Self   bool
+	Redact []string
+}
+
+// auditEntities-ийг урт prefix эхэнд байхаар эрэмбэлнэ.
+var auditEntities = []AuditEntity{
+	{Prefix: "/users/me", Type: "users", Table: model.TableNameUsers, Self: true},
+	{Prefix: "/journals", Type: "journals", Table: model.TableNameJournals, Redact: []string{"title", "content", "content_encrypted"}},
+	{Prefix: "/mood-entries", Type: "mood_entries", Table: model.TableNameMoodEntries, Redact: []string{"notes", "trigger_event", "coping_strategy"}},
+	{Prefix: "/goals/milestones", Type: "goal_milestones", Table: model.TableNameGoalMilestones},
+	{Prefix: "/goals", Type: "goals", Table: model.TableNameGoals},
+	{Prefix: "/core-values", Type: "core_values", Table: model.TableNameCoreValues},
+	{Prefix: "/admin/lessons", Type: "lessons", Table: model.TableNameLessons},
+	{Prefix: "/admin/plutchik-combinations", Type: "plutchik_combinations", Table: model.TableNamePlutchikCombinations},
+	{Prefix: "/admin/roles", Type: "roles", Table: model.TableNameRoles},
+	{Prefix: "/admin/api-keys", Type: "api_keys", Table: model.TableNameAPIKeys},
+	{Prefix: "/admin/users", Type: "users", Table: model.TableNameUsers},
+}
+
+// redactedKeys нь бүх хүснэгт, body-д нууцлагдах талбарууд. password, secret, token
+// агуулсан нэр бүхий талбар мөн нууцлагдана.
+var redactedKeys = map[string]bool{
+	"code":           true,
+	"otp":            true,
+	"otp_code":       true,
+	"key":            true,
+	"key_hash":       true,
+	"recovery_codes": true,
+}
+
+func isRedactedKey(key string, extra []string) bool {
+	key = strings.ToLower(key)
+	if redactedKeys[key] || strings.Contains(key, "password") || strings.Contains(key, "secret") || strings.Contains(key, "token") {
+		return true
+	}
+	for _, field := range extra {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveKey нь талбар (эсвэл query параметр) бүх газарт нууцлагдах эсэхийг буцаана.
+func IsSensitiveKey(key string) bool {
+	return isRedactedKey(key, nil)
+}
+
+// redact нь map болон түүн доторх map, массивын нууц талбаруудыг Redacted-аар солино.
+func redact(value interface{}, extra []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isRedactedKey(key, extra) && item != nil && item != "" {
+				out[key] = Redacted
+				continue
+			}
+			out[key] = redact(item, extra)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redact(item, extra)
+		}
+		return out
+	}
+	return value
+}
+
+// diffValues нь өөрчлөгдсөн талбаруудын хуучин, шинэ утгыг буцаана.
+func diffValues(oldRow, newRow map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldDiff := map[string]interface{}{}
+	newDiff := map[string]interface{}{}
+	for key, oldValue := range oldRow {
+		newValue, ok := newRow[key]
+		if !ok || !sameValue(oldValue, newValue) {
+			oldDiff[key] = oldValue
+			newDiff[key] = newValue
+		}
+	}
+	for key, newValue := range newRow {
+		if _, ok := oldRow[key]; !ok {
+			oldDiff[key] = nil
+			newDiff[key] = newValue
+		}
+	}
+	return oldDiff, newDiff
+}
+
+func sameValue(a, b interface{}) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && string(left) == string(right)
+}
+
+func findAuditEntity(path string) (*AuditEntity, string) {
+	path = strings.TrimPrefix(path, "/api/v1")
+	for i := range auditEntities {
+		entity := &auditEntities[i]
+		rest, ok := strings.CutPrefix(path, entity.Prefix)
+		if !ok || (entity.Self && rest != "") {
+			continue
+		}
+		if rest == "" || rest[0] == '/' {
+			segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+			return entity, segment
+		}
+	}
+	return nil, ""
+}
+
+func auditAction(method string) string {
+	switch method {
+	case fiber.MethodPost:
+		return AuditCreate
+	case fiber.MethodDelete:
+		return AuditDelete
+	}
+	return AuditUpdate
+}
+
+// auditEntityID нь замын id эсвэл Self үед token-ий хэрэглэгчийг буцаана.
+// Token-ийг энд зөвхөн id олохын тулд уншина, эрхийг route-ийн middleware шалгана.
+func auditEntityID(c *fiber.Ctx, entity *AuditEntity, segment string) uint {
+	if entity.Self {
+		tokenString, err := GetTokenFromHeader(c, "Authorization", "Bearer")
+		if err != nil {
+			return 0
+		}
+		claims, err := GetInfoFromToken(tokenString)
+		if err != nil {
+			return 0
+		}
+		return claims.UserID
+	}
+	id, err := strconv.ParseUint(segment, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+// responseEntityID нь үүсгэсэн бичлэгийн id-г хариунаас ({"id"} эсвэл {"data": {"id"}}) хайна.
+func responseEntityID(body []byte) uint {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return 0
+	}
+	if id, ok := payload["id"].(float64); ok && id > 0 {
+		return uint(id)
+	}
+	for _, value := range payload {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if id, ok := nested["id"].(float64); ok && id > 0 {
+				return uint(id)
+			}
+		}
+	}
+	return 0
+}
+
+// requestValues нь JSON эсвэл form body-г map болгоно. Файлын оронд нэрийг нь авна.
+func requestValues(c *fiber.Ctx) map[string]interface{} {
+	values := map[string]interface{}{}
+	contentType := c.Get(fiber.HeaderContentType)
+
+	switch {
+	case strings.Contains(contentType, fiber.MIMEMultipartForm):
+		form, err := c.MultipartForm()
+		if err != nil {
+			return values
+		}
+		for key, items := range form.Value {
+			if len(items) > 0 {
+				values[key] = items[0]
+			}
+		}
+		for key, files := range form.File {
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.Filename)
+			}
+			values[key] = names
+		}
+	case strings.Contains(contentType, fiber.MIMEApplicationForm):
+		c.Request().PostArgs().VisitAll(func(key, value []byte) {
+			values[string(key)] = string(value)
+		})
+	default:
+		body := c.Body()
+		if len(body) == 0 {
+			return values
+		}
+		if len(body) > auditBodyLimit || !utf8.Valid(body) {
+			values["body_truncated"] = true
+			return values
+		}
+		var parsed interface{}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			values["body_truncated"] = true
+			return values
+		}
+		if object, ok := parsed.(map[string]interface{}); ok {
+			return object
+		}
+		values["body"] = parsed
+	}
+	return values
+}
+
+// RedactedRequestBody нь хүсэлтийн body-г нууц талбаруудыг нь (замын entity-ийн талбарыг оруулаад)
+// нууцалсан JSON болгоно. Body хоосон бол хоосон мөр буцаана (error_logs.request_body).
+func RedactedRequestBody(c *fiber.Ctx) string {
+	values := requestValues(c)
+	if len(values) == 0 {
+		return ""
+	}
+	var extra []string
+	if entity, _ := findAuditEntity(c.Path()); entity != nil {
+		extra = entity.Redact
+	}
+	return string(marshalAudit(redact(values, extra)))
+}
+
+func marshalAudit(value interface{}) datatypes.JSON {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("audit утга JSON болгоход алдаа: %v", err)
+		return nil
+	}
+	return datatypes.JSON(data)
+}
+
+func snapshot(entity *AuditEntity, id uint) map[string]interface{} {
+	if entity == nil || id == 0 {
+		return nil
+	}
+	row, err := Audit.AuditSnapshot(entity.Table, id)
+	if err != nil {
+		return nil
+	}
+	return row
+}
+
+// hasCredentials нь хүсэлтэд API key эсвэл хүчинтэй (гарын үсэг, хугацаа) Bearer token байгаа эсэх.
+// Session-ийг шалгахгүй тул DB-д хандахгүй, бүрэн шалгалтыг route-ийн middleware хийнэ.
+func hasCredentials(c *fiber.Ctx) bool {
+	if c.Get(APIKeyHeader) != "" {
+		return true
+	}
+	tokenString, err := GetTokenFromHeader(c, "Authorization", "Bearer")
+	if err != nil {
+		return false
+	}
+	_, err = GetInfoFromToken(tokenString)
+	return err == nil
+}
+
+// AuditMiddleware нь амжилттай POST, PUT, PATCH, DELETE хүсэлт бүрийг system_audit_log-д бичнэ.
+// auditEntities-д бүртгэлтэй замд бичлэгийн өмнөх, дараах төлвийг DB-ээс авч зөвхөн өөрчлөгдсөн
+// талбаруудыг хадгална, бусад замд нууцалсан request body-г хадгална.
+// Audit бичиж чадаагүй ч хүсэлтийг буцаахгүй (impersonation-оос ялгаатай).
+func AuditMiddleware(c *fiber.Ctx) error {
+	if isReadMethod(c) || Audit == nil {
+		return c.Next()
+	}
+
+	method := c.Method()
+	path := c.Path()
+	entity, segment := findAuditEntity(path)
+	var entityID uint
+	var before map[string]interface{}
+	if entity != nil {
+		entityID = auditEntityID(c, entity, segment)
+		// Нэвтрээгүй хүсэлт бүрд DB-ээс уншихгүйн тулд зөвхөн credential-тай үед өмнөх төлвийг авна
+		if hasCredentials(c) {
+			before = snapshot(entity, entityID)
+		}
+	}
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+	if status := c.Response().StatusCode(); status >= fiber.StatusBadRequest {
+		return nil
+	}
+
+	entry := &model.SystemAuditLog{
+		ActionType: auditAction(method),
+		EntityType: fmt.Sprintf("%.50s", path),
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		CreatedAt:  time.Now(),
+	}
+	if claims := GetTokenInfo(c); claims != nil {
+		entry.UserID = claims.UserID
+		if claims.ImpersonatorID != 0 {
+			entry.UserID = claims.ImpersonatorID
+		}
+	}
+
+	var extra []string
+	var oldValue, newValue interface{}
+	if entity != nil {
+		extra = entity.Redact
+		entry.EntityType = entity.Type
+		if entityID == 0 && method == fiber.MethodPost {
+			entityID = responseEntityID(c.Response().Body())
+		}
+		entry.EntityID = entityID
+	}
+
+	// Дэд үйлдэл (жишээ нь /goals/:id/milestones) entity-г өөрчлөөгүй бол body-г хадгална
+	after := snapshot(entity, entityID)
+	switch {
+	case before != nil && after != nil:
+		oldValue, newValue = diffValues(before, after)
+		if len(newValue.(map[string]interface{})) == 0 {
+			oldValue, newValue = nil, requestValues(c)
+		}
+	case before != nil:
+		oldValue = before
+	case after != nil:
+		newValue = after
+	default:
+		newValue = requestValues(c)
+	}
+
+	entry.OldValue = marshalAudit(redact(oldValue, extra))
+	entry.NewValue = marshalAudit(redact(newValue, extra))
+	if err := Audit.CreateAuditLog(entry); err != nil {
+		log.Errorf("audit log бичихэд алдаа (%s %s): %v", method, path, err)
+	}
+	return nil
+}
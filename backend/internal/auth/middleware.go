@@ -4,7 +4,6 @@ import (
 	"errors"
 	"mindsteps/internal/shared"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
@@ -32,28 +31,95 @@ func GetInfoFromToken(token string) (*Token, error) {
 	return info, nil
 }
 
-func TokenMiddleware(c *fiber.Ctx) error {
+// authenticate нь Bearer token-ий гарын үсгийг шалгаад,
+// session хаагдсан эсвэл token цуцлагдсан эсэхийг Sessions-ээр тулгана.
+func authenticate(c *fiber.Ctx) (*Token, error) {
 	tokenString, err := GetTokenFromHeader(c, "Authorization", "Bearer")
 	if err != nil {
-		return shared.ResponseUnauthorized(c)
+		return nil, err
 	}
 	claims, err := GetInfoFromToken(tokenString)
 	if err != nil {
-		return shared.ResponseUnauthorized(c)
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("purpose token-оор нэвтрэх боломжгүй")
+	}
+
+	if Sessions != nil {
+		if err := Sessions.Validate(tokenString, claims); err != nil {
+			if !errors.Is(err, ErrSessionRevoked) {
+				log.Errorf("session шалгахад алдаа: %v", err)
+			}
+			return nil, err
+		}
 	}
 
-	//	fmt.Println(claims)
+	// Тусламжийн ажилтны хүсэлт бүрийг бүртгэж чадахгүй бол нэвтрүүлэхгүй
+	if claims.ImpersonatorID != 0 {
+		if err := recordImpersonation(c, claims); err != nil {
+			log.Errorf("impersonation бүртгэхэд алдаа: %v", err)
+			return nil, err
+		}
+	}
 
-	c.Locals("tokenInfo", claims)
-	return c.Next()
+	return claims, nil
 }
 
-func OtpMiddleware(c *fiber.Ctx) error {
-	tokenString, err := GetTokenFromHeader(c, "Authorization", "Bearer")
+// Имэйлээ баталгаажуулаагүй хэрэглэгчийн эрхийн горимууд (AUTH_UNVERIFIED_ACCESS)
+const (
+	// UnverifiedReadOnly нь зөвхөн GET/HEAD/OPTIONS хүсэлтийг зөвшөөрнө
+	UnverifiedReadOnly = "read_only"
+	UnverifiedFull     = "full"
+)
+
+var unverifiedAccess = UnverifiedReadOnly
+
+// allowUnverified нь read_only горимд баталгаажаагүй хэрэглэгчийн өөрчлөх хүсэлтийг хаана.
+// System token (user_id = 0) хамаарахгүй.
+func allowUnverified(c *fiber.Ctx, claims *Token) bool {
+	if claims.EmailVerified || claims.UserID == 0 || unverifiedAccess == UnverifiedFull {
+		return true
+	}
+	return isReadMethod(c)
+}
+
+func isReadMethod(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func responseUnverified(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"message":        "Имэйл хаягаа баталгаажуулсны дараа энэ үйлдлийг хийх боломжтой",
+		"email_verified": false,
+	})
+}
+
+func TokenMiddleware(c *fiber.Ctx) error {
+	claims, err := authenticate(c)
 	if err != nil {
 		return shared.ResponseUnauthorized(c)
 	}
-	claims, err := GetInfoFromToken(tokenString)
+	if !allowUnverified(c, claims) {
+		return responseUnverified(c)
+	}
+	if !allowImpersonation(c, claims) {
+		return responseImpersonationReadOnly(c)
+	}
+
+	c.Locals("tokenInfo", claims)
+	return c.Next()
+}
+
+// UnverifiedMiddleware нь TokenMiddleware-тэй адил боловч имэйлээ баталгаажуулаагүй
+// хэрэглэгчийг ч нэвтрүүлнэ. Гарах, email засах, нууц үг солих зэрэг бүртгэлээ
+// хамгаалах үйлдлүүдэд л хэрэглэнэ. Гарахаас бусад нь DenyImpersonation-тай хамт тавигдана.
+func UnverifiedMiddleware(c *fiber.Ctx) error {
+	claims, err := authenticate(c)
 	if err != nil {
 		return shared.ResponseUnauthorized(c)
 	}
@@ -62,58 +128,50 @@ func OtpMiddleware(c *fiber.Ctx) error {
 	return c.Next()
 }
 
-const (
-	Permission1 int64 = 1 << iota // view
-	Permission2 int64 = 1 << iota // create
-	Permission3 int64 = 1 << iota // update
-	Permission4 int64 = 1 << iota // delete
-)
+// OtpMiddleware нь зөвхөн хоёр шатат баталгаажуулалтаар нээгдсэн session-ийг нэвтрүүлнэ.
+func OtpMiddleware(c *fiber.Ctx) error {
+	claims, err := authenticate(c)
+	if err != nil {
+		return shared.ResponseUnauthorized(c)
+	}
+	if !claims.Otp {
+		return shared.ResponseForbidden(c)
+	}
+	if !allowUnverified(c, claims) {
+		return responseUnverified(c)
+	}
+	if !allowImpersonation(c, claims) {
+		return responseImpersonationReadOnly(c)
+	}
 
-var MethodMap = map[string]int64{
-	"GET":    1,
-	"POST":   2,
-	"PUT":    4,
-	"DELETE": 8,
+	c.Locals("tokenInfo", claims)
+	return c.Next()
 }
 
-func PermissionMiddleware(resourceCode string) fiber.Handler {
+// RequirePermission нь token-д "<resource>:<action>" эрх байгаа эсэхийг шалгана.
+// Эрхүүд нэвтрэх болон token шинэчлэх үед role_owners-оос ачаалагдана.
+// X-Api-Key header ирвэл Bearer token-ий оронд API key-ийн scope-оор шалгана.
+func RequirePermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		tokenString, err := GetTokenFromHeader(c, "Authorization", "Bearer")
-		if err != nil {
-			return shared.ResponseUnauthorized(c)
+		var tokenInfo *Token
+		var err error
+		if c.Get(APIKeyHeader) != "" {
+			tokenInfo, err = authenticateAPIKey(c)
+		} else {
+			tokenInfo, err = authenticate(c)
 		}
-
-		tokenInfo, err := GetInfoFromToken(tokenString)
 		if err != nil {
-			return shared.ResponseUnauthorized(c)
+			return respondAuthError(c, err)
 		}
 
-		roles := tokenInfo.Roles
-
-		if roles == nil {
+		if !HasPermission(tokenInfo.Permissions, permission) {
 			return shared.ResponseForbidden(c)
 		}
-
-		allowPermission := false
-		for _, r := range roles {
-			if string(r) == "*" {
-				allowPermission = true
-				break
-			}
-			stringValue := string(r)
-
-			scode := stringValue[:2]
-			rcode := stringValue[2:]
-
-			ascii := int64([]rune(rcode)[0])
-			allowPermission = (scode == resourceCode) && (ascii&MethodMap[c.Method()] == MethodMap[c.Method()])
-			if allowPermission {
-				break
-			}
+		if !allowUnverified(c, tokenInfo) {
+			return responseUnverified(c)
 		}
-
-		if !allowPermission {
-			return shared.ResponseForbidden(c)
+		if !allowImpersonation(c, tokenInfo) {
+			return responseImpersonationReadOnly(c)
 		}
 
 		c.Locals("tokenInfo", tokenInfo)
@@ -121,256 +179,13 @@ func PermissionMiddleware(resourceCode string) fiber.Handler {
 	}
 }
 
-func ensureUTF8(data []byte) []byte {
-	if !utf8.Valid(data) {
-		log.Warnf("Invalid UTF-8 data found: %v", data)
-		return []byte("{}")
+// HasPermission нь granted жагсаалтад шаардлагатай эрх эсвэл түүнийг хамарсан wildcard байгаа эсэхийг шалгана.
+func HasPermission(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, perm := range granted {
+		if perm == required || perm == resource+":*" || perm == "*:*" {
+			return true
+		}
 	}
-	return data
+	return false
 }
-
-// func FetchPreviousDataMiddleware(c *fiber.Ctx) error {
-// 	if c.Method() != fiber.MethodPut && c.Method() != fiber.MethodDelete {
-// 		return c.Next()
-// 	}
-// 	resourceID := c.Params("id")
-// 	if resourceID == "" {
-// 		return shared.ResponseBadRequest(c, "Resource ID is required")
-// 	}
-
-// 	path := c.Path()
-// 	tableName := ""
-// 	for _, route := range routes {
-// 		if strings.Contains(path, route.Path) {
-// 			tableName = route.Table
-// 			break
-// 		}
-// 	}
-
-// 	if tableName == "" {
-// 		return shared.ResponseBadRequest(c, "Сервер алдаа")
-// 	}
-
-// 	// Fetch previous data
-// 	var previousData map[string]interface{}
-// 	err := database.DB.Table(tableName).
-// 		Where("id = ?", resourceID).
-// 		Scan(&previousData).Error
-// 	if err != nil {
-// 		return shared.ResponseBadRequest(c, "Сервер алдаа")
-// 	}
-// 	fmt.Println("previous data", previousData)
-
-// 	// Attach previous data to context
-// 	c.Locals("previousData", previousData)
-
-// 	return c.Next()
-// }
-
-// func LoginLogMiddleware(c *fiber.Ctx) error {
-// 	// Оролтын зам нь зөвхөн LOGIN үед
-// 	if c.Method() != fiber.MethodPost || c.Path() != "/auth/login" {
-// 		return c.Next()
-// 	}
-
-// 	var err error
-
-// 	// ---- 1. Request body авах ----
-// 	contentType := c.Get("Content-Type")
-// 	var requestBody []byte
-
-// 	switch {
-// 	case strings.Contains(contentType, "multipart/form-data"):
-// 		formData := make(map[string]interface{})
-// 		multipartForm, err := c.MultipartForm()
-// 		if err == nil {
-// 			// Form values
-// 			for key, values := range multipartForm.Value {
-// 				if len(values) > 0 {
-// 					formData[key] = values[0]
-// 				}
-// 			}
-// 		} else {
-// 			// Fallback
-// 			c.Request().PostArgs().VisitAll(func(key, value []byte) {
-// 				formData[string(key)] = string(value)
-// 			})
-// 		}
-
-// 		requestBody, _ = json.Marshal(formData)
-
-// 	default:
-// 		// JSON эсвэл бусад raw body
-// 		requestBody = c.Body()
-// 	}
-
-// 	// ---- 2. Handler ажиллуулна ----
-// 	if err = c.Next(); err != nil {
-// 		return err
-// 	}
-
-// 	// ---- 3. Response авах ----
-// 	responseBody := c.Response().Body()
-// 	statusCode := strconv.Itoa(c.Response().StatusCode())
-
-// 	// ---- 4. UTF-8-safe ----
-// 	reqBody := ensureUTF8(requestBody)
-// 	respBody := ensureUTF8(responseBody)
-
-// 	// ---- 5. Лог structure ----
-// 	loginLog := models.LoginLog{
-// 		Path:        c.Path(),
-// 		Method:      "LOGIN",
-// 		StatusCode:  statusCode,
-// 		RequestBody: string(reqBody),
-// 		Response:    string(respBody),
-// 		CreatedAt:   time.Now(),
-// 	}
-
-// 	if err := CreateLoginLog(&loginLog); err != nil {
-// 		log.Errorf("Failed to save login log: %v", err)
-// 	}
-
-// 	return nil
-// }
-
-// func LogMiddleware(c *fiber.Ctx) error {
-// 	if c.Method() == fiber.MethodGet {
-// 		return c.Next()
-// 	}
-
-// 	var err error
-// 	if err = c.Next(); err != nil {
-// 		return err
-// 	}
-
-// 	tokenInfo := GetTokenInfo(c)
-// 	userID := tokenInfo.UserId
-
-// 	var requestBody []byte
-// 	contentType := c.Get("Content-Type")
-
-// 	switch {
-
-// 	case strings.Contains(contentType, "multipart/form-data"):
-// 		formData := make(map[string]interface{})
-
-// 		multipartForm, err := c.MultipartForm()
-// 		if err == nil {
-// 			for key, values := range multipartForm.Value {
-// 				if len(values) > 0 {
-// 					formData[key] = values[0]
-// 				}
-// 			}
-
-// 			for key, files := range multipartForm.File {
-// 				if len(files) > 0 {
-// 					var fileNames []string
-// 					for _, file := range files {
-// 						fileNames = append(fileNames, file.Filename)
-// 					}
-// 					formData[key] = fileNames
-// 				}
-// 			}
-// 		} else {
-
-// 			c.Request().PostArgs().VisitAll(func(key, value []byte) {
-// 				formData[string(key)] = string(value)
-// 			})
-
-// 		}
-
-// 		requestBody, err = json.Marshal(formData)
-// 		if err != nil {
-// 			log.Errorf("Failed to convert form data to JSON: %v", err)
-// 			requestBody = []byte("{}")
-// 		}
-
-// 	case strings.Contains(contentType, "application/json"):
-// 		requestBody = c.Body()
-
-// 	default:
-// 		requestBody = c.Body()
-// 	}
-
-// 	responseBody := c.Response().Body()
-// 	statusCode := strconv.Itoa(c.Response().StatusCode())
-// 	// routeName := GetPathName(c.Path())
-
-// 	var action string
-// 	var changes map[string]interface{}
-// 	switch c.Method() {
-// 	case fiber.MethodPost:
-// 		action = "Үүсгэх"
-
-// 	case fiber.MethodPut:
-
-// 		action = "Засах"
-
-// 		previousData, ok := c.Locals("previousData").(map[string]interface{})
-
-// 		if !ok || previousData == nil {
-// 			log.Warnf("Previous data is missing or invalid for path: %s", c.Path())
-// 		} else {
-// 			changes, err = CalculateChanges(previousData, requestBody)
-// 			if err != nil {
-// 				log.Errorf("Failed to calculate changes: %v", err)
-// 			}
-// 			if len(changes) == 0 {
-// 				return shared.ResponseBadRequest(c, "Өөрчлөлт байхгүй байна")
-// 			}
-// 		}
-
-// 		c.Locals("previousData", nil)
-
-// 	case fiber.MethodDelete:
-// 		action = "Устгах"
-// 		previousData, ok := c.Locals("previousData").(map[string]interface{})
-// 		if !ok || previousData == nil {
-// 			log.Warnf("Previous data is missing or invalid for path: %s", c.Path())
-// 			previousData = make(map[string]interface{})
-// 		}
-
-// 		requestBody, err = json.Marshal(previousData)
-// 		if err != nil {
-// 			log.Errorf("Failed to serialize previous data for DELETE request: %v", err)
-// 			requestBody = []byte("{}")
-// 		}
-// 	}
-
-// 	description := action
-// 	if routeName != "" {
-// 		description = routeName + " " + action
-// 	}
-
-// 	if changes == nil {
-// 		changes = make(map[string]interface{})
-// 	}
-
-// 	changesJSON, err := json.Marshal(changes)
-// 	if err != nil {
-// 		log.Errorf("Failed to serialize changes to JSON: %v", err)
-// 		changesJSON = []byte("{}")
-// 	}
-
-// 	reqBody := ensureUTF8(requestBody)
-
-// 	logEntry := model.UserLogs{
-// 		Path:          c.Path(),
-// 		OperationType: c.Method(),
-// 		UserID:        userID,
-// 		DataLog:       string(reqBody),
-// 		ResponseLog:   string(responseBody),
-// 		Changes:       string(changesJSON),
-// 		CreatedAt:     time.Now(),
-// 		StatusCode:    statusCode,
-// 		Description:   description,
-// 	}
-
-// 	if err := CreateUserLog(&logEntry); err != nil {
-// 		log.Errorf("Failed to create user log: %v", err)
-// 		return err
-// 	}
-
-// 	return nil
-// }
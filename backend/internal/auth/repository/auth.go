@@ -7,16 +7,46 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthRepository interface {
 	CreateOTP(otp *model.AuthOTP) error
 	FindValidOTP(email, otpCode, otpType string) (*model.AuthOTP, error)
 	MarkOTPAsUsed(id uint) error
+	ConsumeOTP(userID uint, otpCode, otpType string) (bool, error)
+	InvalidateOTPs(userID uint, otpType string) error
 	CreateSession(session *model.UserSessions) error
-	FindSessionByToken(tokenHash string) (*model.UserSessions, error)
-	RevokeSession(tokenHash string) error
+	FindSessionByID(id uint) (*model.UserSessions, error)
+	RotateSession(id uint, oldHash, newHash string, expiresAt time.Time) (bool, error)
+	RevokeSession(id uint, reason string) error
+	RevokeUserSessions(userID uint, reason string) ([]uint, error)
+	RevokeOtherSessions(userID, keepID uint, reason string) ([]uint, error)
+	ListActiveSessions(userID uint) ([]model.UserSessions, error)
+	TouchSession(id uint) error
+	CreateRevokedToken(revoked *model.RevokedTokens) error
+	IsTokenRevoked(tokenHash string) (bool, error)
+	KnownLoginOrigin(userID uint, deviceInfo, ipAddress string) (knownDevice bool, knownIP bool, err error)
+	CreateAccessLog(entry *model.UserDataAccessLog) error
+	CreateAuditLog(entry *model.SystemAuditLog) error
+	AuditSnapshot(table string, id uint) (map[string]interface{}, error)
+	CreateNotification(notification *model.Notifications) error
 	CleanupExpiredOTPs() error
+	FindIdentity(provider, subject string) (*model.UserIdentities, error)
+	CreateIdentity(identity *model.UserIdentities) error
+	TouchIdentity(id uint, email string) error
+	FindMFA(userID uint) (*model.UserMfa, error)
+	SaveMFA(mfa *model.UserMfa) error
+	EnableMFA(userID uint, step int64, recoveryHashes []string) error
+	DeleteMFA(userID uint) error
+	UseMFAStep(userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uint, hashes []string) error
+	UseRecoveryCode(userID uint, hash string) (bool, error)
+	CreateEmailChange(change *model.UserEmailChanges) error
+	FindPendingEmailChange(userID uint) (*model.UserEmailChanges, error)
+	FindEmailChange(id uint) (*model.UserEmailChanges, error)
+	ConfirmEmailChange(change *model.UserEmailChanges) error
+	CancelEmailChange(change *model.UserEmailChanges) (bool, error)
 }
 
 type authRepo struct {
@@ -57,34 +87,404 @@ func (r *authRepo) MarkOTPAsUsed(id uint) error {
 	}).Error
 }
 
+// ConsumeOTP нь хүчинтэй OTP-г ашигласан болгоно. Зэрэг ирсэн хоёр хүсэлтийн зөвхөн нэг нь true авна.
+func (r *authRepo) ConsumeOTP(userID uint, otpCode, otpType string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&model.AuthOTP{}).
+		Where("user_id = ? AND otp_code = ? AND otp_type = ? AND is_used = ? AND expired_at > ? AND deleted_at IS NULL",
+			userID, otpCode, otpType, false, now).
+		Updates(map[string]interface{}{
+			"is_used": true,
+			"used_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateOTPs нь хэрэглэгчийн тухайн төрлийн ашиглагдаагүй бүх OTP-г хугацаа дууссан болгоно.
+func (r *authRepo) InvalidateOTPs(userID uint, otpType string) error {
+	now := time.Now()
+	return r.db.Model(&model.AuthOTP{}).
+		Where("user_id = ? AND otp_type = ? AND is_used = ? AND expired_at > ?", userID, otpType, false, now).
+		Update("expired_at", now).Error
+}
+
 func (r *authRepo) CreateSession(session *model.UserSessions) error {
 	return r.db.Create(session).Error
 }
 
-func (r *authRepo) FindSessionByToken(tokenHash string) (*model.UserSessions, error) {
+func (r *authRepo) FindSessionByID(id uint) (*model.UserSessions, error) {
 	var session model.UserSessions
-	err := r.db.Where("token_hash = ? AND is_active = ? AND expires_at > ?",
-		tokenHash, true, time.Now()).First(&session).Error
-	if err != nil {
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
 		return nil, err
 	}
 	return &session, nil
 }
 
-func (r *authRepo) RevokeSession(tokenHash string) error {
+// RotateSession нь refresh token-ий hash-ийг зөвхөн хуучин hash таарч байвал солино.
+// Зэрэг ирсэн хоёр refresh хүсэлтийн зөвхөн нэг нь амжилттай болно.
+func (r *authRepo) RotateSession(id uint, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&model.UserSessions{}).
+		Where("id = ? AND token_hash = ? AND is_active = ?", id, oldHash, true).
+		Updates(map[string]interface{}{
+			"token_hash":    newHash,
+			"expires_at":    expiresAt,
+			"last_activity": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *authRepo) RevokeSession(id uint, reason string) error {
 	now := time.Now()
-	return r.db.Model(&model.UserSessions{}).Where("token_hash = ?", tokenHash).Updates(map[string]interface{}{
+	return r.db.Model(&model.UserSessions{}).Where("id = ?", id).Updates(map[string]interface{}{
 		"is_active":     false,
 		"revoked_at":    now,
-		"revoke_reason": "logout",
+		"revoke_reason": reason,
 	}).Error
 }
 
+// RevokeUserSessions нь хэрэглэгчийн бүх идэвхтэй session-г хааж,
+// cache-ээс цэвэрлэхэд зориулан тэдгээрийн ID-уудыг буцаана.
+func (r *authRepo) RevokeUserSessions(userID uint, reason string) ([]uint, error) {
+	return r.revokeSessions(userID, 0, reason)
+}
+
+// RevokeOtherSessions нь keepID-аас бусад идэвхтэй session-уудыг хаана.
+func (r *authRepo) RevokeOtherSessions(userID, keepID uint, reason string) ([]uint, error) {
+	return r.revokeSessions(userID, keepID, reason)
+}
+
+func (r *authRepo) revokeSessions(userID, keepID uint, reason string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserSessions{}).
+			Where("user_id = ? AND is_active = ? AND id <> ?", userID, true, keepID).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		return tx.Model(&model.UserSessions{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"is_active":     false,
+				"revoked_at":    now,
+				"revoke_reason": reason,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *authRepo) ListActiveSessions(userID uint) ([]model.UserSessions, error) {
+	var sessions []model.UserSessions
+	err := r.db.Where("user_id = ? AND is_active = ? AND expires_at > ?", userID, true, time.Now()).
+		Order("last_activity DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *authRepo) TouchSession(id uint) error {
+	return r.db.Model(&model.UserSessions{}).Where("id = ?", id).
+		UpdateColumn("last_activity", time.Now()).Error
+}
+
+func (r *authRepo) CreateRevokedToken(revoked *model.RevokedTokens) error {
+	return r.db.Create(revoked).Error
+}
+
+func (r *authRepo) IsTokenRevoked(tokenHash string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RevokedTokens{}).
+		Where("token_hash = ? AND expires_at > ?", tokenHash, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// KnownLoginOrigin нь хэрэглэгч өмнө нь энэ төхөөрөмж болон IP-ээс нэвтэрч байсан эсэхийг шалгана.
+func (r *authRepo) KnownLoginOrigin(userID uint, deviceInfo, ipAddress string) (bool, bool, error) {
+	var device, ip int64
+	if err := r.db.Model(&model.UserSessions{}).
+		Where("user_id = ? AND device_info = ?", userID, deviceInfo).
+		Limit(1).Count(&device).Error; err != nil {
+		return false, false, err
+	}
+	if err := r.db.Model(&model.UserSessions{}).
+		Where("user_id = ? AND ip_address = ?", userID, ipAddress).
+		Limit(1).Count(&ip).Error; err != nil {
+		return false, false, err
+	}
+	return device > 0, ip > 0, nil
+}
+
+// CreateAccessLog нь user_data_access_log-д бичнэ. Session-гүй үйлдэлд session_id-г хоосон үлдээнэ.
+func (r *authRepo) CreateAccessLog(entry *model.UserDataAccessLog) error {
+	if entry.SessionID == 0 {
+		return r.db.Omit("SessionID").Create(entry).Error
+	}
+	return r.db.Create(entry).Error
+}
+
+func (r *authRepo) CreateAuditLog(entry *model.SystemAuditLog) error {
+	omit := []string{"User"}
+	if entry.UserID == 0 {
+		omit = append(omit, "UserID")
+	}
+	if entry.IPAddress == "" {
+		omit = append(omit, "IPAddress")
+	}
+	if entry.EntityID == 0 {
+		omit = append(omit, "EntityID")
+	}
+	return r.db.Omit(omit...).Create(entry).Error
+}
+
+// AuditSnapshot нь audit log-д зориулж бичлэгийн одоогийн утгыг багана -> утга хэлбэрээр буцаана.
+// table нь auth.AuditMiddleware-ийн бүртгэлээс ирнэ.
+func (r *authRepo) AuditSnapshot(table string, id uint) (map[string]interface{}, error) {
+	row := map[string]interface{}{}
+	if err := r.db.Table(table).Where("id = ?", id).Take(&row).Error; err != nil {
+		return nil, err
+	}
+	for key, value := range row {
+		if data, ok := value.([]byte); ok {
+			row[key] = string(data)
+		}
+	}
+	return row, nil
+}
+
+// CreateNotification нь апп доторх мэдэгдэл үүсгэнэ. Уншаагүй, шууд илгээгдсэн төлөвтэй.
+func (r *authRepo) CreateNotification(notification *model.Notifications) error {
+	return r.db.Omit("User", "ReadAt", "ScheduledFor").Create(notification).Error
+}
+
 func (r *authRepo) CleanupExpiredOTPs() error {
 	return r.db.Where("expired_at < ? OR is_used = ?", time.Now().AddDate(0, 0, -1), true).
 		Delete(&model.AuthOTP{}).Error
 }
 
+func (r *authRepo) FindIdentity(provider, subject string) (*model.UserIdentities, error) {
+	var identity model.UserIdentities
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *authRepo) CreateIdentity(identity *model.UserIdentities) error {
+	return r.db.Create(identity).Error
+}
+
+// TouchIdentity нь сүүлд нэвтэрсэн цаг болон provider-ийн өгсөн email-ийг шинэчилнэ.
+func (r *authRepo) TouchIdentity(id uint, email string) error {
+	return r.db.Model(&model.UserIdentities{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": time.Now(),
+	}).Error
+}
+
+func (r *authRepo) FindMFA(userID uint) (*model.UserMfa, error) {
+	var mfa model.UserMfa
+	if err := r.db.Where("user_id = ?", userID).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// SaveMFA нь баталгаажаагүй (enabled_at хоосон) TOTP нууцыг үүсгэх эсвэл солино.
+func (r *authRepo) SaveMFA(mfa *model.UserMfa) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret_encrypted", "last_used_step", "updated_at"}),
+	}).Omit("EnabledAt").Create(mfa).Error
+}
+
+// EnableMFA нь TOTP-г идэвхжүүлж, сэргээх кодуудыг нэг transaction-д хадгална.
+func (r *authRepo) EnableMFA(userID uint, step int64, recoveryHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&model.UserMfa{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"enabled_at":     now,
+			"last_used_step": step,
+			"updated_at":     now,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, recoveryHashes)
+	})
+}
+
+func (r *authRepo) DeleteMFA(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCodes{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserMfa{}).Error
+	})
+}
+
+// UseMFAStep нь TOTP алхмыг зөвхөн өмнө ашиглагдаагүй бол тэмдэглэнэ (нэг кодыг хоёр удаа ашиглахаас сэргийлнэ).
+func (r *authRepo) UseMFAStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&model.UserMfa{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *authRepo) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, hashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, hashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCodes{}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	codes := make([]model.UserRecoveryCodes, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = model.UserRecoveryCodes{UserID: userID, CodeHash: hash, CreatedAt: now}
+	}
+	return tx.Omit("UsedAt").Create(&codes).Error
+}
+
+// UseRecoveryCode нь ашиглагдаагүй кодыг нэг удаа л ашигласан болгоно.
+func (r *authRepo) UseRecoveryCode(userID uint, hash string) (bool, error) {
+	result := r.db.Model(&model.UserRecoveryCodes{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateEmailChange нь хэрэглэгчийн өмнөх хүлээгдэж буй хүсэлтүүдийг цуцлаад шинийг үүсгэнэ.
+func (r *authRepo) CreateEmailChange(change *model.UserEmailChanges) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserEmailChanges{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", change.UserID).
+			Update("cancelled_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Omit("ConfirmedAt", "CancelledAt").Create(change).Error
+	})
+}
+
+// FindPendingEmailChange нь баталгаажаагүй, цуцлагдаагүй, хугацаа нь дуусаагүй сүүлийн хүсэлтийг буцаана.
+func (r *authRepo) FindPendingEmailChange(userID uint) (*model.UserEmailChanges, error) {
+	var change model.UserEmailChanges
+	err := r.db.Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL AND expired_at > ?", userID, time.Now()).
+		Order("id DESC").
+		First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *authRepo) FindEmailChange(id uint) (*model.UserEmailChanges, error) {
+	var change model.UserEmailChanges
+	if err := r.db.Where("id = ?", id).First(&change).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ConfirmEmailChange нь хүсэлтийг баталгаажуулж users.email-ийг нэг transaction-д солино.
+// Шинэ хаяг OTP-оор баталгаажсан тул is_email_verified-ийг true болгоно.
+func (r *authRepo) ConfirmEmailChange(change *model.UserEmailChanges) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.UserEmailChanges{}).
+			Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", change.ID).
+			Update("confirmed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		result = tx.Model(&model.Users{}).
+			Where("id = ? AND email = ?", change.UserID, change.OldEmail).
+			Updates(map[string]interface{}{
+				"email":             change.NewEmail,
+				"is_email_verified": true,
+				"email_verified_at": now,
+				"updated_at":        now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		change.ConfirmedAt = now
+		return nil
+	})
+}
+
+// CancelEmailChange нь хүсэлтийг нэг л удаа цуцална. Аль хэдийн баталгаажсан бол
+// users.email-ийг хуучин хаяг руу буцаана. Өмнө нь цуцлагдсан бол false буцаана.
+func (r *authRepo) CancelEmailChange(change *model.UserEmailChanges) (bool, error) {
+	cancelled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.UserEmailChanges{}).
+			Where("id = ? AND cancelled_at IS NULL", change.ID).
+			Update("cancelled_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		cancelled = true
+		change.CancelledAt = now
+
+		if change.ConfirmedAt.IsZero() {
+			return nil
+		}
+		// Холбоос хуучин хаяг руу ирсэн тул тэр хаягийг баталгаажсан гэж тооцно
+		return tx.Model(&model.Users{}).
+			Where("id = ? AND email = ?", change.UserID, change.NewEmail).
+			Updates(map[string]interface{}{
+				"email":             change.OldEmail,
+				"is_email_verified": true,
+				"email_verified_at": now,
+				"updated_at":        now,
+			}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return cancelled, nil
+}
+
 func HashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(hash[:])
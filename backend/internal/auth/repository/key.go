@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JWTKeyName нь encryption_keys хүснэгт дэх JWT гарын үсгийн түлхүүрүүдийн key_name
+const JWTKeyName = "jwt"
+
+type KeyRepository interface {
+	ListKeys(keyName string) ([]model.EncryptionKeys, error)
+	FindByVersion(keyName string, version int) (*model.EncryptionKeys, error)
+	LatestVersion(keyName string) (int, error)
+	Create(key *model.EncryptionKeys) error
+	Activate(keyName string, version int, retireAt time.Time) error
+	Retire(keyName string, version int, at time.Time) error
+}
+
+type keyRepo struct {
+	db *gorm.DB
+}
+
+func NewKeyRepository(db *gorm.DB) KeyRepository {
+	return &keyRepo{db: db}
+}
+
+func (r *keyRepo) ListKeys(keyName string) ([]model.EncryptionKeys, error) {
+	var keys []model.EncryptionKeys
+	err := r.db.Where("key_name = ?", keyName).Order("key_version").Find(&keys).Error
+	return keys, err
+}
+
+func (r *keyRepo) FindByVersion(keyName string, version int) (*model.EncryptionKeys, error) {
+	var key model.EncryptionKeys
+	if err := r.db.Where("key_name = ? AND key_version = ?", keyName, version).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *keyRepo) LatestVersion(keyName string) (int, error) {
+	var version int
+	err := r.db.Model(&model.EncryptionKeys{}).
+		Where("key_name = ?", keyName).
+		Select("COALESCE(MAX(key_version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// Create нь шинэ түлхүүрийг хадгална. Хугацаа тавиагүй бол rotated_at, expires_at хоосон үлдэнэ.
+func (r *keyRepo) Create(key *model.EncryptionKeys) error {
+	omit := []string{}
+	if key.RotatedAt.IsZero() {
+		omit = append(omit, "RotatedAt")
+	}
+	if key.ExpiresAt.IsZero() {
+		omit = append(omit, "ExpiresAt")
+	}
+	return r.db.Omit(omit...).Create(key).Error
+}
+
+// Activate нь тухайн хувилбарыг гарын үсэг зурах түлхүүр болгож, өмнөх идэвхтэйг нь
+// retireAt хүртэл зөвхөн шалгалтад ашиглагдахаар үлдээнэ.
+func (r *keyRepo) Activate(keyName string, version int, retireAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&model.EncryptionKeys{}).
+			Where("key_name = ? AND is_active = ? AND key_version <> ?", keyName, true, version).
+			Updates(map[string]interface{}{
+				"is_active":  false,
+				"rotated_at": now,
+				"expires_at": retireAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.EncryptionKeys{}).
+			Where("key_name = ? AND key_version = ?", keyName, version).
+			Updates(map[string]interface{}{
+				"is_active":  true,
+				"expires_at": gorm.Expr("NULL"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Retire нь түлхүүрийг at хугацаанаас хойш шалгалтад ч ашиглахгүй болгоно.
+func (r *keyRepo) Retire(keyName string, version int, at time.Time) error {
+	result := r.db.Model(&model.EncryptionKeys{}).
+		Where("key_name = ? AND key_version = ? AND is_active = ?", keyName, version, false).
+		Update("expires_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
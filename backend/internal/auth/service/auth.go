@@ -2,62 +2,180 @@ package service
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"math/big"
 	"mindsteps/database/model"
 	"mindsteps/internal/auth"
 	authForm "mindsteps/internal/auth/form"
 	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/oidc"
+	"mindsteps/internal/password"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/rbac"
+	rbacRepo "mindsteps/internal/rbac/repository"
 	userRepo "mindsteps/internal/user/repository"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/gofiber/fiber/v2/log"
+
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
-	Register(form *authForm.RegisterForm) (string, *model.Users, error)
-	Login(form *authForm.LoginForm) (string, *model.Users, error)
+	Register(form *authForm.RegisterForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error)
+	Login(form *authForm.LoginForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error)
+	Refresh(form *authForm.RefreshTokenForm) (*auth.TokenPair, error)
 	Logout(token string) error
-	ForgotPassword(form *authForm.ForgotPasswordForm) error
-	ResetPassword(form *authForm.ResetPasswordForm) error
-	VerifyOTP(form *authForm.VerifyOTPForm) error
+	ForgotPassword(form *authForm.ForgotPasswordForm, client auth.ClientInfo) error
+	ResetPassword(form *authForm.ResetPasswordForm, client auth.ClientInfo) error
+	VerifyOTP(form *authForm.VerifyOTPForm, client auth.ClientInfo) error
+	ResendOTP(form *authForm.ResendOTPForm, client auth.ClientInfo) error
+	RevokeSessionByLink(form *authForm.NotMeForm, client auth.ClientInfo) error
+	LoginWithIdentity(identity *oidc.Identity, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error)
+	SendMagicLink(form *authForm.MagicLinkForm, client auth.ClientInfo) error
+	LoginWithMagicLink(form *authForm.MagicLinkLoginForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error)
+	VerifyMFA(form *authForm.MFAVerifyForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error)
+	SetupMFA(userID uint) (*MFASetup, error)
+	EnableMFA(userID, sessionID uint, form *authForm.MFACodeForm) ([]string, error)
+	DisableMFA(userID uint, form *authForm.MFACodeForm) error
+	RegenerateRecoveryCodes(userID uint, form *authForm.MFACodeForm) ([]string, error)
+	VerifySecondFactor(userID uint, code string) error
+	RequestEmailChange(userID uint, form *authForm.EmailChangeForm, client auth.ClientInfo) error
+	ConfirmEmailChange(userID, sessionID uint, form *authForm.EmailChangeConfirmForm) (*model.Users, error)
+	CancelEmailChange(form *authForm.EmailChangeCancelForm, client auth.ClientInfo) error
+	Impersonate(actor *auth.Token, userID uint, form *authForm.ImpersonateForm, client auth.ClientInfo) (*Impersonation, error)
 }
 
+// otpTTLMinutes нь имэйлээр илгээх OTP кодын хүчинтэй хугацаа
+const otpTTLMinutes = 10
+
+// OTP таах болон дахин илгээх хязгаарууд
+const (
+	otpMaxFailedAttempts   = 5
+	otpIPMaxFailedAttempts = 20
+	otpAttemptWindow       = 15 * time.Minute
+	otpResendCooldown      = time.Minute
+	otpMaxSendsPerHour     = 5
+	otpIPMaxSendsPerHour   = 10
+)
+
+var errInvalidOTP = errors.New("OTP буруу эсвэл хүчинтэй хугацаа дууссан байна")
+
 type authService struct {
 	userRepo userRepo.UserRepository
 	authRepo authRepo.AuthRepository
+	roleRepo rbacRepo.RoleRepository
+	mailer   mailer.Mailer
+	limiter  ratelimit.Store
 }
 
-func NewAuthService(userRepo userRepo.UserRepository, authRepo authRepo.AuthRepository) AuthService {
+func NewAuthService(userRepo userRepo.UserRepository, authRepo authRepo.AuthRepository, roleRepo rbacRepo.RoleRepository, mailer mailer.Mailer, limiter ratelimit.Store) AuthService {
 	return &authService{
 		userRepo: userRepo,
 		authRepo: authRepo,
+		roleRepo: roleRepo,
+		mailer:   mailer,
+		limiter:  limiter,
 	}
 }
 
-func (s *authService) Register(f *authForm.RegisterForm) (string, *model.Users, error) {
+func (s *authService) Register(f *authForm.RegisterForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error) {
 	if err := f.Validate(); err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
 	// Check if email exists
 	existing, _ := s.userRepo.FindByEmail(f.Email)
 	if existing != nil {
-		return "", nil, fmt.Errorf("email аль хэдийн бүртгэлтэй байна")
+		return nil, nil, fmt.Errorf("email аль хэдийн бүртгэлтэй байна")
+	}
+	if err := password.Validate(f.Password, f.Email); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.newUser(f.Name, f.Email, f.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Generate email verification OTP
+	// Имэйл илгээгдээгүй ч бүртгэл амжилттай, хэрэглэгч дахин код авах боломжтой
+	if err := s.sendOTP(user, "email_verification", mailer.TemplateEmailVerification); err != nil {
+		log.Errorf("verification email илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+
+	pair, _, err := s.startSession(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair, user, nil
+}
+
+// dummyPasswordHash нь бүртгэлгүй email-ээр нэвтрэх үед харьцуулах, хэнд ч хамааралгүй hash.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("mindsteps-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+func (s *authService) Login(f *authForm.LoginForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error) {
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(f.Password), bcrypt.DefaultCost)
+	user, err := s.userRepo.FindByEmail(f.Email)
 	if err != nil {
-		return "", nil, err
+		user = nil
+	}
+
+	if err := s.checkLoginLock(f.Email, client); err != nil {
+		s.logLogin(user, 0, client, loginLocked)
+		return nil, nil, err
+	}
+
+	if user == nil {
+		// Бүртгэлтэй email-ийг хариу өгөх хугацаагаар ялгахгүйн тул bcrypt-ийг адил ажиллуулна
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(f.Password))
+		return nil, nil, s.loginFailed(nil, f.Email, client, loginUnknownEmail)
+	}
+
+	// Check password
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(f.Password)); err != nil {
+		return nil, nil, s.loginFailed(user, f.Email, client, loginInvalidPassword)
+	}
+
+	if !user.IsActive {
+		s.logLogin(user, 0, client, loginInactive)
+		return nil, nil, fmt.Errorf("таны эрхийг хаасан байна")
+	}
+
+	s.clearLoginFailures(f.Email)
+
+	pair, err := s.completeLogin(user, client, loginSuccess)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair, user, nil
+}
+
+// newUser нь Register болон OIDC-ээр анх нэвтрэх үед ижил анхны утгатай хэрэглэгч үүсгэнэ.
+func (s *authService) newUser(name, email, plain string) (*model.Users, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create user
 	user := &model.Users{
 		UUID:      uuid.New().String(),
-		Name:      f.Name,
-		Email:     f.Email,
+		Name:      name,
+		Email:     email,
 		Password:  string(hashedPassword),
 		Language:  "mn",
 		Timezone:  "Asia/Ulaanbaatar",
@@ -67,141 +185,185 @@ func (s *authService) Register(f *authForm.RegisterForm) (string, *model.Users,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
-		return "", nil, err
+		return nil, err
 	}
+	return user, nil
+}
 
-	// Generate email verification OTP
-	otpCode := generateOTP()
-	otp := &model.AuthOTP{
-		UserID:    user.ID,
-		OtpCode:   otpCode,
-		OtpType:   "email_verification",
-		ExpiredAt: time.Now().Add(10 * time.Minute),
-		CreatedAt: time.Now(),
+// completeLogin нь хоёр шатат баталгаажуулалт идэвхтэй бол session-ий оронд
+// MFARequiredError буцааж, үгүй бол шууд session нээнэ.
+func (s *authService) completeLogin(user *model.Users, client auth.ClientInfo, reason string) (*auth.TokenPair, error) {
+	mfa, err := s.enabledMFA(user.ID)
+	if err != nil {
+		return nil, err
 	}
-	s.authRepo.CreateOTP(otp)
-
-	// TODO: Send email with OTP
+	if mfa != nil {
+		return nil, s.mfaChallenge(user, reason)
+	}
+	return s.finishLogin(user, client, reason)
+}
 
-	// Generate token
-	claims := auth.Token{
-		UserID:    user.ID,
-		UserEmail: user.Email,
+// finishLogin нь баталгаажсан хэрэглэгчид session нээж, нэвтрэлтийг бүртгэн,
+// шинэ төхөөрөмж/IP бол мэдэгдэл илгээнэ.
+func (s *authService) finishLogin(user *model.Users, client auth.ClientInfo, reason string) (*auth.TokenPair, error) {
+	knownDevice, knownIP, err := s.authRepo.KnownLoginOrigin(user.ID, client.DeviceInfo, client.IPAddress)
+	if err != nil {
+		log.Errorf("нэвтрэлтийн түүх шалгахад алдаа (user %d): %v", user.ID, err)
+		knownDevice, knownIP = true, true
 	}
 
-	token, err := auth.Gjwt.GenerateToken(&claims, time.Duration(144000*3))
+	// Update last login
+	s.userRepo.UpdateLastLogin(user.ID)
+	s.userRepo.IncrementLoginCount(user.ID)
 
-	// Create session
-	tokenHash := authRepo.HashToken(token)
-	session := &model.UserSessions{
-		UserID:       user.ID,
-		TokenHash:    tokenHash,
-		IsActive:     true,
-		LastActivity: time.Now(),
-		ExpiresAt:    time.Now().Add(24 * 7 * time.Hour),
-		CreatedAt:    time.Now(),
+	pair, session, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	s.logLogin(user, session.ID, client, reason)
+
+	if !knownDevice || !knownIP {
+		if err := s.notifyNewLogin(user, session); err != nil {
+			log.Errorf("шинэ нэвтрэлтийн мэдэгдэл илгээхэд алдаа (user %d): %v", user.ID, err)
+		}
 	}
-	s.authRepo.CreateSession(session)
 
-	return token, user, nil
+	return pair, nil
 }
 
-func (s *authService) Login(f *authForm.LoginForm) (string, *model.Users, error) {
+// Refresh нь refresh token-ийг шинээр сольж, шинэ access token олгоно.
+// Аль хэдийн солигдсон хуучин refresh token дахин ирвэл session-г бүхэлд нь хаана.
+func (s *authService) Refresh(f *authForm.RefreshTokenForm) (*auth.TokenPair, error) {
 	if err := f.Validate(); err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	user, err := s.userRepo.FindByEmail(f.Email)
+	sessionID, secret, err := auth.ParseRefreshToken(f.RefreshToken)
 	if err != nil {
-		return "", nil, fmt.Errorf("email эсвэл password буруу байна")
+		return nil, err
 	}
 
-	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(f.Password)); err != nil {
-		return "", nil, fmt.Errorf("email эсвэл password буруу байна")
+	session, err := s.authRepo.FindSessionByID(sessionID)
+	if err != nil {
+		return nil, auth.ErrInvalidRefreshToken
 	}
 
-	if !user.IsActive {
-		return "", nil, fmt.Errorf("таны эрхийг хаасан байна")
+	oldHash := authRepo.HashToken(secret)
+	if subtle.ConstantTimeCompare([]byte(session.TokenHash), []byte(oldHash)) != 1 {
+		reused, err := s.authRepo.IsTokenRevoked(oldHash)
+		if err != nil {
+			return nil, err
+		}
+		if reused && session.IsActive {
+			if err := s.authRepo.RevokeSession(session.ID, "refresh_reuse"); err != nil {
+				return nil, err
+			}
+			auth.InvalidateSessions(session.ID)
+		}
+		return nil, auth.ErrInvalidRefreshToken
 	}
 
-	// Update last login
-	s.userRepo.UpdateLastLogin(user.ID)
-	s.userRepo.IncrementLoginCount(user.ID)
+	if !session.IsActive || !session.ExpiresAt.After(time.Now()) {
+		return nil, auth.ErrInvalidRefreshToken
+	}
 
-	//TODO ажиллагаа хянах
-	// Generate token
-	claims := auth.Token{
-		UserID:    user.ID,
-		UserEmail: user.Email,
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil || !user.IsActive {
+		return nil, auth.ErrInvalidRefreshToken
 	}
 
-	token, err := auth.Gjwt.GenerateToken(&claims, time.Duration(144000*3))
+	newSecret, err := auth.GenerateRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
 
+	rotated, err := s.authRepo.RotateSession(session.ID, oldHash, authRepo.HashToken(newSecret), time.Now().Add(auth.RefreshTokenTTL))
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+	if !rotated {
+		// Өөр хүсэлт энэ token-ийг түрүүлж сольсон
+		return nil, auth.ErrInvalidRefreshToken
 	}
 
-	// Create session
-	tokenHash := authRepo.HashToken(token)
-	session := &model.UserSessions{
-		UserID:       user.ID,
-		TokenHash:    tokenHash,
-		IsActive:     true,
-		LastActivity: time.Now(),
-		ExpiresAt:    time.Now().Add(24 * 7 * time.Hour),
-		CreatedAt:    time.Now(),
+	// Хуучин refresh token-ийг дахин ашиглагдвал танихын тулд бүртгэнэ
+	if err := s.authRepo.CreateRevokedToken(&model.RevokedTokens{
+		TokenHash: oldHash,
+		UserID:    session.UserID,
+		RevokedAt: time.Now(),
+		ExpiresAt: session.ExpiresAt,
+		Reason:    "refresh_rotated",
+	}); err != nil {
+		return nil, err
 	}
-	s.authRepo.CreateSession(session)
+	auth.InvalidateTokens(oldHash)
 
-	return token, user, nil
+	return s.issueTokens(user, session.ID, newSecret)
 }
 
 func (s *authService) Logout(token string) error {
-	tokenHash := authRepo.HashToken(token)
-	return s.authRepo.RevokeSession(tokenHash)
+	claims, err := auth.Gjwt.ReadToken(token)
+	if err != nil {
+		return err
+	}
+
+	if claims.SessionID != 0 {
+		if err := s.authRepo.RevokeSession(claims.SessionID, "logout"); err != nil {
+			return err
+		}
+		auth.InvalidateSessions(claims.SessionID)
+	}
+
+	// JWT дуусах хүртэл revoked_tokens-д бүртгэж, access token-ийг шууд хүчингүй болгоно
+	if claims.ExpiresAt != nil {
+		revoked := &model.RevokedTokens{
+			TokenHash: authRepo.HashToken(token),
+			UserID:    claims.UserID,
+			RevokedAt: time.Now(),
+			ExpiresAt: claims.ExpiresAt.Time,
+			Reason:    "logout",
+		}
+		if err := s.authRepo.CreateRevokedToken(revoked); err != nil {
+			return err
+		}
+		auth.InvalidateTokens(revoked.TokenHash)
+	}
+
+	return nil
 }
 
-func (s *authService) ForgotPassword(f *authForm.ForgotPasswordForm) error {
+func (s *authService) ForgotPassword(f *authForm.ForgotPasswordForm, client auth.ClientInfo) error {
 	if err := f.Validate(); err != nil {
 		return err
 	}
 
+	if err := s.throttleSend(f.Email, "password_reset", client); err != nil {
+		return err
+	}
+
 	user, err := s.userRepo.FindByEmail(f.Email)
 	if err != nil {
 		// Don't reveal if email exists or not for security
 		return nil
 	}
 
-	// Generate OTP
-	otpCode := generateOTP()
-	otp := &model.AuthOTP{
-		UserID:    user.ID,
-		OtpType:   otpCode,
-		OtpCode:   "password_reset",
-		ExpiredAt: time.Now().Add(10 * time.Minute),
-		CreatedAt: time.Now(),
-	}
-
-	if err := s.authRepo.CreateOTP(otp); err != nil {
-		return err
+	// Илгээж чадаагүй ч email бүртгэлтэй эсэхийг ил гаргахгүйн тулд алдааг зөвхөн логлоно
+	if err := s.sendOTP(user, "password_reset", mailer.TemplatePasswordReset); err != nil {
+		log.Errorf("password reset email илгээхэд алдаа (user %d): %v", user.ID, err)
 	}
 
-	// TODO: Send email with OTP
-	fmt.Printf("Password reset OTP for %s: %s\n", f.Email, otpCode)
-
 	return nil
 }
 
-func (s *authService) ResetPassword(f *authForm.ResetPasswordForm) error {
+func (s *authService) ResetPassword(f *authForm.ResetPasswordForm, client auth.ClientInfo) error {
 	if err := f.Validate(); err != nil {
 		return err
 	}
 
 	// Verify OTP
-	otp, err := s.authRepo.FindValidOTP(f.Email, f.OTPCode, "password_reset")
+	otp, err := s.checkOTP(f.Email, f.OTPCode, "password_reset", client)
 	if err != nil {
-		return fmt.Errorf("OTP буруу эсвэл хүчинтэй хугацаа дууссан байна")
+		return err
 	}
 
 	// Get user
@@ -210,31 +372,43 @@ func (s *authService) ResetPassword(f *authForm.ResetPasswordForm) error {
 		return err
 	}
 
-	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(f.NewPassword), bcrypt.DefaultCost)
+	// Бодлого болон сүүлийн нууц үгнүүдтэй тулгаж hash үүсгэнэ
+	policy := password.Get()
+	history, err := s.userRepo.PasswordHistory(user.ID, policy.HistorySize)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := policy.Hash(f.NewPassword, user.Email, append([]string{user.Password}, history...)...)
 	if err != nil {
 		return err
 	}
 
 	// Update password
-	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
 		return err
 	}
 
 	// Mark OTP as used
 	s.authRepo.MarkOTPAsUsed(otp.ID)
 
+	// Нууц үг солигдсон тул бүх төхөөрөмжөөс гаргана
+	sessionIDs, err := s.authRepo.RevokeUserSessions(user.ID, "password_reset")
+	if err != nil {
+		return err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
 	return nil
 }
 
-func (s *authService) VerifyOTP(f *authForm.VerifyOTPForm) error {
+func (s *authService) VerifyOTP(f *authForm.VerifyOTPForm, client auth.ClientInfo) error {
 	if err := f.Validate(); err != nil {
 		return err
 	}
 
-	otp, err := s.authRepo.FindValidOTP(f.Email, f.OTPCode, "email_verification")
+	otp, err := s.checkOTP(f.Email, f.OTPCode, "email_verification", client)
 	if err != nil {
-		return fmt.Errorf("OTP буруу эсвэл хүчинтэй хугацаа дууссан байна")
+		return err
 	}
 
 	// Mark email as verified
@@ -254,6 +428,208 @@ func (s *authService) VerifyOTP(f *authForm.VerifyOTPForm) error {
 	return nil
 }
 
+// ResendOTP нь өмнөх кодыг хүчингүй болгож шинэ OTP илгээнэ. Cooldown-оос өмнө дуудвал 429 буцна.
+func (s *authService) ResendOTP(f *authForm.ResendOTPForm, client auth.ClientInfo) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.throttleSend(f.Email, f.OTPType, client); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(f.Email)
+	if err != nil {
+		// Don't reveal if email exists or not for security
+		return nil
+	}
+
+	tpl := mailer.TemplatePasswordReset
+	if f.OTPType == "email_verification" {
+		// Бүртгэл байгаа эсэхийг илчлэхгүйн тулд баталгаажсан бол чимээгүй орхино
+		if user.IsEmailVerified {
+			return nil
+		}
+		tpl = mailer.TemplateEmailVerification
+	}
+
+	if err := s.sendOTP(user, f.OTPType, tpl); err != nil {
+		log.Errorf("OTP дахин илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// checkOTP нь OTP-г шалгахаас өмнө user болон IP-ийн буруу оролдлогын тоог хянана.
+// Хэрэглэгч otpMaxFailedAttempts удаа алдвал тухайн төрлийн бүх идэвхтэй код хүчингүй болно.
+func (s *authService) checkOTP(email, code, otpType string, client auth.ClientInfo) (*model.AuthOTP, error) {
+	userKey := fmt.Sprintf("otp-fail:user:%s:%s", strings.ToLower(email), otpType)
+	ipKey := "otp-fail:ip:" + client.IPAddress
+
+	if err := ratelimit.Exceeded(s.limiter, userKey, otpMaxFailedAttempts); err != nil {
+		return nil, err
+	}
+	if err := ratelimit.Exceeded(s.limiter, ipKey, otpIPMaxFailedAttempts); err != nil {
+		return nil, err
+	}
+
+	otp, err := s.authRepo.FindValidOTP(email, code, otpType)
+	if err != nil {
+		if _, _, err := s.limiter.Hit(ipKey, otpAttemptWindow); err != nil {
+			log.Errorf("OTP IP тоолуур нэмэхэд алдаа: %v", err)
+		}
+
+		count, ttl, hitErr := s.limiter.Hit(userKey, otpAttemptWindow)
+		if hitErr != nil {
+			log.Errorf("OTP user тоолуур нэмэхэд алдаа: %v", hitErr)
+			return nil, errInvalidOTP
+		}
+		if count >= otpMaxFailedAttempts {
+			if user, err := s.userRepo.FindByEmail(email); err == nil {
+				if err := s.authRepo.InvalidateOTPs(user.ID, otpType); err != nil {
+					log.Errorf("OTP хүчингүй болгоход алдаа (user %d): %v", user.ID, err)
+				}
+			}
+			return nil, ratelimit.NewLimitError(ttl)
+		}
+		return nil, errInvalidOTP
+	}
+
+	if err := s.limiter.Reset(userKey); err != nil {
+		log.Errorf("OTP user тоолуур цэвэрлэхэд алдаа: %v", err)
+	}
+
+	return otp, nil
+}
+
+// throttleSend нь OTP илгээх давтамжийг email болон IP-ээр хязгаарлана.
+// Email бүртгэлтэй эсэхээс үл хамааран ижил ажиллах тул хэрэглэгчийг тоолж илрүүлэх боломжгүй.
+func (s *authService) throttleSend(email, otpType string, client auth.ClientInfo) error {
+	email = strings.ToLower(email)
+	ipKey := "otp-send:ip:" + client.IPAddress
+	cooldownKey := fmt.Sprintf("otp-send:cooldown:%s:%s", email, otpType)
+	hourlyKey := "otp-send:user:" + email
+
+	if err := ratelimit.Exceeded(s.limiter, ipKey, otpIPMaxSendsPerHour); err != nil {
+		return err
+	}
+	if err := ratelimit.Exceeded(s.limiter, cooldownKey, 1); err != nil {
+		return err
+	}
+	if err := ratelimit.Exceeded(s.limiter, hourlyKey, otpMaxSendsPerHour); err != nil {
+		return err
+	}
+
+	for key, window := range map[string]time.Duration{
+		ipKey:       time.Hour,
+		cooldownKey: otpResendCooldown,
+		hourlyKey:   time.Hour,
+	} {
+		if _, _, err := s.limiter.Hit(key, window); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendOTP нь шинэ OTP үүсгэж хадгалаад, хэрэглэгчийн хэлээр имэйлээр илгээнэ.
+func (s *authService) sendOTP(user *model.Users, otpType string, tpl mailer.Template) error {
+	// Нэг төрлийн зөвхөн сүүлийн код хүчинтэй байна
+	if err := s.authRepo.InvalidateOTPs(user.ID, otpType); err != nil {
+		return err
+	}
+
+	otpCode := generateOTP()
+	otp := &model.AuthOTP{
+		UserID:    user.ID,
+		OtpCode:   otpCode,
+		OtpType:   otpType,
+		ExpiredAt: time.Now().Add(otpTTLMinutes * time.Minute),
+		CreatedAt: time.Now(),
+	}
+	if err := s.authRepo.CreateOTP(otp); err != nil {
+		return err
+	}
+
+	msg, err := mailer.Render(tpl, user.Language, user.Email, mailer.OTPData{
+		Name:           user.Name,
+		Code:           otpCode,
+		ExpiresMinutes: otpTTLMinutes,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(msg)
+}
+
+// startSession нь шинэ user_sessions мөр үүсгээд түүнд холбогдох token-уудыг олгоно.
+func (s *authService) startSession(user *model.Users, client auth.ClientInfo) (*auth.TokenPair, *model.UserSessions, error) {
+	secret, err := auth.GenerateRefreshSecret()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &model.UserSessions{
+		UserID:       user.ID,
+		TokenHash:    authRepo.HashToken(secret),
+		DeviceInfo:   client.DeviceInfo,
+		IPAddress:    client.IPAddress,
+		UserAgent:    client.UserAgent,
+		IsActive:     true,
+		LastActivity: now,
+		ExpiresAt:    now.Add(auth.RefreshTokenTTL),
+		CreatedAt:    now,
+	}
+	if err := s.authRepo.CreateSession(session); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.issueTokens(user, session.ID, secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, session, nil
+}
+
+// issueTokens нь хэрэглэгчийн идэвхтэй role-уудаас эрхийг ачаалж access token-д шингээнэ.
+// Тиймээс role-ийн өөрчлөлт дараагийн нэвтрэлт эсвэл refresh-ээс хэрэгжинэ.
+func (s *authService) issueTokens(user *model.Users, sessionID uint, refreshSecret string) (*auth.TokenPair, error) {
+	roles, err := s.roleRepo.FindActiveByOwner(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	codes, permissions, level, err := rbac.Resolve(roles)
+	if err != nil {
+		return nil, err
+	}
+	// MFA идэвхжсэн хэрэглэгчийн бүх session хоёр дахь шатаар нээгддэг
+	mfa, err := s.enabledMFA(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := auth.Token{
+		UserID:        user.ID,
+		UserEmail:     user.Email,
+		Roles:         codes,
+		Level:         level,
+		Permissions:   permissions,
+		SessionID:     sessionID,
+		Otp:           mfa != nil,
+		EmailVerified: user.IsEmailVerified,
+	}
+
+	token, err := auth.CreateUserSession(&claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewTokenPair(token, sessionID, refreshSecret), nil
+}
+
 func generateOTP() string {
 	const digits = "0123456789"
 	otp := make([]byte, 6)
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// emailChangeOTPType нь шинэ хаяг руу илгээх кодын auth_otp.otp_type
+	emailChangeOTPType = "email_change"
+	// emailChangeCancelHours нь хуучин хаяг руу илгээх цуцлах холбоосын хүчинтэй хугацаа.
+	// Солигдсоны дараа ч энэ хугацаанд хуучин хаягийг сэргээж болно.
+	emailChangeCancelHours = 72
+)
+
+var (
+	ErrEmailUnchanged = errors.New("шинэ email одоогийн email-ээс өөр байх ёстой")
+	ErrEmailTaken     = errors.New("email аль хэдийн бүртгэлтэй байна")
+)
+
+func emailChangeFailKey(userID uint) string {
+	return "otp-fail:user:" + shared.UintToString(userID) + ":" + emailChangeOTPType
+}
+
+// RequestEmailChange нь шинэ хаяг руу баталгаажуулах код, хуучин хаяг руу цуцлах
+// холбоостой мэдэгдэл илгээнэ. users.email-ийг ConfirmEmailChange хүртэл өөрчлөхгүй.
+func (s *authService) RequestEmailChange(userID uint, f *authForm.EmailChangeForm, client auth.ClientInfo) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(f.Password)); err != nil {
+		return errors.New("password буруу байна")
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(f.NewEmail))
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrEmailUnchanged
+	}
+	if existing, _ := s.userRepo.FindByEmail(newEmail); existing != nil {
+		return ErrEmailTaken
+	}
+
+	if err := s.throttleSend(newEmail, emailChangeOTPType, client); err != nil {
+		return err
+	}
+
+	change := &model.UserEmailChanges{
+		UserID:    user.ID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		ExpiredAt: time.Now().Add(otpTTLMinutes * time.Minute),
+		CreatedAt: time.Now(),
+	}
+	if err := s.authRepo.CreateEmailChange(change); err != nil {
+		return err
+	}
+
+	// Хуучин хаягийн эзэн мэдэгдэл авахгүй бол солих боломжгүй
+	if err := s.notifyEmailChange(user, change); err != nil {
+		return err
+	}
+
+	recipient := *user
+	recipient.Email = newEmail
+	return s.sendOTP(&recipient, emailChangeOTPType, mailer.TemplateEmailChange)
+}
+
+func (s *authService) notifyEmailChange(user *model.Users, change *model.UserEmailChanges) error {
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID: user.ID,
+		Nonce:  shared.UintToString(change.ID),
+	}, auth.PurposeEmailChangeCancel, emailChangeCancelHours*60)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailer.Render(mailer.TemplateEmailChangeNotice, user.Language, change.OldEmail, mailer.EmailChangeData{
+		Name:         user.Name,
+		NewEmail:     change.NewEmail,
+		CancelURL:    mailer.Link("/auth/email-change/cancel", url.Values{"token": {token}}),
+		ExpiresHours: emailChangeCancelHours,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(msg)
+}
+
+// ConfirmEmailChange нь шинэ хаяг руу ирсэн кодыг шалгаад email-ийг солино.
+// Одоогийн session-оос бусад бүх төхөөрөмжийг гаргана.
+func (s *authService) ConfirmEmailChange(userID, sessionID uint, f *authForm.EmailChangeConfirmForm) (*model.Users, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	failKey := emailChangeFailKey(userID)
+	if err := ratelimit.Exceeded(s.limiter, failKey, otpMaxFailedAttempts); err != nil {
+		return nil, err
+	}
+
+	change, err := s.authRepo.FindPendingEmailChange(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidOTP
+		}
+		return nil, err
+	}
+
+	consumed, err := s.authRepo.ConsumeOTP(userID, f.OTPCode, emailChangeOTPType)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		count, ttl, hitErr := s.limiter.Hit(failKey, otpAttemptWindow)
+		if hitErr != nil {
+			log.Errorf("email солих тоолуур нэмэхэд алдаа: %v", hitErr)
+			return nil, errInvalidOTP
+		}
+		if count >= otpMaxFailedAttempts {
+			if err := s.authRepo.InvalidateOTPs(userID, emailChangeOTPType); err != nil {
+				log.Errorf("OTP хүчингүй болгоход алдаа (user %d): %v", userID, err)
+			}
+			return nil, ratelimit.NewLimitError(ttl)
+		}
+		return nil, errInvalidOTP
+	}
+	if err := s.limiter.Reset(failKey); err != nil {
+		log.Errorf("email солих тоолуур цэвэрлэхэд алдаа: %v", err)
+	}
+
+	if err := s.authRepo.ConfirmEmailChange(change); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidOTP
+		}
+		return nil, err
+	}
+
+	sessionIDs, err := s.authRepo.RevokeOtherSessions(userID, sessionID, "email_change")
+	if err != nil {
+		return nil, err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	return s.userRepo.FindByID(userID)
+}
+
+// CancelEmailChange нь хуучин хаяг руу ирсэн холбоосоор хүсэлтийг цуцална.
+// Email аль хэдийн солигдсон бол хуучин хаягийг сэргээж, бүх төхөөрөмжөөс гаргана.
+func (s *authService) CancelEmailChange(f *authForm.EmailChangeCancelForm, client auth.ClientInfo) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	claims, err := auth.ReadPurposeToken(f.Token, auth.PurposeEmailChangeCancel)
+	if err != nil {
+		return err
+	}
+
+	change, err := s.authRepo.FindEmailChange(shared.StringToUint(claims.Nonce))
+	if err != nil || change.UserID != claims.UserID {
+		return auth.ErrInvalidLinkToken
+	}
+
+	cancelled, err := s.authRepo.CancelEmailChange(change)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return auth.ErrInvalidLinkToken
+	}
+
+	if change.ConfirmedAt.IsZero() {
+		return s.authRepo.InvalidateOTPs(change.UserID, emailChangeOTPType)
+	}
+
+	log.Warnf("email солилтыг хуучин хаягаас буцаалаа (user %d, ip %s)", change.UserID, client.IPAddress)
+	sessionIDs, err := s.authRepo.RevokeUserSessions(change.UserID, "email_change_reverted")
+	if err != nil {
+		return err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/rbac"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// impersonationDefaultMinutes нь хугацаа заагаагүй үеийн impersonation token-ий хугацаа
+const impersonationDefaultMinutes = 30
+
+var (
+	ErrImpersonationForbidden = errors.New("энэ хэрэглэгчийн нэрийн өмнөөс нэвтрэх эрхгүй")
+	ErrImpersonateSelf        = errors.New("өөрийнхөө нэрийн өмнөөс нэвтрэх боломжгүй")
+	ErrImpersonationTarget    = errors.New("хэрэглэгч олдсонгүй эсвэл идэвхгүй байна")
+)
+
+// Impersonation нь тусламжийн ажилтанд олгох token. Refresh token олгохгүй тул хугацаа дуусахад дахин хүснэ.
+type Impersonation struct {
+	AccessToken string `json:"token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	SessionID   uint   `json:"session_id"`
+	ReadOnly    bool   `json:"read_only"`
+}
+
+// Impersonate нь хэрэглэгчийн харж буйг шалгах богино хугацаат token олгоно.
+// Тусдаа session нээдэг тул хэрэглэгч төхөөрөмжийн жагсаалтаасаа харж, хааж болно.
+// Эхлэл болон хүсэлт бүр system_audit_log-д бичигдэж, хэрэглэгчид мэдэгдэл очно.
+func (s *authService) Impersonate(actor *auth.Token, userID uint, f *authForm.ImpersonateForm, client auth.ClientInfo) (*Impersonation, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if actor.UserID == 0 || actor.ImpersonatorID != 0 {
+		return nil, ErrImpersonationForbidden
+	}
+	if actor.UserID == userID {
+		return nil, ErrImpersonateSelf
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImpersonationTarget
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrImpersonationTarget
+	}
+
+	// Өөртэйгээ адил эсвэл өндөр level-тэй ажилтны нэрийн өмнөөс нэвтрэхгүй
+	roles, err := s.roleRepo.FindActiveByOwner(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	_, _, level, err := rbac.Resolve(roles)
+	if err != nil {
+		return nil, err
+	}
+	if level > 0 && level >= actor.Level {
+		return nil, ErrImpersonationForbidden
+	}
+
+	minutes := f.Minutes
+	if minutes == 0 {
+		minutes = impersonationDefaultMinutes
+	}
+	reason := strings.TrimSpace(f.Reason)
+
+	// Refresh secret-ийг хэнд ч өгөхгүй тул энэ session-ийг сунгах боломжгүй
+	secret, err := auth.GenerateRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &model.UserSessions{
+		UserID:       user.ID,
+		TokenHash:    authRepo.HashToken(secret),
+		DeviceInfo:   "MindSteps тусламж (ажилтан #" + shared.UintToString(actor.UserID) + ")",
+		IPAddress:    client.IPAddress,
+		UserAgent:    client.UserAgent,
+		IsActive:     true,
+		LastActivity: now,
+		ExpiresAt:    now.Add(time.Duration(minutes) * time.Minute),
+		CreatedAt:    now,
+	}
+	if err := s.authRepo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"reason":     reason,
+		"minutes":    minutes,
+		"write":      f.AllowWrite,
+		"session_id": session.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authRepo.CreateAuditLog(&model.SystemAuditLog{
+		UserID:     actor.UserID,
+		ActionType: auth.AuditImpersonationStart,
+		EntityType: "users",
+		EntityID:   user.ID,
+		NewValue:   datatypes.JSON(details),
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifySupportAccess(user, session, reason, minutes, !f.AllowWrite); err != nil {
+		return nil, err
+	}
+
+	token, err := auth.Gjwt.GenerateToken(&auth.Token{
+		UserID:             user.ID,
+		UserEmail:          user.Email,
+		SessionID:          session.ID,
+		EmailVerified:      user.IsEmailVerified,
+		ImpersonatorID:     actor.UserID,
+		ImpersonationWrite: f.AllowWrite,
+	}, time.Duration(minutes))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Warnf("ажилтан %d хэрэглэгч %d-ийн нэрийн өмнөөс нэвтэрлээ (session %d, write=%t)", actor.UserID, user.ID, session.ID, f.AllowWrite)
+
+	return &Impersonation{
+		AccessToken: token,
+		ExpiresIn:   int64(minutes * 60),
+		SessionID:   session.ID,
+		ReadOnly:    !f.AllowWrite,
+	}, nil
+}
+
+// notifySupportAccess нь апп доторх мэдэгдэл үүсгэж, "хандалтыг зогсоох" холбоостой имэйл илгээнэ.
+// Мэдэгдэл хадгалагдахгүй бол token олгохгүй. Имэйлийн алдааг зөвхөн логлоно.
+func (s *authService) notifySupportAccess(user *model.Users, session *model.UserSessions, reason string, minutes int, readOnly bool) error {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"session_id": session.ID,
+		"read_only":  readOnly,
+		"reason":     reason,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.authRepo.CreateNotification(&model.Notifications{
+		UserID:           user.ID,
+		NotificationType: "support_access",
+		Title:            "Тусламжийн ажилтан таны бүртгэлд нэвтэрлээ",
+		Message:          "Шалтгаан: " + reason,
+		ActionURL:        "/settings/sessions",
+		ActionLabel:      "Төхөөрөмжүүд",
+		SentAt:           session.CreatedAt,
+		Metadata:         datatypes.JSON(metadata),
+		Priority:         "high",
+		CreatedAt:        session.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID:    user.ID,
+		SessionID: session.ID,
+	}, auth.PurposeRevokeSession, revokeLinkTTLMinutes)
+	if err != nil {
+		return err
+	}
+
+	accessAt := session.CreatedAt
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		accessAt = accessAt.In(loc)
+	}
+
+	msg, err := mailer.Render(mailer.TemplateSupportAccess, user.Language, user.Email, mailer.SupportAccessData{
+		Name:           user.Name,
+		Reason:         reason,
+		ReadOnly:       readOnly,
+		AccessAt:       accessAt.Format("2006-01-02 15:04 MST"),
+		ExpiresMinutes: minutes,
+		RevokeURL:      mailer.Link("/auth/not-me", url.Values{"token": {token}}),
+	})
+	if err == nil {
+		err = s.mailer.Send(msg)
+	}
+	if err != nil {
+		log.Errorf("тусламжийн хандалтын мэдэгдэл илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+	return nil
+}
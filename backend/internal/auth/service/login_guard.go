@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/ratelimit"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Нэвтрэх оролдлогын хязгаарууд. Бүртгэл бүр loginLockThreshold удаа алдсаны дараа
+// алдаа тутамд түгжих хугацаа 1, 2, 4 ... минут болж loginMaxLock хүртэл өснө.
+const (
+	loginLockThreshold   = 5
+	loginFailureWindow   = 24 * time.Hour
+	loginMaxLock         = time.Hour
+	loginIPMaxFailures   = 30
+	loginIPFailureWindow = time.Hour
+)
+
+// revokeLinkTTLMinutes нь "Энэ би биш" холбоосын хүчинтэй хугацаа (7 хоног)
+const revokeLinkTTLMinutes = 7 * 24 * 60
+
+// user_data_access_log.access_type-д бичигдэх утгууд
+const (
+	loginAccessType     = "login"
+	sessionRevokeAccess = "session_revoke"
+)
+
+// user_data_access_log.access_reason-д бичигдэх утгууд
+const (
+	loginSuccess         = "success"
+	loginInvalidPassword = "invalid_password"
+	loginUnknownEmail    = "unknown_email"
+	loginInactive        = "inactive"
+	loginLocked          = "locked"
+	loginInvalidMFA      = "invalid_mfa"
+)
+
+var errInvalidCredentials = fmt.Errorf("email эсвэл password буруу байна")
+
+func loginFailKey(email string) string {
+	return "login-fail:email:" + strings.ToLower(email)
+}
+
+func loginLockKey(email string) string {
+	return "login-lock:email:" + strings.ToLower(email)
+}
+
+func loginIPKey(ip string) string {
+	return "login-fail:ip:" + ip
+}
+
+// checkLoginLock нь бүртгэл эсвэл IP түгжигдсэн бол LimitError буцаана.
+// Email бүртгэлтэй эсэхээс үл хамааран ижил ажиллана.
+func (s *authService) checkLoginLock(email string, client auth.ClientInfo) error {
+	if err := ratelimit.Exceeded(s.limiter, loginIPKey(client.IPAddress), loginIPMaxFailures); err != nil {
+		return err
+	}
+	return ratelimit.Exceeded(s.limiter, loginLockKey(email), 1)
+}
+
+// loginFailed нь амжилтгүй оролдлогыг тоолж, босго давбал бүртгэлийг түгжинэ.
+func (s *authService) loginFailed(user *model.Users, email string, client auth.ClientInfo, reason string) error {
+	s.logLogin(user, 0, client, reason)
+
+	if _, _, err := s.limiter.Hit(loginIPKey(client.IPAddress), loginIPFailureWindow); err != nil {
+		log.Errorf("login IP тоолуур нэмэхэд алдаа: %v", err)
+	}
+
+	count, _, err := s.limiter.Hit(loginFailKey(email), loginFailureWindow)
+	if err != nil {
+		log.Errorf("login тоолуур нэмэхэд алдаа: %v", err)
+		return errInvalidCredentials
+	}
+	if count < loginLockThreshold {
+		return errInvalidCredentials
+	}
+
+	lockFor := loginMaxLock
+	if shift := count - loginLockThreshold; shift < 6 {
+		lockFor = time.Minute << shift
+	}
+	if _, _, err := s.limiter.Hit(loginLockKey(email), lockFor); err != nil {
+		log.Errorf("login түгжээ тавихад алдаа: %v", err)
+	}
+
+	log.Warnf("нэвтрэх оролдлого түгжигдлээ: email=%s ip=%s алдаа=%d хугацаа=%v", email, client.IPAddress, count, lockFor)
+	return ratelimit.NewLimitError(lockFor)
+}
+
+func (s *authService) clearLoginFailures(email string) {
+	for _, key := range []string{loginFailKey(email), loginLockKey(email)} {
+		if err := s.limiter.Reset(key); err != nil {
+			log.Errorf("login тоолуур цэвэрлэхэд алдаа: %v", err)
+		}
+	}
+}
+
+// logLogin нь нэвтрэх оролдлогыг user_data_access_log-д бичнэ.
+// Бүртгэлгүй email-ийн оролдлогыг user_id байхгүй тул зөвхөн логлоно.
+func (s *authService) logLogin(user *model.Users, sessionID uint, client auth.ClientInfo, reason string) {
+	if user == nil {
+		log.Warnf("нэвтрэх оролдлого амжилтгүй: reason=%s ip=%s ua=%q", reason, client.IPAddress, client.UserAgent)
+		return
+	}
+	s.logAccess(user.ID, sessionID, client, loginAccessType, reason)
+}
+
+func (s *authService) logAccess(userID, sessionID uint, client auth.ClientInfo, accessType, reason string) {
+	entry := &model.UserDataAccessLog{
+		UserID:       userID,
+		AccessedByID: userID,
+		AccessType:   accessType,
+		TableName_:   model.TableNameUserSessions,
+		RecordID:     sessionID,
+		AccessReason: reason,
+		IPAddress:    client.IPAddress,
+		UserAgent:    client.UserAgent,
+		SessionID:    sessionID,
+		AccessedAt:   time.Now(),
+	}
+	if err := s.authRepo.CreateAccessLog(entry); err != nil {
+		log.Errorf("access log бичихэд алдаа (user %d): %v", userID, err)
+	}
+}
+
+// notifyNewLogin нь шинэ төхөөрөмж/IP-ээс нэвтэрсэн тухай имэйл илгээнэ.
+// Имэйл доторх "Энэ би биш" холбоос нь тухайн session-г шууд хаана.
+func (s *authService) notifyNewLogin(user *model.Users, session *model.UserSessions) error {
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID:    user.ID,
+		SessionID: session.ID,
+	}, auth.PurposeRevokeSession, revokeLinkTTLMinutes)
+	if err != nil {
+		return err
+	}
+
+	loginAt := session.CreatedAt
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		loginAt = loginAt.In(loc)
+	}
+
+	msg, err := mailer.Render(mailer.TemplateNewLogin, user.Language, user.Email, mailer.NewLoginData{
+		Name:      user.Name,
+		Device:    session.DeviceInfo,
+		IPAddress: session.IPAddress,
+		LoginAt:   loginAt.Format("2006-01-02 15:04 MST"),
+		RevokeURL: mailer.Link("/auth/not-me", url.Values{"token": {token}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(msg)
+}
+
+// RevokeSessionByLink нь "Энэ би биш" холбоосоор ирсэн session-г хаана.
+func (s *authService) RevokeSessionByLink(f *authForm.NotMeForm, client auth.ClientInfo) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	claims, err := auth.ReadPurposeToken(f.Token, auth.PurposeRevokeSession)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.authRepo.FindSessionByID(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID {
+		return auth.ErrInvalidLinkToken
+	}
+
+	if session.IsActive {
+		if err := s.authRepo.RevokeSession(session.ID, "not_me"); err != nil {
+			return err
+		}
+		auth.InvalidateSessions(session.ID)
+	}
+
+	s.logAccess(session.UserID, session.ID, client, sessionRevokeAccess, "not_me")
+
+	return nil
+}
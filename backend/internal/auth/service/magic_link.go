@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	"mindsteps/internal/mailer"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	// magicLinkOTPType нь auth_otp.otp_type-д бичигдэх утга
+	magicLinkOTPType    = "magic_link"
+	magicLinkTTLMinutes = 15
+	// magicLinkNonceLength нь auth_otp.otp_code (varchar(10))-д багтана
+	magicLinkNonceLength = 10
+)
+
+// SendMagicLink нь нууц үггүй нэвтрэх нэг удаагийн холбоосыг имэйлээр илгээнэ.
+// Email бүртгэлгүй эсвэл эрх хаагдсан ч адилхан амжилттай хариулна.
+func (s *authService) SendMagicLink(f *authForm.MagicLinkForm, client auth.ClientInfo) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.throttleSend(f.Email, magicLinkOTPType, client); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(f.Email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	if err := s.sendMagicLink(user); err != nil {
+		log.Errorf("нэвтрэх холбоос илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// sendMagicLink: холбоосын гарын үсэгтэй token нь auth_otp-ийн мөрийг nonce-оор заана.
+// Ингэснээр token хүчинтэй хугацаандаа ч зөвхөн нэг удаа ашиглагдана.
+func (s *authService) sendMagicLink(user *model.Users) error {
+	if err := s.authRepo.InvalidateOTPs(user.ID, magicLinkOTPType); err != nil {
+		return err
+	}
+
+	nonce, err := randomCode(magicLinkNonceLength)
+	if err != nil {
+		return err
+	}
+	if err := s.authRepo.CreateOTP(&model.AuthOTP{
+		UserID:    user.ID,
+		OtpCode:   nonce,
+		OtpType:   magicLinkOTPType,
+		ExpiredAt: time.Now().Add(magicLinkTTLMinutes * time.Minute),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID: user.ID,
+		Nonce:  nonce,
+	}, auth.PurposeMagicLink, magicLinkTTLMinutes)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailer.Render(mailer.TemplateMagicLink, user.Language, user.Email, mailer.LinkData{
+		Name:           user.Name,
+		URL:            mailer.Link("/auth/magic-link", url.Values{"token": {token}}),
+		ExpiresMinutes: magicLinkTTLMinutes,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(msg)
+}
+
+// LoginWithMagicLink нь холбоосыг нэг удаа ашиглаж энгийн session нээнэ.
+// Холбоос имэйл рүү ирсэн тул email-ийг баталгаажсан гэж тооцно.
+func (s *authService) LoginWithMagicLink(f *authForm.MagicLinkLoginForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error) {
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	claims, err := auth.ReadPurposeToken(f.Token, auth.PurposeMagicLink)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consumed, err := s.authRepo.ConsumeOTP(claims.UserID, claims.Nonce, magicLinkOTPType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !consumed {
+		return nil, nil, auth.ErrInvalidLinkToken
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, nil, auth.ErrInvalidLinkToken
+	}
+	if !user.IsActive {
+		s.logLogin(user, 0, client, loginInactive)
+		return nil, nil, errors.New("таны эрхийг хаасан байна")
+	}
+
+	if !user.IsEmailVerified {
+		user.IsEmailVerified = true
+		user.EmailVerifiedAt = time.Now()
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pair, err := s.completeLogin(user, client, magicLinkOTPType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
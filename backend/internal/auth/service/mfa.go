@@ -0,0 +1,381 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/shared"
+	"mindsteps/pkg/totp"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// mfaIssuer нь authenticator app дээр харагдах нэр
+	mfaIssuer            = "MindSteps"
+	mfaChallengeMinutes  = 5
+	mfaRecoveryCodeCount = 10
+	mfaMaxFailedAttempts = 5
+	mfaAttemptWindow     = 15 * time.Minute
+)
+
+// recoveryCodeAlphabet нь андуурагдах тэмдэгтүүдийг (0/o, 1/l/i) агуулаагүй
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("хоёр шатат баталгаажуулалт аль хэдийн идэвхтэй байна")
+	ErrMFANotEnabled     = errors.New("хоёр шатат баталгаажуулалт идэвхгүй байна")
+	ErrMFASetupRequired  = errors.New("эхлээд хоёр шатат баталгаажуулалтыг тохируулна уу")
+	ErrMFAUnavailable    = errors.New("хоёр шатат баталгаажуулалт түр ашиглах боломжгүй байна")
+	errInvalidMFACode    = errors.New("баталгаажуулах код буруу байна")
+)
+
+// MFASetup нь authenticator app-д оруулах нууц болон QR болгох otpauth:// холбоос
+type MFASetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFARequiredError нь password (эсвэл OIDC) зөв боловч хоёр дахь шат шаардлагатайг илэрхийлнэ.
+// Token-ийг /auth/mfa/verify руу кодтой хамт илгээж session авна.
+type MFARequiredError struct {
+	Token     string
+	ExpiresIn int64
+}
+
+func (e *MFARequiredError) Error() string {
+	return "хоёр шатат баталгаажуулалтын код шаардлагатай"
+}
+
+func mfaFailKey(userID uint) string {
+	return "mfa-fail:user:" + shared.UintToString(userID)
+}
+
+// enabledMFA нь идэвхтэй TOTP тохиргоог буцаана. Тохируулаагүй бол nil.
+func (s *authService) enabledMFA(userID uint) (*model.UserMfa, error) {
+	mfa, err := s.authRepo.FindMFA(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt.IsZero() {
+		return nil, nil
+	}
+	return mfa, nil
+}
+
+// mfaChallenge нь session нээхийн оронд богино хугацаат challenge token олгоно.
+func (s *authService) mfaChallenge(user *model.Users, reason string) error {
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID:     user.ID,
+		AuthMethod: reason,
+	}, auth.PurposeMFAChallenge, mfaChallengeMinutes)
+	if err != nil {
+		return err
+	}
+	return &MFARequiredError{Token: token, ExpiresIn: int64(mfaChallengeMinutes * 60)}
+}
+
+func (s *authService) SetupMFA(userID uint) (*MFASetup, error) {
+	if len(auth.MFAKey) == 0 {
+		return nil, ErrMFAUnavailable
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.enabledMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := totp.SealSecret(auth.MFAKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.authRepo.SaveMFA(&model.UserMfa{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &MFASetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// EnableMFA нь app-ийн анхны кодоор тохиргоог баталгаажуулж, сэргээх кодуудыг нэг удаа буцаана.
+// Одоогийн session-оос бусад бүх төхөөрөмжийг гаргана.
+func (s *authService) EnableMFA(userID, sessionID uint, f *authForm.MFACodeForm) ([]string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.authRepo.FindMFA(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFASetupRequired
+		}
+		return nil, err
+	}
+	if !mfa.EnabledAt.IsZero() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err := ratelimit.Exceeded(s.limiter, mfaFailKey(userID), mfaMaxFailedAttempts); err != nil {
+		return nil, err
+	}
+	step, ok, err := s.validateTOTP(mfa, f.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.mfaFailed(userID)
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authRepo.EnableMFA(userID, step, hashes); err != nil {
+		return nil, err
+	}
+	s.clearMFAFailures(userID)
+
+	sessionIDs, err := s.authRepo.RevokeOtherSessions(userID, sessionID, "mfa_enabled")
+	if err != nil {
+		return nil, err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	return codes, nil
+}
+
+func (s *authService) DisableMFA(userID uint, f *authForm.MFACodeForm) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	mfa, err := s.enabledMFA(userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil {
+		return ErrMFANotEnabled
+	}
+
+	if err := s.verifySecondFactor(mfa, f.Code, true); err != nil {
+		return err
+	}
+
+	return s.authRepo.DeleteMFA(userID)
+}
+
+// RegenerateRecoveryCodes нь хуучин сэргээх кодуудыг хүчингүй болгож шинээр үүсгэнэ.
+// Зөвхөн app-ийн кодоор баталгаажуулна.
+func (s *authService) RegenerateRecoveryCodes(userID uint, f *authForm.MFACodeForm) ([]string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.enabledMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnabled
+	}
+
+	if err := s.verifySecondFactor(mfa, f.Code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifySecondFactor нь идэвхтэй MFA-тай хэрэглэгчийн app эсвэл сэргээх кодыг шалгана (auth.SecondFactor).
+func (s *authService) VerifySecondFactor(userID uint, code string) error {
+	mfa, err := s.enabledMFA(userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil {
+		return ErrMFANotEnabled
+	}
+	return s.verifySecondFactor(mfa, code, true)
+}
+
+// VerifyMFA нь Login-ий буцаасан challenge token болон кодыг шалгаад session нээнэ.
+func (s *authService) VerifyMFA(f *authForm.MFAVerifyForm, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error) {
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	claims, err := auth.ReadPurposeToken(f.MFAToken, auth.PurposeMFAChallenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, nil, auth.ErrInvalidLinkToken
+	}
+	if !user.IsActive {
+		s.logLogin(user, 0, client, loginInactive)
+		return nil, nil, errors.New("таны эрхийг хаасан байна")
+	}
+
+	mfa, err := s.enabledMFA(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mfa == nil {
+		return nil, nil, ErrMFANotEnabled
+	}
+
+	if err := s.verifySecondFactor(mfa, f.Code, true); err != nil {
+		s.logLogin(user, 0, client, loginInvalidMFA)
+		return nil, nil, err
+	}
+
+	pair, err := s.finishLogin(user, client, claims.AuthMethod)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// verifySecondFactor нь TOTP эсвэл (allowRecovery бол) сэргээх кодыг шалгана.
+// Алдаа mfaMaxFailedAttempts хүрвэл mfaAttemptWindow хугацаанд түгжинэ.
+func (s *authService) verifySecondFactor(mfa *model.UserMfa, code string, allowRecovery bool) error {
+	if err := ratelimit.Exceeded(s.limiter, mfaFailKey(mfa.UserID), mfaMaxFailedAttempts); err != nil {
+		return err
+	}
+
+	step, ok, err := s.validateTOTP(mfa, code)
+	if err != nil {
+		return err
+	}
+	if ok {
+		// Нэг алхмын кодыг хоёр дахь удаа ашиглавал буруу гэж үзнэ
+		fresh, err := s.authRepo.UseMFAStep(mfa.UserID, step)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			s.clearMFAFailures(mfa.UserID)
+			return nil
+		}
+	} else if allowRecovery {
+		used, err := s.authRepo.UseRecoveryCode(mfa.UserID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		if used {
+			log.Infof("сэргээх код ашиглагдлаа (user %d)", mfa.UserID)
+			s.clearMFAFailures(mfa.UserID)
+			return nil
+		}
+	}
+
+	return s.mfaFailed(mfa.UserID)
+}
+
+func (s *authService) validateTOTP(mfa *model.UserMfa, code string) (int64, bool, error) {
+	if len(auth.MFAKey) == 0 {
+		return 0, false, ErrMFAUnavailable
+	}
+	secret, err := totp.OpenSecret(auth.MFAKey, mfa.SecretEncrypted)
+	if err != nil {
+		return 0, false, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	return step, ok, nil
+}
+
+func (s *authService) mfaFailed(userID uint) error {
+	count, ttl, err := s.limiter.Hit(mfaFailKey(userID), mfaAttemptWindow)
+	if err != nil {
+		log.Errorf("MFA тоолуур нэмэхэд алдаа: %v", err)
+		return errInvalidMFACode
+	}
+	if count >= mfaMaxFailedAttempts {
+		return ratelimit.NewLimitError(ttl)
+	}
+	return errInvalidMFACode
+}
+
+func (s *authService) clearMFAFailures(userID uint) {
+	if err := s.limiter.Reset(mfaFailKey(userID)); err != nil {
+		log.Errorf("MFA тоолуур цэвэрлэхэд алдаа: %v", err)
+	}
+}
+
+// generateRecoveryCodes нь "xxxxx-xxxxx" хэлбэрийн кодууд болон DB-д хадгалах hash-уудыг буцаана.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, mfaRecoveryCodeCount)
+	hashes := make([]string, mfaRecoveryCodeCount)
+
+	for i := range codes {
+		code, err := randomCode(10)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// randomCode нь recoveryCodeAlphabet-аас n урттай санамсаргүй мөр үүсгэнэ.
+func randomCode(n int) (string, error) {
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	buf := make([]byte, n)
+	for i := range buf {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = recoveryCodeAlphabet[idx.Int64()]
+	}
+	return string(buf), nil
+}
+
+// hashRecoveryCode: хэрэглэгч том үсгээр эсвэл зураасгүй бичсэн ч таарна.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return authRepo.HashToken(normalized)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/oidc"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrOIDCEmailNotVerified = errors.New("provider-ийн email баталгаажаагүй тул бүртгэлтэй холбох боломжгүй")
+
+// oidcLoginReason нь user_data_access_log.access_reason-д бичигдэх утга (жишээ нь "oidc:google")
+func oidcLoginReason(provider string) string {
+	return "oidc:" + provider
+}
+
+// LoginWithIdentity нь provider-ийн баталгаажуулсан identity-ээр нэвтрүүлнэ.
+// Анх удаа ирсэн identity-г баталгаажсан email-ээр нь бүртгэлтэй хэрэглэгчид холбох,
+// эсвэл Register-тэй ижил анхны утгатай шинэ хэрэглэгч үүсгэнэ.
+func (s *authService) LoginWithIdentity(identity *oidc.Identity, client auth.ClientInfo) (*auth.TokenPair, *model.Users, error) {
+	user, err := s.identityUser(identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !user.IsActive {
+		s.logLogin(user, 0, client, loginInactive)
+		return nil, nil, errors.New("таны эрхийг хаасан байна")
+	}
+
+	pair, err := s.completeLogin(user, client, oidcLoginReason(identity.Provider))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair, user, nil
+}
+
+func (s *authService) identityUser(identity *oidc.Identity) (*model.Users, error) {
+	linked, err := s.authRepo.FindIdentity(identity.Provider, identity.Subject)
+	if err == nil {
+		if err := s.authRepo.TouchIdentity(linked.ID, identity.Email); err != nil {
+			log.Errorf("identity шинэчлэхэд алдаа (%d): %v", linked.ID, err)
+		}
+		return s.userRepo.FindByID(linked.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Баталгаажаагүй email-ээр холбовол бусдын бүртгэлийг авах боломж үүснэ
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(identity.Email)
+	created := false
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if user, err = s.createIdentityUser(identity); err != nil {
+			return nil, err
+		}
+		created = true
+	}
+
+	if !user.IsEmailVerified {
+		// Баталгаажаагүй бүртгэлийг email-ийн эзэн бус хүн урьдчилан үүсгэсэн байж болно.
+		// Түүний password, session-оор нэвтрэх боломжийг хааж, бүртгэлийг identity-ийн эзэнд шилжүүлнэ.
+		if !created {
+			plain, err := randomPassword()
+			if err != nil {
+				return nil, err
+			}
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, err
+			}
+			user.Password = string(hashedPassword)
+		}
+		user.IsEmailVerified = true
+		user.EmailVerifiedAt = time.Now()
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+		if !created {
+			sessionIDs, err := s.authRepo.RevokeUserSessions(user.ID, "oidc_link_unverified")
+			if err != nil {
+				return nil, err
+			}
+			auth.InvalidateSessions(sessionIDs...)
+		}
+	}
+
+	if err := s.authRepo.CreateIdentity(&model.UserIdentities{
+		UserID:      user.ID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   time.Now(),
+		LastLoginAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createIdentityUser нь password-гүй хэрэглэгч үүсгэнэ. Санамсаргүй password-ийг хэн ч мэдэхгүй тул
+// хэрэглэгч password-оор нэвтрэх бол "нууц үг мартсан"-аар шинээр тохируулна.
+func (s *authService) createIdentityUser(identity *oidc.Identity) (*model.Users, error) {
+	plain, err := randomPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	return s.newUser(name, identity.Email, plain)
+}
+
+// randomPassword нь хэн ч мэдэхгүй password үүсгэнэ.
+func randomPassword() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
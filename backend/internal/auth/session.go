@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/pkg/redis"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+// sessionCacheTTL нь session шалгалтын үр дүнг Redis-д хадгалах хугацаа.
+// LastActivity мөн энэ давтамжаар л шинэчлэгдэнэ.
+const sessionCacheTTL = time.Minute
+
+const (
+	sessionActive  = "active"
+	sessionRevoked = "revoked"
+)
+
+var ErrSessionRevoked = errors.New("session хүчингүй болсон байна")
+
+type SessionGuard interface {
+	Validate(token string, claims *Token) error
+	Invalidate(sessionIDs ...uint)
+	InvalidateTokens(tokenHashes ...string)
+}
+
+var Sessions SessionGuard
+
+type sessionGuard struct {
+	repo authRepo.AuthRepository
+}
+
+func NewSessionGuard(repo authRepo.AuthRepository) SessionGuard {
+	return &sessionGuard{repo: repo}
+}
+
+func InitSessions(db *gorm.DB) {
+	Sessions = NewSessionGuard(authRepo.NewAuthRepository(db))
+}
+
+// Validate нь access token-ийг revoked_tokens хүснэгт болон түүний session (sid)-тэй тулгаж шалгана.
+// Үр дүнг Redis-д богино хугацаагаар хадгалж, DB руу хандах давтамжийг бууруулна.
+func (g *sessionGuard) Validate(token string, claims *Token) error {
+	if err := g.checkRevokedToken(authRepo.HashToken(token)); err != nil {
+		return err
+	}
+
+	// System token-д user_sessions мөр байдаггүй
+	if claims.UserID == 0 && claims.SystemCode != "" {
+		return nil
+	}
+
+	if claims.SessionID == 0 {
+		return ErrSessionRevoked
+	}
+
+	return g.checkSession(claims)
+}
+
+// Invalidate нь хаагдсан session-уудыг cache дээр шууд revoked болгоно.
+func (g *sessionGuard) Invalidate(sessionIDs ...uint) {
+	for _, id := range sessionIDs {
+		g.store(sessionCacheKey(id), sessionRevoked, sessionCacheTTL)
+	}
+}
+
+// InvalidateTokens нь revoked_tokens-д нэмэгдсэн token-уудын cache-д хадгалсан
+// "хүчинтэй" үр дүнг дарж бичнэ.
+func (g *sessionGuard) InvalidateTokens(tokenHashes ...string) {
+	for _, hash := range tokenHashes {
+		g.store(revokedTokenCacheKey(hash), sessionRevoked, sessionCacheTTL)
+	}
+}
+
+func (g *sessionGuard) checkRevokedToken(tokenHash string) error {
+	key := revokedTokenCacheKey(tokenHash)
+
+	if status, ok := g.cached(key); ok {
+		if status == sessionRevoked {
+			return ErrSessionRevoked
+		}
+		return nil
+	}
+
+	revoked, err := g.repo.IsTokenRevoked(tokenHash)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		g.store(key, sessionRevoked, sessionCacheTTL)
+		return ErrSessionRevoked
+	}
+
+	g.store(key, sessionActive, sessionCacheTTL)
+	return nil
+}
+
+func (g *sessionGuard) checkSession(claims *Token) error {
+	key := sessionCacheKey(claims.SessionID)
+
+	if status, ok := g.cached(key); ok {
+		if status == sessionRevoked {
+			return ErrSessionRevoked
+		}
+		return nil
+	}
+
+	session, err := g.repo.FindSessionByID(claims.SessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	if !session.IsActive || !session.ExpiresAt.After(time.Now()) || session.UserID != claims.UserID {
+		g.store(key, sessionRevoked, sessionCacheTTL)
+		return ErrSessionRevoked
+	}
+
+	if err := g.repo.TouchSession(session.ID); err != nil {
+		log.Errorf("session last_activity шинэчлэхэд алдаа: %v", err)
+	}
+
+	ttl := sessionCacheTTL
+	if left := time.Until(session.ExpiresAt); left < ttl {
+		ttl = left
+	}
+	g.store(key, sessionActive, ttl)
+
+	return nil
+}
+
+func (g *sessionGuard) cached(key string) (string, bool) {
+	client := redis.GetRedis()
+	if client == nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	status, err := client.Get(ctx, key).Result()
+	if err != nil {
+		return "", false
+	}
+	return status, true
+}
+
+func (g *sessionGuard) store(key, status string, ttl time.Duration) {
+	client := redis.GetRedis()
+	if client == nil || ttl <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if err := client.Set(ctx, key, status, ttl).Err(); err != nil {
+		log.Warnf("session cache бичихэд алдаа: %v", err)
+	}
+}
+
+func sessionCacheKey(sessionID uint) string {
+	return fmt.Sprintf("session:%d", sessionID)
+}
+
+func revokedTokenCacheKey(tokenHash string) string {
+	return "revoked-token:" + tokenHash
+}
+
+// InvalidateSessions нь Sessions тохируулагдсан үед л cache-ийг цэвэрлэнэ.
+func InvalidateSessions(sessionIDs ...uint) {
+	if Sessions == nil {
+		return
+	}
+	Sessions.Invalidate(sessionIDs...)
+}
+
+// InvalidateTokens нь Sessions тохируулагдсан үед л token-ийн cache-ийг цэвэрлэнэ.
+func InvalidateTokens(tokenHashes ...string) {
+	if Sessions == nil {
+		return
+	}
+	Sessions.InvalidateTokens(tokenHashes...)
+}
@@ -1,21 +1,74 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 )
 
+const (
+	// AccessTokenMinutes нь access token-ий хүчинтэй хугацаа (минутаар)
+	AccessTokenMinutes time.Duration = 15
+	// RefreshTokenTTL нь refresh token болон session-ий хүчинтэй хугацаа
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Purpose token-ий төрлүүд. Эдгээр нь access token биш тул TokenMiddleware хүлээж авахгүй.
+const (
+	PurposeRevokeSession     = "revoke_session"
+	PurposeOIDCState         = "oidc_state"
+	PurposeMFAChallenge      = "mfa_challenge"
+	PurposeMagicLink         = "magic_link"
+	PurposeEmailChangeCancel = "email_change_cancel"
+	PurposeAccountRecovery   = "account_recovery"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token буруу эсвэл хугацаа дууссан байна")
+	ErrInvalidLinkToken    = errors.New("холбоос буруу эсвэл хугацаа дууссан байна")
+)
+
 type Token struct {
 	jwt.RegisteredClaims
-	SystemCode string   `json:"system_code"`
-	OrgID      uint     `json:"org_id"`
-	UserID     uint     `json:"user_id"`
-	UserEmail  string   `json:"user_email"`
-	Roles      []string `json:"roles"`
-	Level      int      `json:"level"`
-	//Otp        bool     `json:otp`
+	SystemCode  string     `json:"system_code"`
+	OrgID       uint       `json:"org_id"`
+	UserID      uint       `json:"user_id"`
+	UserEmail   string     `json:"user_email"`
+	Roles       []string   `json:"roles"`
+	Level       int        `json:"level"`
+	Permissions []string   `json:"perms,omitempty"`
+	SessionID   uint       `json:"sid,omitempty"`
+	Purpose     string     `json:"purpose,omitempty"`
+	OIDC        *OIDCState `json:"oidc,omitempty"`
+	// Otp нь session хоёр шатат баталгаажуулалтаар нээгдсэн эсэх
+	Otp bool `json:"otp,omitempty"`
+	// APIKeyID нь X-Api-Key-ээр нэвтэрсэн системийн identity-д api_keys.id-г заана
+	APIKeyID uint `json:"api_key_id,omitempty"`
+	// ImpersonatorID нь тусламжийн ажилтны хэрэглэгчийн нэрийн өмнөөс авсан token-д ажилтны users.id-г заана
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
+	// ImpersonationWrite нь impersonation token-оор өөрчлөх хүсэлт илгээж болох эсэх. Анхдагчаар зөвхөн уншина
+	ImpersonationWrite bool `json:"imp_write,omitempty"`
+	// EmailVerified нь token олгох үеийн users.is_email_verified. Баталгаажуулсны дараа refresh хийхэд шинэчлэгдэнэ
+	EmailVerified bool `json:"ev,omitempty"`
+	// AuthMethod нь MFA challenge token-д анхны нэвтрэлтийн аргыг (password, oidc:google) хадгална
+	AuthMethod string `json:"amr,omitempty"`
+	// Nonce нь нэг удаагийн холбоосыг auth_otp (эсвэл user_email_changes)-ийн мөртэй холбоно
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// OIDCState нь provider руу шилжүүлэхээс өмнө cookie-д хадгалах нэг удаагийн утгууд
+type OIDCState struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
 }
 
 func GetTokenInfo(c *fiber.Ctx) *Token {
@@ -27,8 +80,23 @@ func GetTokenInfo(c *fiber.Ctx) *Token {
 	return info
 }
 
+// TokenPair нь нэвтрэлтийн дараа client-д буцаах access болон refresh token
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func NewTokenPair(accessToken string, sessionID uint, refreshSecret string) *TokenPair {
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: FormatRefreshToken(sessionID, refreshSecret),
+		ExpiresIn:    int64((AccessTokenMinutes * time.Minute).Seconds()),
+	}
+}
+
 func CreateUserSession(tokenInfo *Token) (string, error) {
-	token, err := Gjwt.GenerateToken(tokenInfo, time.Duration(144000*3))
+	token, err := Gjwt.GenerateToken(tokenInfo, AccessTokenMinutes)
 	if err != nil {
 		return "", err
 	}
@@ -49,3 +117,47 @@ func CreateSystemSession(systemCode string, roles []string, day uint) (string, e
 
 	return token, nil
 }
+
+// CreatePurposeToken нь имэйлийн холбоос зэрэгт ашиглах, зөвхөн нэг зорилготой token үүсгэнэ.
+func CreatePurposeToken(tokenInfo *Token, purpose string, minutes time.Duration) (string, error) {
+	tokenInfo.Purpose = purpose
+	return Gjwt.GenerateToken(tokenInfo, minutes)
+}
+
+// ReadPurposeToken нь token-ий гарын үсэг болон зорилгыг шалгана.
+func ReadPurposeToken(token, purpose string) (*Token, error) {
+	claims, err := Gjwt.ReadToken(token)
+	if err != nil || claims.Purpose != purpose {
+		return nil, ErrInvalidLinkToken
+	}
+	return claims, nil
+}
+
+// GenerateRefreshSecret нь refresh token-ий санамсаргүй нууц хэсгийг үүсгэнэ.
+// DB-д зөвхөн түүний hash хадгалагдана.
+func GenerateRefreshSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// FormatRefreshToken нь "<session_id>.<secret>" хэлбэрийн opaque refresh token үүсгэнэ.
+func FormatRefreshToken(sessionID uint, secret string) string {
+	return fmt.Sprintf("%d.%s", sessionID, secret)
+}
+
+func ParseRefreshToken(token string) (uint, string, error) {
+	id, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return 0, "", ErrInvalidRefreshToken
+	}
+
+	sessionID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || sessionID == 0 {
+		return 0, "", ErrInvalidRefreshToken
+	}
+
+	return uint(sessionID), secret, nil
+}
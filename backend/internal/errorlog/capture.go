@@ -0,0 +1,197 @@
+package errorlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/errorlog/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/utils"
+	"gorm.io/gorm"
+)
+
+// error_logs.error_type-д бичигдэх утгууд
+const (
+	// TypePanic нь handler-т panic гарсан
+	TypePanic = "panic"
+	// TypeError нь handler 5xx алдаа (error) буцаасан
+	TypeError = "error"
+	// TypeResponse нь handler өөрөө 5xx хариу бичсэн (shared.ResponseErr)
+	TypeResponse = "response"
+)
+
+const (
+	messageLimit = 4 * 1024
+	stackLimit   = 16 * 1024
+	// localsErrorID нь бичигдсэн error_logs.id-г ErrorHandler-т дамжуулна
+	localsErrorID = "errorLogID"
+	// internalMessage нь 5xx үед client-д харуулах мессеж (дотоод алдааг задруулахгүй)
+	internalMessage = "Серверт алдаа гарлаа. Дахин оролдоно уу"
+)
+
+// Recorder нь алдааг fingerprint-ээр нэгтгэн хадгална.
+type Recorder interface {
+	Record(entry *model.ErrorLogs) (uint, error)
+}
+
+var Store Recorder
+
+func Init(db *gorm.DB) {
+	Store = repository.NewErrorLogRepository(db)
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	quotedPattern = regexp.MustCompile(`"[^"]*"|'[^']*'|\([^()]*\)=\([^()]*\)`)
+	hexPattern    = regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*[0-9][0-9a-fA-F]*\b`)
+)
+
+// sanitizeMessage нь мессеж дэх и-мэйл хаягийг нууцалж, уртыг хязгаарлана.
+func sanitizeMessage(message string) string {
+	message = emailPattern.ReplaceAllString(message, auth.Redacted)
+	if len(message) > messageLimit {
+		message = message[:messageLimit]
+	}
+	return message
+}
+
+// Fingerprint нь алдааны төрөл, method, route болон мессежийн хувьсах хэсгийг (тоо, id,
+// хашилттай утга) арилгасан хэлбэрээс тооцогдоно. Жишээ нь "user 12 not found", "user 15 not found"
+// нэг бүлэгт орно.
+func Fingerprint(errorType, method, route, message string) string {
+	normalized := quotedPattern.ReplaceAllString(message, "?")
+	normalized = hexPattern.ReplaceAllString(normalized, "#")
+	sum := sha256.Sum256([]byte(errorType + "|" + method + "|" + route + "|" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestURL нь замыг query-ийн нууц параметрүүдийг (token, code, ...) нууцалсан хэлбэрээр буцаана.
+func requestURL(c *fiber.Ctx) string {
+	query := url.Values{}
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		if auth.IsSensitiveKey(string(key)) {
+			query.Add(string(key), auth.Redacted)
+			return
+		}
+		query.Add(string(key), string(value))
+	})
+	if len(query) == 0 {
+		return c.Path()
+	}
+	return c.Path() + "?" + query.Encode()
+}
+
+// routePath нь тохирсон route-ийн загвар (/journals/:id), route олдоогүй бол замыг буцаана.
+func routePath(c *fiber.Ctx) string {
+	if route := c.Route(); route != nil && route.Path != "" && route.Path != "/" {
+		return route.Path
+	}
+	return c.Path()
+}
+
+// Capture нь алдааг нууц мэдээллийг нь арилгаад error_logs-д бичнэ. Бичиж чадаагүй ч
+// хүсэлтийг өөрчлөхгүй, зөвхөн log-д гаргана.
+func Capture(c *fiber.Ctx, errorType, message, stack string) {
+	if Store == nil {
+		return
+	}
+
+	message = sanitizeMessage(message)
+	if len(stack) > stackLimit {
+		stack = stack[:stackLimit]
+	}
+	entry := &model.ErrorLogs{
+		ErrorType:     errorType,
+		ErrorMessage:  message,
+		StackTrace:    stack,
+		RequestURL:    requestURL(c),
+		RequestMethod: c.Method(),
+		RequestBody:   auth.RedactedRequestBody(c),
+		IPAddress:     c.IP(),
+		UserAgent:     c.Get(fiber.HeaderUserAgent),
+		Fingerprint:   Fingerprint(errorType, c.Method(), routePath(c), message),
+		CreatedAt:     time.Now(),
+	}
+	if claims := auth.GetTokenInfo(c); claims != nil {
+		entry.UserID = claims.UserID
+	}
+
+	id, err := Store.Record(entry)
+	if err != nil {
+		log.Errorf("error_logs бичихэд алдаа (%s %s): %v", entry.RequestMethod, entry.RequestURL, err)
+		return
+	}
+	c.Locals(localsErrorID, id)
+}
+
+func statusCode(err error) int {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// responseMessage нь хариуны {"message": ...}-ийг, байхгүй бол status-ийн текстийг буцаана.
+func responseMessage(c *fiber.Ctx) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(c.Response().Body(), &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return fmt.Sprintf("%d %s", c.Response().StatusCode(), utils.StatusMessage(c.Response().StatusCode()))
+}
+
+// Middleware нь fiber-ийн recover middleware-ийг орлоно: panic-ийг stack trace-тэй нь,
+// handler-ийн буцаасан 5xx error болон handler-ийн бичсэн 5xx хариуг error_logs-д бичнэ.
+// Алдааны хариуг ErrorHandler бичнэ.
+func Middleware(c *fiber.Ctx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := string(debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+			log.Errorf("path: %s | %v\n%s", c.Path(), err, stack)
+			Capture(c, TypePanic, err.Error(), stack)
+		}
+	}()
+
+	if err = c.Next(); err != nil {
+		if statusCode(err) >= fiber.StatusInternalServerError {
+			Capture(c, TypeError, err.Error(), "")
+		}
+		return err
+	}
+	if c.Response().StatusCode() >= fiber.StatusInternalServerError {
+		Capture(c, TypeResponse, responseMessage(c), "")
+	}
+	return nil
+}
+
+// ErrorHandler нь fiber.Config.ErrorHandler. fiber.Error-ийн мессежийг буцааж, бусад алдааны
+// дотоод мессежийг нууцлан ерөнхий мессеж, error_logs-ийн id-тай хамт 500 буцаана.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	message := internalMessage
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		code, message = fe.Code, fe.Message
+	}
+
+	body := fiber.Map{"message": message}
+	if id, ok := c.Locals(localsErrorID).(uint); ok && id != 0 {
+		body["error_id"] = id
+	}
+	return c.Status(code).JSON(body)
+}
@@ -0,0 +1,84 @@
+package form
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrorLogFilter нь error_logs-оос хайх шүүлтүүр. Хоосон талбар шүүхгүй.
+// - Status: open (шийдэгдээгүй), resolved эсвэл хоосон (бүгд)
+// - ErrorType: panic, error, response
+// - Fingerprint: Нэг бүлгийн алдаанууд
+// - Search: error_message эсвэл request_url-д агуулагдах текст
+// - From, To: last_seen_at-ийн огнооны хязгаар (YYYY-MM-DD, хоёр тал нь багтана)
+type ErrorLogFilter struct {
+	Status      string
+	ErrorType   string
+	Fingerprint string
+	Search      string
+	From        string
+	To          string
+	Page        int
+	Limit       int
+}
+
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+)
+
+// Validate нь төлөв, огноог шалгаж, Page, Limit-д анхны утга онооно.
+func (f *ErrorLogFilter) Validate() error {
+	switch f.Status {
+	case "", StatusOpen, StatusResolved:
+	default:
+		return fmt.Errorf("status нь %s эсвэл %s байх ёстой", StatusOpen, StatusResolved)
+	}
+	if _, _, err := f.Range(); err != nil {
+		return err
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		return fmt.Errorf("limit %d-оос их байж болохгүй", MaxLimit)
+	}
+	return nil
+}
+
+// Range нь огнооны хязгаарыг буцаана. to нь дараагийн өдрийн эхлэл (хамаарахгүй) байна.
+func (f *ErrorLogFilter) Range() (from, to time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.Parse(time.DateOnly, f.From); err != nil {
+			return from, to, fmt.Errorf("from YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+	}
+	if f.To != "" {
+		if to, err = time.Parse(time.DateOnly, f.To); err != nil {
+			return from, to, fmt.Errorf("to YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("to нь from-оос өмнө байж болохгүй")
+	}
+	return from, to, nil
+}
+
+// ResolveForm нь алдааг шийдэгдсэн гэж тэмдэглэх. Notes нь заавал биш.
+type ResolveForm struct {
+	Notes string `json:"notes" validate:"max=2000"`
+}
+
+// NotesForm нь алдаанд тэмдэглэл нэмэх (шийдэгдсэн төлөвийг өөрчлөхгүй).
+type NotesForm struct {
+	Notes string `json:"notes" validate:"required,max=2000"`
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+
+	"mindsteps/internal/auth"
+	"mindsteps/internal/errorlog/form"
+	"mindsteps/internal/errorlog/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ErrorLogHandler struct {
+	service service.ErrorLogService
+}
+
+func NewErrorLogHandler(s service.ErrorLogService) *ErrorLogHandler {
+	return &ErrorLogHandler{service: s}
+}
+
+func filterFromQuery(c *fiber.Ctx) form.ErrorLogFilter {
+	return form.ErrorLogFilter{
+		Status:      c.Query("status"),
+		ErrorType:   c.Query("error_type"),
+		Fingerprint: c.Query("fingerprint"),
+		Search:      c.Query("q"),
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		Page:        c.QueryInt("page", 1),
+		Limit:       c.QueryInt("limit", form.DefaultLimit),
+	}
+}
+
+func respondError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrErrorLogNotFound):
+		return shared.ResponseNotFound(c)
+	case errors.Is(err, service.ErrAlreadyResolved):
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	return shared.ResponseErr(c, err.Error())
+}
+
+// List: ?status=open|resolved&error_type=&fingerprint=&q=&from=&to=&page=&limit=
+func (h *ErrorLogHandler) List(c *fiber.Ctx) error {
+	filter := filterFromQuery(c)
+
+	logs, total, err := h.service.List(&filter)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"page":    filter.Page,
+		"limit":   filter.Limit,
+		"total":   total,
+		"errors":  logs,
+	})
+}
+
+// Groups нь List-тэй ижил шүүлтүүрээр алдааг fingerprint-ээр бүлэглэнэ.
+func (h *ErrorLogHandler) Groups(c *fiber.Ctx) error {
+	filter := filterFromQuery(c)
+
+	groups, total, err := h.service.Groups(&filter)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"page":    filter.Page,
+		"limit":   filter.Limit,
+		"total":   total,
+		"groups":  groups,
+	})
+}
+
+func (h *ErrorLogHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "id буруу байна")
+	}
+
+	entry, err := h.service.Get(uint(id))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"error":   entry,
+	})
+}
+
+func (h *ErrorLogHandler) Resolve(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "id буруу байна")
+	}
+
+	var f form.ResolveForm
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&f); err != nil {
+			return shared.ResponseBadRequest(c, err.Error())
+		}
+	}
+	if err := shared.Validate(f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	entry, err := h.service.Resolve(uint(id), tokenInfo.UserID, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Алдааг шийдэгдсэн гэж тэмдэглэлээ",
+		"error":   entry,
+	})
+}
+
+func (h *ErrorLogHandler) Annotate(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "id буруу байна")
+	}
+
+	var f form.NotesForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := shared.Validate(f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	entry, err := h.service.Annotate(uint(id), &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Тэмдэглэл хадгалагдлаа",
+		"error":   entry,
+	})
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/errorlog/form"
+
+	"gorm.io/gorm"
+)
+
+// ErrorGroup нь нэг fingerprint-тэй алдаануудын нэгтгэл.
+// Incidents нь мөрийн тоо (шийдэгдсэний дараа дахин гарсан бүр шинэ мөр),
+// Occurrences нь нийт давтагдсан тоо.
+type ErrorGroup struct {
+	Fingerprint  string    `json:"fingerprint"`
+	ErrorType    string    `json:"error_type"`
+	ErrorMessage string    `json:"error_message"`
+	RequestURL   string    `json:"request_url"`
+	Incidents    int64     `json:"incidents"`
+	Occurrences  int64     `json:"occurrences"`
+	FirstSeenAt  time.Time `json:"first_seen_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+	// OpenID нь шийдэгдээгүй мөрийн id (байхгүй бол 0)
+	OpenID uint `json:"open_id"`
+}
+
+type ErrorLogRepository interface {
+	Record(entry *model.ErrorLogs) (uint, error)
+	List(filter *form.ErrorLogFilter) ([]model.ErrorLogs, int64, error)
+	Groups(filter *form.ErrorLogFilter) ([]ErrorGroup, int64, error)
+	FindByID(id uint) (*model.ErrorLogs, error)
+	Resolve(id, resolvedByID uint, notes string, at time.Time) error
+	Annotate(id uint, notes string) error
+}
+
+type errorLogRepo struct {
+	db *gorm.DB
+}
+
+func NewErrorLogRepository(db *gorm.DB) ErrorLogRepository {
+	return &errorLogRepo{db: db}
+}
+
+// Record нь шинэ алдааг бичнэ. Ижил fingerprint-тэй шийдэгдээгүй мөр байвал шинэ мөр
+// үүсгэлгүй occurrences, last_seen_at-ийг шинэчилнэ (uq_error_logs_open_fingerprint).
+// Бичигдсэн эсвэл шинэчлэгдсэн мөрийн id-г буцаана.
+func (r *errorLogRepo) Record(entry *model.ErrorLogs) (uint, error) {
+	var id uint
+	err := r.db.Raw(`INSERT INTO `+model.TableNameErrorLogs+` AS e
+	(user_id, error_type, error_message, stack_trace, request_url, request_method, request_body,
+	 ip_address, user_agent, is_resolved, fingerprint, occurrences, created_at, last_seen_at)
+VALUES (NULLIF(@user_id, 0), @error_type, @error_message, @stack_trace, @request_url, @request_method, @request_body,
+	NULLIF(@ip_address, '')::inet, @user_agent, false, @fingerprint, 1, @now, @now)
+ON CONFLICT (fingerprint) WHERE is_resolved = false
+DO UPDATE SET occurrences = e.occurrences + 1, last_seen_at = EXCLUDED.last_seen_at
+RETURNING e.id`, map[string]interface{}{
+		"user_id":        entry.UserID,
+		"error_type":     entry.ErrorType,
+		"error_message":  entry.ErrorMessage,
+		"stack_trace":    entry.StackTrace,
+		"request_url":    entry.RequestURL,
+		"request_method": entry.RequestMethod,
+		"request_body":   entry.RequestBody,
+		"ip_address":     entry.IPAddress,
+		"user_agent":     entry.UserAgent,
+		"fingerprint":    entry.Fingerprint,
+		"now":            entry.CreatedAt,
+	}).Scan(&id).Error
+	return id, err
+}
+
+func (r *errorLogRepo) filtered(filter *form.ErrorLogFilter) (*gorm.DB, error) {
+	query := r.db.Model(&model.ErrorLogs{})
+	switch filter.Status {
+	case form.StatusOpen:
+		query = query.Where("is_resolved = ?", false)
+	case form.StatusResolved:
+		query = query.Where("is_resolved = ?", true)
+	}
+	if filter.ErrorType != "" {
+		query = query.Where("error_type = ?", filter.ErrorType)
+	}
+	if filter.Fingerprint != "" {
+		query = query.Where("fingerprint = ?", filter.Fingerprint)
+	}
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("(error_message ILIKE ? OR request_url ILIKE ?)", like, like)
+	}
+	from, to, err := filter.Range()
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() {
+		query = query.Where("last_seen_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("last_seen_at < ?", to)
+	}
+	return query, nil
+}
+
+// List нь шүүлтүүрт таарах алдаануудыг сүүлд гарснаас нь эхлэн хуудаслаж, нийт тоог хамт буцаана.
+func (r *errorLogRepo) List(filter *form.ErrorLogFilter) ([]model.ErrorLogs, int64, error) {
+	query, err := r.filtered(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []model.ErrorLogs
+	err = query.Omit("stack_trace", "request_body").
+		Order("last_seen_at DESC NULLS LAST, id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// Groups нь шүүлтүүрт таарах алдааг fingerprint-ээр бүлэглэж, сүүлд гарснаас нь эхлэн буцаана.
+// Жишээ мессеж, URL нь бүлгийн хамгийн сүүлийн мөрөөс авна.
+func (r *errorLogRepo) Groups(filter *form.ErrorLogFilter) ([]ErrorGroup, int64, error) {
+	query, err := r.filtered(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	query = query.Where("fingerprint IS NOT NULL")
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Distinct("fingerprint").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var groups []ErrorGroup
+	err = query.Select(`fingerprint,
+	(array_agg(error_type ORDER BY id DESC))[1] AS error_type,
+	(array_agg(error_message ORDER BY id DESC))[1] AS error_message,
+	(array_agg(request_url ORDER BY id DESC))[1] AS request_url,
+	count(*) AS incidents,
+	sum(occurrences) AS occurrences,
+	min(created_at) AS first_seen_at,
+	max(last_seen_at) AS last_seen_at,
+	coalesce(max(id) FILTER (WHERE is_resolved = false), 0) AS open_id`).
+		Group("fingerprint").
+		Order("max(last_seen_at) DESC NULLS LAST").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Scan(&groups).Error
+	return groups, total, err
+}
+
+func (r *errorLogRepo) FindByID(id uint) (*model.ErrorLogs, error) {
+	var entry model.ErrorLogs
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Resolve нь алдааг шийдэгдсэн болгоно. Дараа нь ижил fingerprint-тэй алдаа гарвал шинэ мөр үүснэ.
+func (r *errorLogRepo) Resolve(id, resolvedByID uint, notes string, at time.Time) error {
+	updates := map[string]interface{}{
+		"is_resolved":    true,
+		"resolved_at":    at,
+		"resolved_by_id": resolvedByID,
+	}
+	if notes != "" {
+		updates["resolution_notes"] = notes
+	}
+	return r.db.Model(&model.ErrorLogs{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *errorLogRepo) Annotate(id uint, notes string) error {
+	return r.db.Model(&model.ErrorLogs{}).Where("id = ?", id).Update("resolution_notes", notes).Error
+}
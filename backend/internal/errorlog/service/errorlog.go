@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/errorlog/form"
+	"mindsteps/internal/errorlog/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrErrorLogNotFound = errors.New("алдааны бичлэг олдсонгүй")
+	ErrAlreadyResolved  = errors.New("алдаа аль хэдийн шийдэгдсэн байна")
+)
+
+type ErrorLogService interface {
+	List(filter *form.ErrorLogFilter) ([]model.ErrorLogs, int64, error)
+	Groups(filter *form.ErrorLogFilter) ([]repository.ErrorGroup, int64, error)
+	Get(id uint) (*model.ErrorLogs, error)
+	Resolve(id, adminID uint, f *form.ResolveForm) (*model.ErrorLogs, error)
+	Annotate(id uint, f *form.NotesForm) (*model.ErrorLogs, error)
+}
+
+type errorLogService struct {
+	repo repository.ErrorLogRepository
+}
+
+func NewErrorLogService(repo repository.ErrorLogRepository) ErrorLogService {
+	return &errorLogService{repo: repo}
+}
+
+func (s *errorLogService) List(filter *form.ErrorLogFilter) ([]model.ErrorLogs, int64, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(filter)
+}
+
+func (s *errorLogService) Groups(filter *form.ErrorLogFilter) ([]repository.ErrorGroup, int64, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.Groups(filter)
+}
+
+func (s *errorLogService) Get(id uint) (*model.ErrorLogs, error) {
+	entry, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrErrorLogNotFound
+	}
+	return entry, err
+}
+
+// Resolve нь шийдэгдээгүй алдааг шийдэгдсэн болгоно. Notes хоосон бол өмнөх тэмдэглэл хэвээр үлдэнэ.
+func (s *errorLogService) Resolve(id, adminID uint, f *form.ResolveForm) (*model.ErrorLogs, error) {
+	entry, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsResolved {
+		return nil, ErrAlreadyResolved
+	}
+
+	now := time.Now()
+	if err := s.repo.Resolve(id, adminID, f.Notes, now); err != nil {
+		return nil, err
+	}
+	entry.IsResolved = true
+	entry.ResolvedAt = now
+	entry.ResolvedByID = adminID
+	if f.Notes != "" {
+		entry.ResolutionNotes = f.Notes
+	}
+	return entry, nil
+}
+
+// Annotate нь алдааны тэмдэглэлийг солино (шийдэгдсэн эсэхээс үл хамаарна).
+func (s *errorLogService) Annotate(id uint, f *form.NotesForm) (*model.ErrorLogs, error) {
+	entry, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Annotate(id, f.Notes); err != nil {
+		return nil, err
+	}
+	entry.ResolutionNotes = f.Notes
+	return entry, nil
+}
@@ -11,6 +11,7 @@ type GamificationRepository interface {
 	UpdateProgress(stats *model.UserGamification) error
 	CreateScoreHistory(history *model.ScoringHistory) error
 	GetLevelByScore(score int) (*model.UserLevels, error)
+	UserTimezone(userID uint) (string, error)
 }
 
 type gamificationRepo struct {
@@ -52,3 +53,9 @@ func (r *gamificationRepo) UpdateProgress(stats *model.UserGamification) error {
 func (r *gamificationRepo) CreateScoreHistory(history *model.ScoringHistory) error {
 	return r.db.Create(history).Error
 }
+
+func (r *gamificationRepo) UserTimezone(userID uint) (string, error) {
+	var timezone string
+	err := r.db.Model(&model.Users{}).Where("id = ?", userID).Select("timezone").Scan(&timezone).Error
+	return timezone, err
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"mindsteps/database/model"
 	"mindsteps/internal/gamification/repository"
+	"mindsteps/internal/shared"
 	"time"
 )
 
@@ -31,18 +32,16 @@ func (s *gamificationService) AddXP(userID uint, points int, source_type string,
 	}
 
 	// --- Streak Logic Эхлэл ---
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Өдрүүдийг хэрэглэгчийн цагийн бүсээр (users.timezone) тоолно, серверийн цагаар биш
+	timezone, err := s.repo.UserTimezone(userID)
+	if err != nil {
+		return err
+	}
+	loc := shared.UserLocation(timezone)
+	now := time.Now().In(loc)
 
 	if !stats.LastActivityAt.IsZero() {
-		lastActivityDay := time.Date(
-			stats.LastActivityAt.Year(),
-			stats.LastActivityAt.Month(),
-			stats.LastActivityAt.Day(),
-			0, 0, 0, 0, stats.LastActivityAt.Location(),
-		)
-
-		diff := today.Sub(lastActivityDay).Hours() / 24
+		diff := shared.DaysBetween(shared.FromStored(stats.LastActivityAt).In(loc), now)
 
 		if diff == 1 {
 			// Өчигдөр орсон байна, streak нэмнэ
@@ -60,7 +59,7 @@ func (s *gamificationService) AddXP(userID uint, points int, source_type string,
 		stats.CurrentStreak = 1
 		stats.LongestStreak = 1
 	}
-	stats.LastActivityAt = now
+	stats.LastActivityAt = now.In(shared.StoredLocation)
 	// --- Streak Logic Төгсгөл ---
 
 	// 2. Оноо нэмэх
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const fileMailerFrom = "MindSteps <no-reply@mindsteps.local>"
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer нь имэйлийг илгээхийн оронд maildir бүтэцтэй хавтаст (.eml) хадгална.
+// Local хөгжүүлэлт болон тестэд ашиглана.
+func NewFileMailer(dir string) (Mailer, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("maildir үүсгэхэд алдаа: %w", err)
+		}
+	}
+	return &fileMailer{dir: dir}, nil
+}
+
+// Send нь эхлээд tmp/-д бичээд new/ руу зөөнө (maildir-ийн atomic delivery).
+func (m *fileMailer) Send(msg *Message) error {
+	name := fmt.Sprintf("%d.%s.mindsteps.eml", time.Now().UnixNano(), uuid.New().String())
+
+	tmpPath := filepath.Join(m.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, msg.Build(fileMailerFrom), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, filepath.Join(m.dir, "new", name))
+}
@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"mindsteps/config"
+	"mindsteps/pkg/smtp"
+)
+
+// Message нь илгээх нэг имэйл. Text болон HTML хоёуланг нь multipart/alternative болгож илгээнэ.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer нь имэйл илгээх хэрэгсэл. SMTP болон local file (maildir) хэрэгжүүлэлттэй.
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+var (
+	defaultMailer Mailer
+	publicURL     string
+	once          sync.Once
+)
+
+// MustLoad нь MAIL_DRIVER тохиргооноос хамаарч Mailer үүсгэнэ.
+func MustLoad() Mailer {
+	once.Do(func() {
+		m, err := New(config.Get().Mail.Driver)
+		if err != nil {
+			panic(err)
+		}
+		defaultMailer = m
+		publicURL = strings.TrimRight(config.Get().Mail.PublicURL, "/")
+	})
+	return defaultMailer
+}
+
+// Get нь MustLoad-аар үүсгэсэн Mailer-ийг буцаана.
+func Get() Mailer {
+	return MustLoad()
+}
+
+// Link нь имэйлд оруулах, APP_PUBLIC_URL-аас эхэлсэн бүтэн холбоос үүсгэнэ.
+func Link(path string, query url.Values) string {
+	link := publicURL + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+func New(driver string) (Mailer, error) {
+	switch driver {
+	case "smtp":
+		if err := smtp.Load(); err != nil {
+			return nil, fmt.Errorf("SMTP холбогдоход алдаа: %w", err)
+		}
+		return NewSMTPMailer(smtp.GetClient()), nil
+	case "file", "":
+		return NewFileMailer(config.Get().Mail.FileDir)
+	default:
+		return nil, fmt.Errorf("тодорхойгүй MAIL_DRIVER: %s", driver)
+	}
+}
@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"fmt"
+
+	"mindsteps/pkg/smtp"
+)
+
+type smtpMailer struct {
+	client *smtp.SMTPClient
+}
+
+// NewSMTPMailer нь pkg/smtp client ашиглан имэйл илгээнэ (PLAIN эсвэл XOAUTH2).
+func NewSMTPMailer(client *smtp.SMTPClient) Mailer {
+	return &smtpMailer{client: client}
+}
+
+func (m *smtpMailer) Send(msg *Message) error {
+	if m.client == nil {
+		return fmt.Errorf("SMTP client тохируулаагүй байна")
+	}
+	return m.client.SendRaw(msg.To, msg.Build(m.client.From()))
+}
@@ -0,0 +1,173 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	textTemplate "text/template"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+type Template string
+
+const (
+	TemplateEmailVerification Template = "verification"
+	TemplatePasswordReset     Template = "password_reset"
+	TemplateNewLogin          Template = "new_login"
+	TemplateMagicLink         Template = "magic_link"
+	TemplateEmailChange       Template = "email_change"
+	TemplateEmailChangeNotice Template = "email_change_notice"
+	TemplateSupportAccess     Template = "support_access"
+	TemplateAccountDeletion   Template = "account_deletion"
+	TemplateAccountRecovered  Template = "account_recovered"
+	TemplateAccountDeleted    Template = "account_deleted"
+	TemplateDataExportReady   Template = "data_export_ready"
+)
+
+const defaultLanguage = "mn"
+
+var subjects = map[Template]map[string]string{
+	TemplateEmailVerification: {
+		"mn": "MindSteps - Имэйл хаягаа баталгаажуулна уу",
+		"en": "MindSteps - Verify your email address",
+	},
+	TemplatePasswordReset: {
+		"mn": "MindSteps - Нууц үг сэргээх код",
+		"en": "MindSteps - Password reset code",
+	},
+	TemplateNewLogin: {
+		"mn": "MindSteps - Шинэ төхөөрөмжөөс нэвтэрлээ",
+		"en": "MindSteps - New sign-in to your account",
+	},
+	TemplateMagicLink: {
+		"mn": "MindSteps - Нэвтрэх холбоос",
+		"en": "MindSteps - Your sign-in link",
+	},
+	TemplateEmailChange: {
+		"mn": "MindSteps - Шинэ имэйл хаягаа баталгаажуулна уу",
+		"en": "MindSteps - Confirm your new email address",
+	},
+	TemplateEmailChangeNotice: {
+		"mn": "MindSteps - Имэйл хаяг солих хүсэлт",
+		"en": "MindSteps - Email change requested",
+	},
+	TemplateSupportAccess: {
+		"mn": "MindSteps - Тусламжийн ажилтан таны бүртгэлд нэвтэрлээ",
+		"en": "MindSteps - Support accessed your account",
+	},
+	TemplateAccountDeletion: {
+		"mn": "MindSteps - Бүртгэл устгах хүсэлт хүлээн авлаа",
+		"en": "MindSteps - Your account is scheduled for deletion",
+	},
+	TemplateAccountRecovered: {
+		"mn": "MindSteps - Бүртгэл сэргээгдлээ",
+		"en": "MindSteps - Your account has been restored",
+	},
+	TemplateAccountDeleted: {
+		"mn": "MindSteps - Бүртгэл бүрмөсөн устгагдлаа",
+		"en": "MindSteps - Your account has been deleted",
+	},
+	TemplateDataExportReady: {
+		"mn": "MindSteps - Таны мэдээлэл татахад бэлэн боллоо",
+		"en": "MindSteps - Your data export is ready",
+	},
+}
+
+// OTPData нь OTP агуулсан имэйлийн загварт дамжуулах өгөгдөл
+type OTPData struct {
+	Name           string
+	Code           string
+	ExpiresMinutes int
+}
+
+// NewLoginData нь шинэ төхөөрөмжөөс нэвтэрсэн тухай мэдэгдлийн загварт дамжуулах өгөгдөл
+type NewLoginData struct {
+	Name      string
+	Device    string
+	IPAddress string
+	LoginAt   string
+	RevokeURL string
+}
+
+// LinkData нь нэг удаагийн холбоос агуулсан имэйлийн загварт дамжуулах өгөгдөл
+type LinkData struct {
+	Name           string
+	URL            string
+	ExpiresMinutes int
+}
+
+// EmailChangeData нь email солих тухай хуучин хаяг руу илгээх мэдэгдлийн загварт дамжуулах өгөгдөл
+type EmailChangeData struct {
+	Name         string
+	NewEmail     string
+	CancelURL    string
+	ExpiresHours int
+}
+
+// SupportAccessData нь тусламжийн ажилтан бүртгэлд нэвтэрсэн тухай мэдэгдлийн загварт дамжуулах өгөгдөл
+type SupportAccessData struct {
+	Name           string
+	Reason         string
+	ReadOnly       bool
+	AccessAt       string
+	ExpiresMinutes int
+	RevokeURL      string
+}
+
+// AccountDeletionData нь бүртгэл устгах үе шат бүрийн (хүсэлт, сэргээлт, бүрмөсөн устгал) имэйлд дамжуулах өгөгдөл
+type AccountDeletionData struct {
+	Name       string
+	RecoverURL string
+	PurgeAt    string
+	GraceDays  int
+}
+
+// DataExportData нь хувийн мэдээллийн экспорт бэлэн болсон тухай имэйлд дамжуулах өгөгдөл
+type DataExportData struct {
+	Name        string
+	DownloadURL string
+	ExpiresAt   string
+}
+
+// Render нь тухайн хэлний (mn/en) HTML болон text загвараас Message үүсгэнэ.
+// Хэл олдохгүй бол монгол загварыг ашиглана.
+func Render(tpl Template, lang, to string, data any) (*Message, error) {
+	subject, ok := subjects[tpl][lang]
+	if !ok {
+		lang = defaultLanguage
+		subject, ok = subjects[tpl][lang]
+		if !ok {
+			return nil, fmt.Errorf("имэйлийн загвар олдсонгүй: %s", tpl)
+		}
+	}
+
+	base := fmt.Sprintf("templates/%s.%s", tpl, lang)
+
+	text, err := textTemplate.ParseFS(templateFS, base+".txt")
+	if err != nil {
+		return nil, err
+	}
+	var textBuf bytes.Buffer
+	if err := text.Execute(&textBuf, data); err != nil {
+		return nil, err
+	}
+
+	html, err := htmlTemplate.ParseFS(templateFS, base+".html")
+	if err != nil {
+		return nil, err
+	}
+	var htmlBuf bytes.Buffer
+	if err := html.Execute(&htmlBuf, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: subject,
+		Text:    textBuf.String(),
+		HTML:    htmlBuf.String(),
+	}, nil
+}
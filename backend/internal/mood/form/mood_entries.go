@@ -17,11 +17,72 @@ type MoodEntryForm struct {
 	MoodUnitId     int    `json:"mood_unit_id"`
 	CorevalueID    uint   `json:"core_value_id"`
 	UserID         uint   `json:"user_id"`
+	// EntryDate нь сэтгэл санаа мэдэрсэн өдөр, хэрэглэгчийн цагийн бүсээр (YYYY-MM-DD).
+	// Хоосон бол өнөөдөр, MaxBackdateDays хоногоос өмнөх байж болохгүй.
+	EntryDate string `json:"entry_date"`
+	// Emotions нь Plutchik-ийн дугуйн дээр тэмдэглэсэн сэтгэл хөдлөлүүд, тус бүр өөрийн эрчимтэй.
+	// Өгсөн бол mood_unit_id, intensity-г хамгийн хүчтэй сэтгэл хөдлөлөөс тооцож болно.
+	Emotions []EmotionIntensityForm `json:"emotions"`
+	// TriggerTags, CopingTags нь autocomplete-оос сонгосон шошгууд. Хоосон бол trigger_event,
+	// coping_strategy текстээс таслалаар салгана.
+	TriggerTags []string `json:"trigger_tags"`
+	CopingTags  []string `json:"coping_tags"`
+}
+
+// EmotionIntensityForm нь нэг Plutchik сэтгэл хөдлөл, түүний эрчим (1-10)
+type EmotionIntensityForm struct {
+	PlutchikEmotionID int `json:"plutchik_emotion_id"`
+	Intensity         int `json:"intensity"`
+}
+
+// MaxBackdateDays нь бүртгэлийг хэдэн хоногийн өмнөх өдрөөр нөхөж оруулж болох
+const MaxBackdateDays = 7
+
+// MaxCheckInEmotions нь нэг бүртгэлд сонгох сэтгэл хөдлөлийн дээд тоо (Plutchik-ийн 8 үндсэн)
+const MaxCheckInEmotions = 8
+
+// Dominant нь хамгийн өндөр эрчимтэй сэтгэл хөдлөлийг буцаана (тэнцвэл эхнийх).
+func (f MoodEntryForm) Dominant() EmotionIntensityForm {
+	var dominant EmotionIntensityForm
+	for _, emotion := range f.Emotions {
+		if emotion.Intensity > dominant.Intensity {
+			dominant = emotion
+		}
+	}
+	return dominant
+}
+
+// ValidateEmotions нь сэтгэл хөдлөлүүдийн эрчим, давхардлыг шалгана.
+func (f MoodEntryForm) ValidateEmotions() error {
+	if len(f.Emotions) > MaxCheckInEmotions {
+		return fmt.Errorf("emotions %d-оос ихгүй байх ёстой", MaxCheckInEmotions)
+	}
+	seen := map[int]bool{}
+	for _, emotion := range f.Emotions {
+		if emotion.PlutchikEmotionID <= 0 {
+			return fmt.Errorf("plutchik_emotion_id шаардлагатай")
+		}
+		if emotion.Intensity < 1 || emotion.Intensity > 10 {
+			return fmt.Errorf("сэтгэл хөдлөлийн intensity 1-10 хооронд байх ёстой")
+		}
+		if seen[emotion.PlutchikEmotionID] {
+			return fmt.Errorf("plutchik_emotion_id %d давхардсан байна", emotion.PlutchikEmotionID)
+		}
+		seen[emotion.PlutchikEmotionID] = true
+	}
+	return nil
 }
 
 func (f MoodEntryForm) Validate() error {
-	if f.MoodUnitId == 0 {
-		return fmt.Errorf("mood_unit_id шаардлагатай")
+	if err := f.ValidateEmotions(); err != nil {
+		return err
+	}
+	if len(f.Emotions) > 0 && f.Intensity == 0 {
+		f.Intensity = f.Dominant().Intensity
+	}
+
+	if f.MoodUnitId == 0 && len(f.Emotions) == 0 {
+		return fmt.Errorf("mood_unit_id эсвэл emotions шаардлагатай")
 	}
 
 	if f.CorevalueID == 0 {
@@ -34,7 +95,29 @@ func (f MoodEntryForm) Validate() error {
 	return nil
 }
 
-func NewMoodEntryFromForm(f MoodEntryForm) *model.MoodEntries {
+// EntryDay нь entry_date-ийг шалгаж огноо (UTC шөнө дунд) буцаана. now нь хэрэглэгчийн
+// цагийн бүсээрх одоогийн цаг, entry_date хоосон бол түүний өдөр.
+func (f MoodEntryForm) EntryDay(now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if f.EntryDate == "" {
+		return today, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, f.EntryDate)
+	if err != nil {
+		return day, fmt.Errorf("entry_date YYYY-MM-DD хэлбэртэй байх ёстой")
+	}
+	if day.After(today) {
+		return day, fmt.Errorf("entry_date ирээдүйн өдөр байж болохгүй")
+	}
+	if day.Before(today.AddDate(0, 0, -MaxBackdateDays)) {
+		return day, fmt.Errorf("entry_date %d хоногоос өмнөх байж болохгүй", MaxBackdateDays)
+	}
+	return day, nil
+}
+
+// NewMoodEntryFromForm нь маягтаас бүртгэл үүсгэнэ. entryDate нь EntryDay-ээс гарсан огноо.
+func NewMoodEntryFromForm(f MoodEntryForm, entryDate time.Time) *model.MoodEntries {
 	return &model.MoodEntries{
 		UserID:         f.UserID,
 		MoodUnitID:     f.MoodUnitId,
@@ -46,6 +129,6 @@ func NewMoodEntryFromForm(f MoodEntryForm) *model.MoodEntries {
 		Location:       f.Location,
 		Weather:        f.Weather,
 		CoreValueID:    int64(f.CorevalueID),
-		EntryDate:      time.Now(),
+		EntryDate:      entryDate,
 	}
 }
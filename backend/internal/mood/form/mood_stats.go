@@ -0,0 +1,91 @@
+package form
+
+import (
+	"fmt"
+	"time"
+)
+
+// Статистикийн бүлэглэх нэгж (Postgres date_trunc-ийн утга)
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+const (
+	// DefaultStatsDays нь from, to өгөөгүй үед сүүлийн хэдэн хоногийг авах
+	DefaultStatsDays = 30
+	// MaxStatsDays нь нэг хүсэлтэд авах хамгийн урт хугацаа
+	MaxStatsDays = 366
+)
+
+// MoodStatsForm нь сэтгэл санааны статистикийн шүүлтүүр.
+// - Bucket: day, week, month (анхны утга day)
+// - From, To: Хэрэглэгчийн цагийн бүсээрх огноо (YYYY-MM-DD, хоёр тал нь багтана).
+// Хоосон бол өнөөдрийг дуусгавар болгон сүүлийн DefaultStatsDays хоног.
+type MoodStatsForm struct {
+	Bucket string
+	From   string
+	To     string
+}
+
+// Range нь now (хэрэглэгчийн цагийн бүсээр)-оос хамааран [from, to) хязгаарыг буцаана.
+// to нь дараагийн өдрийн эхлэл. Bucket хоосон бол анхны утга онооно.
+func (f *MoodStatsForm) Range(now time.Time) (from, to time.Time, err error) {
+	switch f.Bucket {
+	case "":
+		f.Bucket = BucketDay
+	case BucketDay, BucketWeek, BucketMonth:
+	default:
+		return from, to, fmt.Errorf("bucket нь %s, %s эсвэл %s байх ёстой", BucketDay, BucketWeek, BucketMonth)
+	}
+
+	return dayRange(f.From, f.To, now)
+}
+
+// dayRange нь YYYY-MM-DD хэлбэрийн from, to-г [from, to) болгоно. Хоосон бол now-г дуусгавар
+// болгосон сүүлийн DefaultStatsDays хоног.
+func dayRange(fromValue, toValue string, now time.Time) (from, to time.Time, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to = today.AddDate(0, 0, 1)
+	if toValue != "" {
+		if to, err = time.Parse(time.DateOnly, toValue); err != nil {
+			return from, to, fmt.Errorf("to YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	from = to.AddDate(0, 0, -DefaultStatsDays)
+	if fromValue != "" {
+		if from, err = time.Parse(time.DateOnly, fromValue); err != nil {
+			return from, to, fmt.Errorf("from YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("to нь from-оос өмнө байж болохгүй")
+	}
+	if to.Sub(from) > MaxStatsDays*24*time.Hour {
+		return from, to, fmt.Errorf("хугацаа %d хоногоос урт байж болохгүй", MaxStatsDays)
+	}
+	return from, to, nil
+}
+
+// MoodWheelForm нь хугацааны нэгтгэсэн Plutchik дугуйн шүүлтүүр (MoodStatsForm-той ижил огноо).
+type MoodWheelForm struct {
+	From string
+	To   string
+}
+
+func (f *MoodWheelForm) Range(now time.Time) (from, to time.Time, err error) {
+	return dayRange(f.From, f.To, now)
+}
+
+// MoodCalendarForm нь хуанли (year-in-pixels)-ийн хугацаа. Өгөөгүй бол сүүлийн DefaultStatsDays хоног.
+type MoodCalendarForm struct {
+	From string
+	To   string
+}
+
+func (f *MoodCalendarForm) Range(now time.Time) (from, to time.Time, err error) {
+	return dayRange(f.From, f.To, now)
+}
@@ -0,0 +1,112 @@
+package form
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Шошгын төрөл (mood_tags.kind)
+const (
+	TagKindTrigger = "trigger"
+	TagKindCoping  = "coping"
+)
+
+const (
+	// MaxTagsPerKind нь нэг бүртгэлд төрөл тус бүрээс хадгалах шошгын дээд тоо
+	MaxTagsPerKind = 5
+	// MaxTagLength нь шошгын нэрийн урт (тэмдэгтээр), mood_tags.name баганатай ижил
+	MaxTagLength = 100
+	// DefaultTagLimit, MaxTagLimit нь autocomplete-ийн үр дүнгийн тоо
+	DefaultTagLimit = 10
+	MaxTagLimit     = 50
+	// DefaultAnalysisDays нь trigger, coping шинжилгээнд авах сүүлийн хоногууд
+	DefaultAnalysisDays = 90
+)
+
+// NormalizeTag нь шошгыг харьцуулах хэлбэрт оруулна: илүү зайг хасч, жижиг үсэг болгоно.
+// 0010_mood_tags.sql-ийн backfill-тэй ижил дүрэм.
+func NormalizeTag(name string) string {
+	return strings.ToLower(cleanTag(name))
+}
+
+func cleanTag(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		name = strings.TrimSpace(string([]rune(name)[:MaxTagLength]))
+	}
+	return name
+}
+
+// SplitTags нь чөлөөт текстийг таслал, цэгтэй таслал, шинэ мөрөөр хувааж шошго болгоно.
+func SplitTags(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+}
+
+// Tags нь бүртгэлийн тухайн төрлийн шошгуудыг буцаана. trigger_tags, coping_tags өгөөгүй бол
+// trigger_event, coping_strategy текстээс салгана. Давхардлыг (normalized-аар) хасч, MaxTagsPerKind хүртэл авна.
+func (f MoodEntryForm) Tags(kind string) []string {
+	names, text := f.TriggerTags, f.TriggerEvent
+	if kind == TagKindCoping {
+		names, text = f.CopingTags, f.CopingStrategy
+	}
+	if len(names) == 0 {
+		names = SplitTags(text)
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = cleanTag(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, name)
+		if len(tags) == MaxTagsPerKind {
+			break
+		}
+	}
+	return tags
+}
+
+// MoodTagQuery нь шошгын autocomplete-ийн шүүлтүүр.
+// - Kind: trigger эсвэл coping (хоосон бол хоёулаа)
+// - Query: normalized нэрийн эхлэл
+type MoodTagQuery struct {
+	Kind  string
+	Query string
+	Limit int
+}
+
+func (q *MoodTagQuery) Validate() error {
+	if q.Kind != "" && q.Kind != TagKindTrigger && q.Kind != TagKindCoping {
+		return fmt.Errorf("kind нь %s эсвэл %s байх ёстой", TagKindTrigger, TagKindCoping)
+	}
+	if q.Limit < 1 {
+		q.Limit = DefaultTagLimit
+	}
+	if q.Limit > MaxTagLimit {
+		q.Limit = MaxTagLimit
+	}
+	q.Query = NormalizeTag(q.Query)
+	return nil
+}
+
+// MoodAnalysisForm нь trigger, coping шинжилгээний хугацаа (сүүлийн Days хоног).
+type MoodAnalysisForm struct {
+	Days int
+}
+
+func (f *MoodAnalysisForm) Validate() error {
+	if f.Days == 0 {
+		f.Days = DefaultAnalysisDays
+	}
+	if f.Days < 1 || f.Days > MaxStatsDays {
+		return fmt.Errorf("days 1-%d хооронд байх ёстой", MaxStatsDays)
+	}
+	return nil
+}
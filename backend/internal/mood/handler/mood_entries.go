@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"mindsteps/internal/auth"
 	"mindsteps/internal/mood/form"
 	"mindsteps/internal/mood/service"
@@ -58,7 +59,11 @@ func (h *MoodEntryHandler) GetByID(c *fiber.Ctx) error {
 		return shared.ResponseForbidden(c)
 	}
 
-	return c.JSON(entry)
+	checkIn, err := h.service.CheckIn(entry)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+	return c.JSON(checkIn)
 }
 
 func (h *MoodEntryHandler) Update(c *fiber.Ctx) error {
@@ -84,11 +89,11 @@ func (h *MoodEntryHandler) Update(c *fiber.Ctx) error {
 		return shared.ResponseForbidden(c)
 	}
 
-	entry, err = h.service.Update(uint(id), &f)
+	checkIn, err := h.service.Update(uint(id), &f)
 	if err != nil {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
-	return c.JSON(entry)
+	return c.JSON(checkIn)
 }
 
 func (h *MoodEntryHandler) Delete(c *fiber.Ctx) error {
@@ -136,15 +141,20 @@ func (h *MoodEntryHandler) ListByUserID(c *fiber.Ctx) error {
 	})
 }
 
-func (h *MoodEntryHandler) GetStatistics(c *fiber.Ctx) error {
+// Statistics: ?bucket=day|week|month&from=YYYY-MM-DD&to=YYYY-MM-DD (хэрэглэгчийн цагийн бүсээр)
+func (h *MoodEntryHandler) Statistics(c *fiber.Ctx) error {
 	tokenInfo := auth.GetTokenInfo(c)
 	if tokenInfo == nil {
 		return shared.ResponseUnauthorized(c)
 	}
 
-	days := c.QueryInt("days", 30)
+	f := form.MoodStatsForm{
+		Bucket: c.Query("bucket"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
 
-	stats, err := h.service.GetStatistics(tokenInfo.UserID, days)
+	stats, err := h.service.Statistics(tokenInfo.UserID, &f)
 	if err != nil {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
@@ -152,6 +162,127 @@ func (h *MoodEntryHandler) GetStatistics(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
+// Wheel: ?from=YYYY-MM-DD&to=YYYY-MM-DD (хэрэглэгчийн цагийн бүсээр) хугацааны Plutchik дугуй
+func (h *MoodEntryHandler) Wheel(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	f := form.MoodWheelForm{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	wheel, err := h.service.Wheel(tokenInfo.UserID, &f)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(wheel)
+}
+
+// Calendar: ?from=YYYY-MM-DD&to=YYYY-MM-DD (хэрэглэгчийн цагийн бүсээр, дээд тал нь 366 хоног)
+func (h *MoodEntryHandler) Calendar(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	f := form.MoodCalendarForm{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	calendar, err := h.service.Calendar(tokenInfo.UserID, &f)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(calendar)
+}
+
+// CalendarDay: /calendar/:date (YYYY-MM-DD) өдрийн бүртгэл, тэмдэглэл, бясалгал, хичээлүүд
+func (h *MoodEntryHandler) CalendarDay(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	detail, err := h.service.CalendarDay(tokenInfo.UserID, c.Params("date"))
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(detail)
+}
+
+// Tags: ?kind=trigger|coping&q=&limit= хэрэглэгчийн өмнө ашигласан шошгуудаас autocomplete
+func (h *MoodEntryHandler) Tags(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	q := form.MoodTagQuery{
+		Kind:  c.Query("kind"),
+		Query: c.Query("q"),
+		Limit: c.QueryInt("limit", form.DefaultTagLimit),
+	}
+
+	tags, err := h.service.Tags(tokenInfo.UserID, &q)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(tags)
+}
+
+// Analysis: ?days=90 trigger-үүд сөрөг сэтгэл хөдлөлтэй, coping-ууд дараагийн эрчмийн бууралттай хэр холбоотой
+func (h *MoodEntryHandler) Analysis(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	f := form.MoodAnalysisForm{Days: c.QueryInt("days", form.DefaultAnalysisDays)}
+
+	analysis, err := h.service.Analysis(tokenInfo.UserID, &f)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(analysis)
+}
+
+// EntryAnalysis: /:id/analysis бүртгэлийн ai_mood_analysis-д хадгалагдсан trigger, coping оноо
+func (h *MoodEntryHandler) EntryAnalysis(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.ResponseBadRequest(c, "Invalid ID")
+	}
+
+	entry, err := h.service.GetByID(uint(id))
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	tokenInfo := auth.GetTokenInfo(c)
+	if entry.UserID != tokenInfo.UserID {
+		return shared.ResponseForbidden(c)
+	}
+
+	analysis, err := h.service.EntryAnalysis(entry.ID)
+	if errors.Is(err, service.ErrMoodAnalysisNotFound) {
+		return shared.ResponseNotFound(c)
+	}
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(analysis)
+}
+
 func (h *MoodEntryHandler) MoodCategories(c *fiber.Ctx) error {
 	moodId, err := h.service.ListByMoodID()
 
@@ -160,3 +291,47 @@ func (h *MoodEntryHandler) MoodCategories(c *fiber.Ctx) error {
 	}
 	return c.JSON(moodId)
 }
+
+// SystemListByUserID: /system/users/:userId/mood-entries?page=&limit= API key-ээр хэрэглэгчийн бүртгэлүүд
+func (h *MoodEntryHandler) SystemListByUserID(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil || userID == 0 {
+		return shared.ResponseBadRequest(c, "userId буруу байна")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	entries, total, err := h.service.ListByUserID(uint(userID), page, limit)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+		"entries": entries,
+	})
+}
+
+// SystemStatistics: /system/users/:userId/mood-stats?bucket=&from=&to= API key-ээр хэрэглэгчийн статистик
+func (h *MoodEntryHandler) SystemStatistics(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil || userID == 0 {
+		return shared.ResponseBadRequest(c, "userId буруу байна")
+	}
+
+	f := form.MoodStatsForm{
+		Bucket: c.Query("bucket"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+
+	stats, err := h.service.Statistics(uint(userID), &f)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(stats)
+}
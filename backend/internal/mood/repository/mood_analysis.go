@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"time"
+
+	"mindsteps/database/model"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NegativeEmotions нь сөрөг гэж тооцох Plutchik үндсэн сэтгэл хөдлөлүүд (name_en, жижиг үсгээр).
+var NegativeEmotions = []string{"sadness", "fear", "anger", "disgust"}
+
+// negativeEntry нь бүртгэл (me) сөрөг эсэх: mood unit-ийн сэтгэл хөдлөл, хослолын бүрэлдэхүүн
+// эсвэл дугуй дээр тэмдэглэсэн сэтгэл хөдлөлийн аль нэгний үндсэн сэтгэл хөдлөл нь сөрөг.
+var negativeEntry = `EXISTS (
+	SELECT 1 FROM (
+		SELECT mu.plutchik_id AS emotion_id FROM ` + model.TableNameMoodUnit + ` mu WHERE mu.id = me.mood_unit_id
+		UNION ALL
+		SELECT unnest(ARRAY[pc.emotion1_id, pc.emotion2_id]) FROM ` + model.TableNameMoodUnit + ` mu
+		JOIN ` + model.TableNamePlutchikCombinations + ` pc ON pc.id = mu.combination_id WHERE mu.id = me.mood_unit_id
+		UNION ALL
+		SELECT w.plutchik_emotion_id FROM ` + model.TableNameUserEmotionWheel + ` w WHERE w.mood_entry_id = me.id
+	) em
+	JOIN ` + model.TableNamePlutchikEmotions + ` pe ON pe.id = em.emotion_id
+	JOIN ` + model.TableNamePlutchikEmotions + ` p ON p.id = coalesce(nullif(pe.base_emotion_id, 0), pe.id)
+	WHERE lower(p.name_en) IN @negative
+)`
+
+// analysisEntries нь шинжилгээний хугацааны бүртгэлүүд, сөрөг эсэхийн хамт (CTE).
+var analysisEntries = `entries AS (
+	SELECT me.id, me.created_at, me.intensity, ` + negativeEntry + ` AS negative
+	FROM ` + model.TableNameMoodEntries + ` me
+	WHERE me.user_id = @user_id AND me.created_at >= @since
+)`
+
+// copingFollowUpWindow нь coping хэрэглэсний дараах бүртгэлийг хүлээх хугацаа
+const copingFollowUpWindow = "24 hours"
+
+// AnalysisQuery нь нэг хэрэглэгчийн Since-ээс хойших бүртгэлүүд.
+type AnalysisQuery struct {
+	UserID uint
+	Since  time.Time
+}
+
+func (q AnalysisQuery) params() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":  q.UserID,
+		"since":    q.Since,
+		"negative": NegativeEmotions,
+	}
+}
+
+// AnalysisBaseline нь хэрэглэгчийн нийт болон сөрөг бүртгэлийн тоо (trigger-ийг харьцуулах суурь).
+type AnalysisBaseline struct {
+	Entries         int64 `json:"entries"`
+	NegativeEntries int64 `json:"negative_entries"`
+}
+
+// TagStat нь нэг шошгын нэгтгэл. FollowUps, Improved, AverageDrop нь зөвхөн coping-д:
+// сөрөг бүртгэлийн дараах 24 цагт хийсэн дараагийн бүртгэл, түүнд сөрөг биш болсон эсвэл эрчим буурсан тоо.
+type TagStat struct {
+	TagID            uint    `json:"tag_id"`
+	Kind             string  `json:"kind"`
+	Name             string  `json:"name"`
+	Uses             int64   `json:"uses"`
+	NegativeUses     int64   `json:"negative_uses"`
+	AverageIntensity float64 `json:"average_intensity"`
+	FollowUps        int64   `json:"follow_ups"`
+	Improved         int64   `json:"improved"`
+	AverageDrop      float64 `json:"average_drop"`
+}
+
+type EntryTagLink struct {
+	MoodEntryID uint
+	TagID       uint
+}
+
+// EntryScore нь ai_mood_analysis-д бичих бүртгэлийн оноо. Шошгогүй бол оноо nil (NULL).
+type EntryScore struct {
+	MoodEntryID  uint
+	UserID       uint
+	TriggerScore *int
+	CopingScore  *int
+	Patterns     datatypes.JSON
+}
+
+func (r *moodEntryRepo) AnalysisBaseline(q AnalysisQuery) (AnalysisBaseline, error) {
+	var baseline AnalysisBaseline
+	err := r.db.Raw(`WITH `+analysisEntries+`
+SELECT count(*) AS entries, count(*) FILTER (WHERE negative) AS negative_entries
+FROM entries`, q.params()).Scan(&baseline).Error
+	return baseline, err
+}
+
+func (r *moodEntryRepo) TagStats(q AnalysisQuery) ([]TagStat, error) {
+	stats := []TagStat{}
+	err := r.db.Raw(`WITH `+analysisEntries+`, tagged AS (
+	SELECT t.id AS tag_id, t.kind, t.name, e.id, e.created_at, e.intensity, e.negative
+	FROM entries e
+	JOIN `+model.TableNameMoodEntryTags+` met ON met.mood_entry_id = e.id
+	JOIN `+model.TableNameMoodTags+` t ON t.id = met.tag_id
+)
+SELECT tg.tag_id, tg.kind, tg.name,
+	count(*) AS uses,
+	count(*) FILTER (WHERE tg.negative) AS negative_uses,
+	avg(tg.intensity)::float8 AS average_intensity,
+	count(n.id) AS follow_ups,
+	count(n.id) FILTER (WHERE NOT n.negative OR n.intensity < tg.intensity) AS improved,
+	coalesce(avg(tg.intensity - n.intensity), 0)::float8 AS average_drop
+FROM tagged tg
+LEFT JOIN LATERAL (
+	SELECT e2.id, e2.intensity, e2.negative FROM entries e2
+	WHERE e2.created_at > tg.created_at AND e2.created_at <= tg.created_at + interval '`+copingFollowUpWindow+`'
+	ORDER BY e2.created_at, e2.id
+	LIMIT 1
+) n ON tg.kind = 'coping' AND tg.negative
+GROUP BY tg.tag_id, tg.kind, tg.name
+ORDER BY uses DESC, tg.tag_id`, q.params()).Scan(&stats).Error
+	return stats, err
+}
+
+// EntryTagLinks нь хугацааны бүртгэл бүрийн шошгууд. Шошгогүй ч өмнө нь шинжлэгдсэн бүртгэл
+// TagID = 0-оор орно (оноог нь арилгахын тулд).
+func (r *moodEntryRepo) EntryTagLinks(q AnalysisQuery) ([]EntryTagLink, error) {
+	var links []EntryTagLink
+	err := r.db.Raw(`SELECT me.id AS mood_entry_id, coalesce(met.tag_id, 0) AS tag_id
+FROM `+model.TableNameMoodEntries+` me
+LEFT JOIN `+model.TableNameMoodEntryTags+` met ON met.mood_entry_id = me.id
+WHERE me.user_id = @user_id AND me.created_at >= @since
+	AND (met.tag_id IS NOT NULL OR EXISTS (SELECT 1 FROM `+model.TableNameAIMoodAnalysis+` a WHERE a.mood_entry_id = me.id))
+ORDER BY me.id, met.tag_id`, q.params()).Scan(&links).Error
+	return links, err
+}
+
+// SaveAnalyses нь ai_mood_analysis-ийн trigger, coping оноог бүртгэл тутамд upsert хийнэ.
+// detected_patterns-ийн бусад түлхүүрүүд хадгалагдана.
+func (r *moodEntryRepo) SaveAnalyses(scores []EntryScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, score := range scores {
+			if err := tx.Exec(`INSERT INTO `+model.TableNameAIMoodAnalysis+` AS a
+	(mood_entry_id, user_id, trigger_pattern_score, coping_effectiveness_score, detected_patterns)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (mood_entry_id) DO UPDATE SET
+	trigger_pattern_score = EXCLUDED.trigger_pattern_score,
+	coping_effectiveness_score = EXCLUDED.coping_effectiveness_score,
+	detected_patterns = coalesce(a.detected_patterns, '{}'::jsonb) || EXCLUDED.detected_patterns`,
+				score.MoodEntryID, score.UserID, score.TriggerScore, score.CopingScore, score.Patterns).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *moodEntryRepo) FindAnalysis(entryID uint) (*model.AIMoodAnalysis, error) {
+	var analysis model.AIMoodAnalysis
+	err := r.db.Select("id", "mood_entry_id", "user_id", "trigger_pattern_score", "coping_effectiveness_score", "detected_patterns", "created_at").
+		Where("mood_entry_id = ?", entryID).
+		First(&analysis).Error
+	if err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// UsersWithEntriesSince нь since-ээс хойш бүртгэл хийсэн хэрэглэгчид (өдөр тутмын шинжилгээнд).
+func (r *moodEntryRepo) UsersWithEntriesSince(since time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&model.MoodEntries{}).
+		Where("created_at >= ?", since).
+		Distinct("user_id").
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"mindsteps/database/model"
+)
+
+// CalendarDay нь нэг өдрийн (хэрэглэгчийн цагаар) нэгтгэл. Давамгайлсан mood unit нь тухайн
+// өдөр хамгийн олон бүртгэгдсэн нь (тэнцвэл эрчим өндөр, дараа нь сүүлд бүртгэгдсэн нь).
+type CalendarDay struct {
+	Day              time.Time
+	Entries          int64
+	AverageIntensity float64
+	MoodUnitID       uint
+	DisplayNameEn    string
+	DisplayNameMn    string
+	DisplayColor     string
+	DisplayEmoji     string
+}
+
+// DayJournal нь өдрийн дэлгэрэнгүйд харуулах тэмдэглэлийн товч (агуулгагүй).
+type DayJournal struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	WordCount      int       `json:"word_count"`
+	SentimentScore float64   `json:"sentiment_score"`
+	Tags           string    `json:"tags"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// DayLesson нь тухайн өдөр дууссан хичээл.
+type DayLesson struct {
+	LessonID       uint      `json:"lesson_id"`
+	Title          string    `json:"title"`
+	Slug           string    `json:"slug"`
+	TimeSpent      int       `json:"time_spent"`
+	Rating         *int      `json:"rating"`
+	CompletionDate time.Time `json:"completion_date"`
+}
+
+func (r *moodEntryRepo) CalendarDays(q StatsQuery) ([]CalendarDay, error) {
+	var days []CalendarDay
+	err := r.db.Raw(`WITH entries AS (
+	SELECT me.entry_date AS day, me.mood_unit_id, me.intensity, me.created_at
+	FROM `+model.TableNameMoodEntries+` me
+	WHERE `+statsWhere+`
+), days AS (
+	SELECT day, count(*) AS entries, avg(intensity)::float8 AS average_intensity
+	FROM entries
+	GROUP BY day
+), units AS (
+	SELECT day, mood_unit_id,
+		row_number() OVER (PARTITION BY day ORDER BY count(*) DESC, avg(intensity) DESC, max(created_at) DESC) AS rank
+	FROM entries
+	GROUP BY day, mood_unit_id
+)
+SELECT d.day, d.entries, d.average_intensity,
+	mu.id AS mood_unit_id, mu.display_name_en, mu.display_name_mn, mu.display_color, mu.display_emoji
+FROM days d
+JOIN units u ON u.day = d.day AND u.rank = 1
+JOIN `+model.TableNameMoodUnit+` mu ON mu.id = u.mood_unit_id
+ORDER BY d.day`, q.params()).Scan(&days).Error
+	return days, err
+}
+
+// DayEntries нь q-ийн хугацаан дахь (нэг өдөр) бүртгэлүүдийг цагийн дарааллаар буцаана.
+func (r *moodEntryRepo) DayEntries(q StatsQuery) ([]model.MoodEntries, error) {
+	var entries []model.MoodEntries
+	err := r.db.Table(model.TableNameMoodEntries+" me").
+		Where(statsWhere, q.params()).
+		Preload("MoodUnit").
+		Order("me.created_at").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *moodEntryRepo) DayJournals(q StatsQuery) ([]DayJournal, error) {
+	var journals []DayJournal
+	err := r.db.Raw(`SELECT j.id, j.title, j.word_count, coalesce(j.sentiment_score, 0)::float8 AS sentiment_score, j.tags, j.created_at
+FROM `+model.TableNameJournals+` j
+WHERE `+rangeWhere("j.user_id", "j.created_at")+` AND j.deleted_at IS NULL
+ORDER BY j.created_at`, q.params()).Scan(&journals).Error
+	return journals, err
+}
+
+// DayMeditations: start_time байвал түүний өдрөөр, үгүй бол session_date-аар шүүнэ.
+func (r *moodEntryRepo) DayMeditations(q StatsQuery) ([]model.MeditationSessions, error) {
+	var sessions []model.MeditationSessions
+	err := r.db.Table(model.TableNameMeditationSessions+" ms").
+		Where(`ms.user_id = @user_id AND CASE WHEN ms.start_time IS NOT NULL
+	THEN `+localTime("ms.start_time")+`::date ELSE ms.session_date END = CAST(@from AS date)`, q.params()).
+		Preload("Technique").
+		Order("ms.start_time NULLS LAST, ms.id").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *moodEntryRepo) DayLessons(q StatsQuery) ([]DayLesson, error) {
+	var lessons []DayLesson
+	err := r.db.Raw(`SELECT l.id AS lesson_id, l.title, l.slug, p.time_spent, p.rating, p.completion_date
+FROM `+model.TableNameUserLessonProgress+` p
+JOIN `+model.TableNameLessons+` l ON l.id = p.lesson_id
+WHERE `+rangeWhere("p.user_id", "p.completion_date")+`
+ORDER BY p.completion_date`, q.params()).Scan(&lessons).Error
+	return lessons, err
+}
@@ -16,6 +16,34 @@ type MoodEntryRepository interface {
 	CountByUserID(userID uint) (int64, error)
 	FindByDateRange(userID uint, fromDate, toDate time.Time) ([]model.MoodEntries, error)
 	ListByMoodID() ([]model.MoodCategories, error)
+	UserTimezone(userID uint) (string, error)
+	StatsSummary(q StatsQuery) (StatsSummary, error)
+	StatsTrend(q StatsQuery, bucket string) ([]StatsBucket, error)
+	StatsEmotions(q StatsQuery) ([]StatsShare, error)
+	StatsCategories(q StatsQuery) ([]StatsShare, error)
+	StatsHours(q StatsQuery) ([]StatsHour, error)
+	FindEmotions(ids []int) ([]model.PlutchikEmotions, error)
+	MoodUnitByEmotion(emotionID int) (*model.MoodUnit, error)
+	CreateWithEmotions(entry *model.MoodEntries, emotions []model.UserEmotionWheel) error
+	ReplaceEmotions(entryID uint, emotions []model.UserEmotionWheel) error
+	EntryEmotions(entryID uint) ([]model.UserEmotionWheel, error)
+	WheelEntries(q StatsQuery) (int64, error)
+	WheelEmotions(q StatsQuery) ([]WheelEmotion, error)
+	WheelDyads(q StatsQuery) ([]WheelDyad, error)
+	CalendarDays(q StatsQuery) ([]CalendarDay, error)
+	DayEntries(q StatsQuery) ([]model.MoodEntries, error)
+	DayJournals(q StatsQuery) ([]DayJournal, error)
+	DayMeditations(q StatsQuery) ([]model.MeditationSessions, error)
+	DayLessons(q StatsQuery) ([]DayLesson, error)
+	SyncEntryTags(userID, entryID uint, kind string, names []string) error
+	EntryTags(entryID uint) ([]model.MoodTags, error)
+	SearchTags(userID uint, kind, prefix string, limit int) ([]model.MoodTags, error)
+	AnalysisBaseline(q AnalysisQuery) (AnalysisBaseline, error)
+	TagStats(q AnalysisQuery) ([]TagStat, error)
+	EntryTagLinks(q AnalysisQuery) ([]EntryTagLink, error)
+	SaveAnalyses(scores []EntryScore) error
+	FindAnalysis(entryID uint) (*model.AIMoodAnalysis, error)
+	UsersWithEntriesSince(since time.Time) ([]uint, error)
 }
 
 type moodEntryRepo struct {
@@ -49,7 +77,22 @@ func (r *moodEntryRepo) Update(entry *model.MoodEntries) error {
 }
 
 func (r *moodEntryRepo) Delete(id uint) error {
-	return r.db.Delete(&model.MoodEntries{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("mood_entry_id = ?", id).Delete(&model.UserEmotionWheel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("mood_entry_id = ?", id).Delete(&model.AIMoodAnalysis{}).Error; err != nil {
+			return err
+		}
+		var tagIDs []uint
+		if err := tx.Raw(`DELETE FROM `+model.TableNameMoodEntryTags+` WHERE mood_entry_id = ? RETURNING tag_id`, id).Scan(&tagIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.MoodEntries{}, id).Error; err != nil {
+			return err
+		}
+		return recountTags(tx, tagIDs)
+	})
 }
 
 func (r *moodEntryRepo) ListByUserID(userID uint, limit int, offset int) ([]model.MoodEntries, error) {
@@ -94,7 +137,7 @@ func (r *moodEntryRepo) FindByDateRange(userID uint, fromDate, toDate time.Time)
 	var entries []model.MoodEntries
 	if err := r.db.Where("user_id = ? AND entry_date BETWEEN ? AND ?",
 		userID, fromDate, toDate).
-		Preload("Mood").
+		Preload("MoodUnit").
 		Order("entry_date ASC").
 		Find(&entries).Error; err != nil {
 		return nil, err
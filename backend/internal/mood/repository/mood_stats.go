@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"time"
+
+	"mindsteps/database/model"
+)
+
+// storedTimeZone нь timestamp without time zone баганууд (created_at г.м) хадгалагдсан бүс.
+// shared.StoredLocation (database/postgres.go-ийн TimeZone, NowFunc)-тэй ижил байх ёстой.
+const storedTimeZone = "Asia/Ulaanbaatar"
+
+// localTime нь timestamp баганыг хэрэглэгчийн цагийн бүс (@tz) рүү хөрвүүлнэ.
+func localTime(column string) string {
+	return "((" + column + " AT TIME ZONE '" + storedTimeZone + "') AT TIME ZONE @tz)"
+}
+
+// rangeWhere: хэрэглэгчийн цагаар [from, to) хязгаарт орох мөрүүд. Хөрвүүлээгүй баганын нөхцөл нь
+// index ашиглуулах зорилготой (цагийн бүсийн зөрүү 2 хоногоос хэтрэхгүй).
+func rangeWhere(userColumn, timeColumn string) string {
+	local := localTime(timeColumn)
+	return userColumn + " = @user_id" +
+		" AND " + local + " >= CAST(@from AS timestamp) AND " + local + " < CAST(@to AS timestamp)" +
+		" AND " + timeColumn + " >= CAST(@from AS timestamp) - interval '2 days' AND " + timeColumn + " < CAST(@to AS timestamp) + interval '2 days'"
+}
+
+// entry_date нь бүртгэл үүсгэх үеийн хэрэглэгчийн цагийн бүсээрх (эсвэл нөхөж оруулсан) өдөр тул
+// өдөр, долоо хоног, сараар бүлэглэхэд хөрвүүлэлтгүй ашиглана. Цагийн бүс солигдсон ч (аялал)
+// хуучин бүртгэлүүд өөр өдөр рүү шилжихгүй.
+var (
+	localEntryTime = localTime("me.created_at")
+	statsWhere     = "me.user_id = @user_id AND me.entry_date >= CAST(@from AS date) AND me.entry_date < CAST(@to AS date)"
+	// sameDayEntry нь тухайн өдөртөө хийсэн (нөхөж оруулаагүй) бүртгэл, цаг нь мэдэгдэнэ
+	sameDayEntry = localEntryTime + "::date = me.entry_date"
+)
+
+// StatsQuery нь статистикийн хүрээ. From, To нь хэрэглэгчийн цагийн бүсээрх өдрийн эхлэл, To хамаарахгүй.
+type StatsQuery struct {
+	UserID   uint
+	Timezone string
+	From     time.Time
+	To       time.Time
+}
+
+func (q StatsQuery) params() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id": q.UserID,
+		"tz":      q.Timezone,
+		"from":    q.From.Format(time.DateOnly),
+		"to":      q.To.Format(time.DateOnly),
+	}
+}
+
+type StatsSummary struct {
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+	ActiveDays       int64   `json:"active_days"`
+}
+
+// StatsBucket нь нэг өдөр, долоо хоног эсвэл сарын нэгтгэл. Bucket нь хугацааны эхлэл.
+type StatsBucket struct {
+	Bucket           time.Time `json:"bucket"`
+	Entries          int64     `json:"entries"`
+	AverageIntensity float64   `json:"average_intensity"`
+	MinIntensity     int       `json:"min_intensity"`
+	MaxIntensity     int       `json:"max_intensity"`
+}
+
+// StatsShare нь Plutchik үндсэн сэтгэл хөдлөл эсвэл mood category-ийн эзлэх хэмжээ.
+type StatsShare struct {
+	ID               int     `json:"id"`
+	NameEn           string  `json:"name_en"`
+	NameMn           string  `json:"name_mn"`
+	Color            string  `json:"color"`
+	Emoji            string  `json:"emoji"`
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+}
+
+type StatsHour struct {
+	Hour             int     `json:"hour"`
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+}
+
+func (r *moodEntryRepo) UserTimezone(userID uint) (string, error) {
+	var timezone string
+	err := r.db.Model(&model.Users{}).Where("id = ?", userID).Select("timezone").Scan(&timezone).Error
+	return timezone, err
+}
+
+func (r *moodEntryRepo) StatsSummary(q StatsQuery) (StatsSummary, error) {
+	var summary StatsSummary
+	err := r.db.Raw(`SELECT count(*) AS entries,
+	coalesce(avg(me.intensity), 0)::float8 AS average_intensity,
+	count(DISTINCT me.entry_date) AS active_days
+FROM `+model.TableNameMoodEntries+` me
+WHERE `+statsWhere, q.params()).Scan(&summary).Error
+	return summary, err
+}
+
+// StatsTrend нь bucket (day, week, month)-аар бүлэглэсэн эрчмийн өөрчлөлт. Бичлэггүй bucket ордоггүй.
+func (r *moodEntryRepo) StatsTrend(q StatsQuery, bucket string) ([]StatsBucket, error) {
+	params := q.params()
+	params["bucket"] = bucket
+
+	var buckets []StatsBucket
+	err := r.db.Raw(`SELECT date_trunc(@bucket, me.entry_date::timestamp) AS bucket,
+	count(*) AS entries,
+	avg(me.intensity)::float8 AS average_intensity,
+	min(me.intensity) AS min_intensity,
+	max(me.intensity) AS max_intensity
+FROM `+model.TableNameMoodEntries+` me
+WHERE `+statsWhere+`
+GROUP BY 1
+ORDER BY 1`, params).Scan(&buckets).Error
+	return buckets, err
+}
+
+// StatsEmotions нь бичлэгийг mood unit-ийн Plutchik сэтгэл хөдлөлийн үндсэн (base) сэтгэл хөдлөлөөр
+// бүлэглэнэ. Хослол (dyad) mood unit нь хоёр бүрэлдэхүүн сэтгэл хөдлөл тус бүрд тоологдоно.
+func (r *moodEntryRepo) StatsEmotions(q StatsQuery) ([]StatsShare, error) {
+	var shares []StatsShare
+	err := r.db.Raw(`WITH entries AS (
+	SELECT me.intensity, mu.plutchik_id, mu.combination_id
+	FROM `+model.TableNameMoodEntries+` me
+	JOIN `+model.TableNameMoodUnit+` mu ON mu.id = me.mood_unit_id
+	WHERE `+statsWhere+`
+), emotions AS (
+	SELECT e.plutchik_id AS emotion_id, e.intensity FROM entries e WHERE e.plutchik_id IS NOT NULL
+	UNION ALL
+	SELECT pc.emotion1_id, e.intensity FROM entries e
+	JOIN `+model.TableNamePlutchikCombinations+` pc ON pc.id = e.combination_id WHERE e.plutchik_id IS NULL
+	UNION ALL
+	SELECT pc.emotion2_id, e.intensity FROM entries e
+	JOIN `+model.TableNamePlutchikCombinations+` pc ON pc.id = e.combination_id WHERE e.plutchik_id IS NULL
+)
+SELECT p.id, p.name_en, p.name_mn, p.color, p.emoji,
+	count(*) AS entries,
+	avg(em.intensity)::float8 AS average_intensity
+FROM emotions em
+JOIN `+model.TableNamePlutchikEmotions+` pe ON pe.id = em.emotion_id
+JOIN `+model.TableNamePlutchikEmotions+` p ON p.id = coalesce(nullif(pe.base_emotion_id, 0), pe.id)
+GROUP BY p.id, p.name_en, p.name_mn, p.color, p.emoji
+ORDER BY entries DESC, p.id`, q.params()).Scan(&shares).Error
+	return shares, err
+}
+
+func (r *moodEntryRepo) StatsCategories(q StatsQuery) ([]StatsShare, error) {
+	var shares []StatsShare
+	err := r.db.Raw(`SELECT mc.id, mc.name_en, mc.name_mn, mc.color, mc.emoji,
+	count(*) AS entries,
+	avg(me.intensity)::float8 AS average_intensity
+FROM `+model.TableNameMoodEntries+` me
+JOIN `+model.TableNameMoodUnit+` mu ON mu.id = me.mood_unit_id
+JOIN `+model.TableNameMoodCategories+` mc ON mc.id = mu.category_id
+WHERE `+statsWhere+`
+GROUP BY mc.id, mc.name_en, mc.name_mn, mc.color, mc.emoji
+ORDER BY entries DESC, mc.id`, q.params()).Scan(&shares).Error
+	return shares, err
+}
+
+// StatsHours нь хэрэглэгчийн цагаарх цаг (0-23) тус бүрийн нэгтгэл. Бичлэггүй цаг ордоггүй.
+// Нөхөж оруулсан бүртгэлийн мэдэрсэн цаг тодорхойгүй тул тоологдохгүй.
+func (r *moodEntryRepo) StatsHours(q StatsQuery) ([]StatsHour, error) {
+	var hours []StatsHour
+	err := r.db.Raw(`SELECT extract(hour FROM `+localEntryTime+`)::int AS hour,
+	count(*) AS entries,
+	avg(me.intensity)::float8 AS average_intensity
+FROM `+model.TableNameMoodEntries+` me
+WHERE `+statsWhere+` AND `+sameDayEntry+`
+GROUP BY 1
+ORDER BY 1`, q.params()).Scan(&hours).Error
+	return hours, err
+}
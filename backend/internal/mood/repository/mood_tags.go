@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+
+	"gorm.io/gorm"
+)
+
+// SyncEntryTags нь бүртгэлийн тухайн төрлийн (trigger, coping) шошгуудыг names-ээр солино.
+// Хэрэглэгчийн шошго байхгүй бол үүсгэж, хөндөгдсөн шошгуудын usage_count-ийг дахин тоолно.
+func (r *moodEntryRepo) SyncEntryTags(userID, entryID uint, kind string, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var affected []uint
+		if err := tx.Raw(`DELETE FROM `+model.TableNameMoodEntryTags+` met
+USING `+model.TableNameMoodTags+` t
+WHERE t.id = met.tag_id AND met.mood_entry_id = ? AND t.kind = ?
+RETURNING met.tag_id`, entryID, kind).Scan(&affected).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, name := range names {
+			var tagID uint
+			if err := tx.Raw(`INSERT INTO `+model.TableNameMoodTags+` (user_id, kind, name, normalized, last_used_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_id, kind, normalized) DO UPDATE SET last_used_at = EXCLUDED.last_used_at
+RETURNING id`, userID, kind, name, strings.ToLower(name), now).Scan(&tagID).Error; err != nil {
+				return err
+			}
+			link := model.MoodEntryTags{MoodEntryID: entryID, TagID: tagID}
+			if err := tx.Where(link).FirstOrCreate(&link).Error; err != nil {
+				return err
+			}
+			affected = append(affected, tagID)
+		}
+		return recountTags(tx, affected)
+	})
+}
+
+// recountTags нь шошгуудын usage_count-ийг mood_entry_tags-аас дахин тооцно.
+func recountTags(tx *gorm.DB, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(`UPDATE `+model.TableNameMoodTags+` t
+SET usage_count = (SELECT count(*) FROM `+model.TableNameMoodEntryTags+` met WHERE met.tag_id = t.id)
+WHERE t.id IN ?`, tagIDs).Error
+}
+
+// EntryTags нь бүртгэлийн бүх шошгууд (trigger, coping).
+func (r *moodEntryRepo) EntryTags(entryID uint) ([]model.MoodTags, error) {
+	tags := []model.MoodTags{}
+	err := r.db.Joins("JOIN "+model.TableNameMoodEntryTags+" met ON met.tag_id = "+model.TableNameMoodTags+".id").
+		Where("met.mood_entry_id = ?", entryID).
+		Order("kind, name").
+		Find(&tags).Error
+	return tags, err
+}
+
+// SearchTags нь хэрэглэгчийн өмнө ашигласан шошгуудаас prefix (normalized)-аар хайна.
+// Олон ашигласан, сүүлд ашигласан нь эхэнд. kind хоосон бол хоёр төрлөөс.
+func (r *moodEntryRepo) SearchTags(userID uint, kind, prefix string, limit int) ([]model.MoodTags, error) {
+	tags := []model.MoodTags{}
+	query := r.db.Where("user_id = ? AND usage_count > 0", userID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if prefix != "" {
+		query = query.Where("normalized LIKE ?", escapeLike(prefix)+"%")
+	}
+	err := query.Order("usage_count DESC, last_used_at DESC, id").Limit(limit).Find(&tags).Error
+	return tags, err
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"mindsteps/database/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WheelEmotion нь хугацааны туршид нэг үндсэн (base) Plutchik сэтгэл хөдлөлийн нэгтгэл.
+type WheelEmotion struct {
+	ID               int     `json:"id"`
+	NameEn           string  `json:"name_en"`
+	NameMn           string  `json:"name_mn"`
+	Color            string  `json:"color"`
+	Emoji            string  `json:"emoji"`
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+	MaxIntensity     int     `json:"max_intensity"`
+}
+
+// WheelDyad нь илэрсэн хослол (dyad), хэдэн бүртгэлд илэрсэн тоотой.
+type WheelDyad struct {
+	ID              int    `json:"id"`
+	CombinedNameEn  string `json:"combined_name_en"`
+	CombinedNameMn  string `json:"combined_name_mn"`
+	CombinationType string `json:"combination_type"`
+	Color           string `json:"color"`
+	Emoji           string `json:"emoji"`
+	Entries         int64  `json:"entries"`
+}
+
+// wheelFrom нь дугуйн мөрүүдийг бүртгэлийн entry_date-ээр (statsWhere) шүүхэд холбоно.
+var wheelFrom = model.TableNameUserEmotionWheel + ` w
+JOIN ` + model.TableNameMoodEntries + ` me ON me.id = w.mood_entry_id`
+
+func (r *moodEntryRepo) FindEmotions(ids []int) ([]model.PlutchikEmotions, error) {
+	var emotions []model.PlutchikEmotions
+	err := r.db.Where("id IN ?", ids).Find(&emotions).Error
+	return emotions, err
+}
+
+// MoodUnitByEmotion нь тухайн Plutchik сэтгэл хөдлөлд харгалзах (хамгийн эхний) mood unit.
+func (r *moodEntryRepo) MoodUnitByEmotion(emotionID int) (*model.MoodUnit, error) {
+	var unit model.MoodUnit
+	if err := r.db.Where("plutchik_id = ?", emotionID).Order("id").First(&unit).Error; err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// CreateWithEmotions нь бүртгэл болон user_emotion_wheel мөрүүдийг нэг transaction-д хадгална.
+func (r *moodEntryRepo) CreateWithEmotions(entry *model.MoodEntries, emotions []model.UserEmotionWheel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return createEmotions(tx, entry.ID, emotions)
+	})
+}
+
+// ReplaceEmotions нь бүртгэлийн user_emotion_wheel мөрүүдийг шинээр солино.
+func (r *moodEntryRepo) ReplaceEmotions(entryID uint, emotions []model.UserEmotionWheel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("mood_entry_id = ?", entryID).Delete(&model.UserEmotionWheel{}).Error; err != nil {
+			return err
+		}
+		return createEmotions(tx, entryID, emotions)
+	})
+}
+
+func createEmotions(tx *gorm.DB, entryID uint, emotions []model.UserEmotionWheel) error {
+	for i := range emotions {
+		emotions[i].MoodEntryID = entryID
+		omit := []string{clause.Associations, "JournalID"}
+		if emotions[i].DetectedCombinationID == 0 {
+			omit = append(omit, "DetectedCombinationID")
+		}
+		if err := tx.Omit(omit...).Create(&emotions[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *moodEntryRepo) EntryEmotions(entryID uint) ([]model.UserEmotionWheel, error) {
+	var emotions []model.UserEmotionWheel
+	err := r.db.Where("mood_entry_id = ?", entryID).
+		Preload("PlutchikEmotion").
+		Preload("DetectedCombination").
+		Order("intensity DESC, id").
+		Find(&emotions).Error
+	return emotions, err
+}
+
+// WheelEntries нь хугацаанд дугуй дээр тэмдэглэсэн бүртгэлийн тоо.
+func (r *moodEntryRepo) WheelEntries(q StatsQuery) (int64, error) {
+	var count int64
+	err := r.db.Raw(`SELECT count(DISTINCT w.mood_entry_id)
+FROM `+wheelFrom+`
+WHERE `+statsWhere, q.params()).Scan(&count).Error
+	return count, err
+}
+
+// WheelEmotions нь сэтгэл хөдлөлүүдийг үндсэн (base) сэтгэл хөдлөлөөр нь бүлэглэнэ.
+func (r *moodEntryRepo) WheelEmotions(q StatsQuery) ([]WheelEmotion, error) {
+	var emotions []WheelEmotion
+	err := r.db.Raw(`SELECT p.id, p.name_en, p.name_mn, p.color, p.emoji,
+	count(DISTINCT w.mood_entry_id) AS entries,
+	avg(w.intensity)::float8 AS average_intensity,
+	max(w.intensity) AS max_intensity
+FROM `+wheelFrom+`
+JOIN `+model.TableNamePlutchikEmotions+` pe ON pe.id = w.plutchik_emotion_id
+JOIN `+model.TableNamePlutchikEmotions+` p ON p.id = coalesce(nullif(pe.base_emotion_id, 0), pe.id)
+WHERE `+statsWhere+`
+GROUP BY p.id, p.name_en, p.name_mn, p.color, p.emoji
+ORDER BY p.id`, q.params()).Scan(&emotions).Error
+	return emotions, err
+}
+
+// WheelDyads нь илэрсэн хослолуудыг (detected_combination_id) олон илэрснээс нь эхлэн буцаана.
+func (r *moodEntryRepo) WheelDyads(q StatsQuery) ([]WheelDyad, error) {
+	var dyads []WheelDyad
+	err := r.db.Raw(`SELECT pc.id, pc.combined_name_en, pc.combined_name_mn, pc.combination_type, pc.color, pc.emoji,
+	count(DISTINCT w.mood_entry_id) AS entries
+FROM `+wheelFrom+`
+JOIN `+model.TableNamePlutchikCombinations+` pc ON pc.id = w.detected_combination_id
+WHERE `+statsWhere+`
+GROUP BY pc.id, pc.combined_name_en, pc.combined_name_mn, pc.combination_type, pc.color, pc.emoji
+ORDER BY entries DESC, pc.id`, q.params()).Scan(&dyads).Error
+	return dyads, err
+}
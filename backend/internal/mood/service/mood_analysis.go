@@ -0,0 +1,260 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/mood/form"
+	"mindsteps/internal/mood/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrMoodAnalysisNotFound = errors.New("бүртгэлийн шинжилгээ хараахан хийгдээгүй байна")
+
+// confidentUses нь оноог бүрэн итгэлтэй гэж үзэх хэрэглээний тоо. Үүнээс цөөн бол оноо
+// харьцаагаараа буурна (2 удаа ашигласан шошго 100% биш).
+const confidentUses = 5
+
+// analysisLookback нь өдөр тутмын шинжилгээнд сүүлийн хэдэн цагт бүртгэл хийсэн хэрэглэгчийг авах
+const analysisLookback = 25 * time.Hour
+
+// MoodAnalysis нь /mood-entries/analysis-ийн хариу: trigger бүр сөрөг сэтгэл хөдлөлтэй хэр
+// холбоотой, coping бүрийн дараа сэтгэл санаа хэр сайжирсан.
+type MoodAnalysis struct {
+	Days            int                         `json:"days"`
+	Baseline        repository.AnalysisBaseline `json:"baseline"`
+	NegativePercent float64                     `json:"negative_percent"`
+	Triggers        []TriggerInsight            `json:"triggers"`
+	Coping          []CopingInsight             `json:"coping"`
+}
+
+// TriggerInsight: Lift нь шошготой бүртгэлийн сөрөг хувь хэрэглэгчийн ерөнхий сөрөг хувиас хэд дахин их.
+type TriggerInsight struct {
+	TagID            uint    `json:"tag_id"`
+	Name             string  `json:"name"`
+	Uses             int64   `json:"uses"`
+	NegativeUses     int64   `json:"negative_uses"`
+	NegativePercent  float64 `json:"negative_percent"`
+	AverageIntensity float64 `json:"average_intensity"`
+	Lift             float64 `json:"lift"`
+	Score            int     `json:"score"`
+}
+
+// CopingInsight: FollowUps нь сөрөг бүртгэлийн дараах 24 цагт хийсэн бүртгэл, Improved нь тэдгээрээс
+// сөрөг биш болсон эсвэл эрчим нь буурсан тоо.
+type CopingInsight struct {
+	TagID           uint    `json:"tag_id"`
+	Name            string  `json:"name"`
+	Uses            int64   `json:"uses"`
+	FollowUps       int64   `json:"follow_ups"`
+	Improved        int64   `json:"improved"`
+	ImprovedPercent float64 `json:"improved_percent"`
+	AverageDrop     float64 `json:"average_drop"`
+	Score           int     `json:"score"`
+}
+
+// tagPattern нь ai_mood_analysis.detected_patterns-д бичигдэх шошгын оноо
+type tagPattern struct {
+	TagID uint   `json:"tag_id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// analysisScore нь hits/total харьцааг 0-100 оноо болгоно, confidentUses-ээс цөөн бол бууруулна.
+func analysisScore(hits, total int64) int {
+	if total == 0 {
+		return 0
+	}
+	confidence := math.Min(1, float64(total)/confidentUses)
+	return int(math.Round(100 * float64(hits) / float64(total) * confidence))
+}
+
+func percent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round2(float64(part) * 100 / float64(total))
+}
+
+func (s *moodEntryService) Tags(userID uint, q *form.MoodTagQuery) ([]model.MoodTags, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.SearchTags(userID, q.Kind, q.Query, q.Limit)
+}
+
+// syncTags нь бүртгэлийн trigger, coping шошгуудыг маягтаас шинэчилнэ.
+func (s *moodEntryService) syncTags(entry *model.MoodEntries, f *form.MoodEntryForm) error {
+	for _, kind := range []string{form.TagKindTrigger, form.TagKindCoping} {
+		if err := s.repo.SyncEntryTags(entry.UserID, entry.ID, kind, f.Tags(kind)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Analysis нь сүүлийн f.Days хоногийн шинжилгээг тооцно (ai_mood_analysis-д бичихгүй).
+func (s *moodEntryService) Analysis(userID uint, f *form.MoodAnalysisForm) (*MoodAnalysis, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return s.analyze(repository.AnalysisQuery{UserID: userID, Since: time.Now().AddDate(0, 0, -f.Days)}, f.Days)
+}
+
+func (s *moodEntryService) analyze(q repository.AnalysisQuery, days int) (*MoodAnalysis, error) {
+	baseline, err := s.repo.AnalysisBaseline(q)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.TagStats(q)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &MoodAnalysis{
+		Days:            days,
+		Baseline:        baseline,
+		NegativePercent: percent(baseline.NegativeEntries, baseline.Entries),
+		Triggers:        []TriggerInsight{},
+		Coping:          []CopingInsight{},
+	}
+	for _, stat := range stats {
+		switch stat.Kind {
+		case form.TagKindTrigger:
+			insight := TriggerInsight{
+				TagID:            stat.TagID,
+				Name:             stat.Name,
+				Uses:             stat.Uses,
+				NegativeUses:     stat.NegativeUses,
+				NegativePercent:  percent(stat.NegativeUses, stat.Uses),
+				AverageIntensity: round2(stat.AverageIntensity),
+				Score:            analysisScore(stat.NegativeUses, stat.Uses),
+			}
+			if analysis.NegativePercent > 0 {
+				insight.Lift = round2(insight.NegativePercent / analysis.NegativePercent)
+			}
+			analysis.Triggers = append(analysis.Triggers, insight)
+		case form.TagKindCoping:
+			analysis.Coping = append(analysis.Coping, CopingInsight{
+				TagID:           stat.TagID,
+				Name:            stat.Name,
+				Uses:            stat.Uses,
+				FollowUps:       stat.FollowUps,
+				Improved:        stat.Improved,
+				ImprovedPercent: percent(stat.Improved, stat.FollowUps),
+				AverageDrop:     round2(stat.AverageDrop),
+				Score:           analysisScore(stat.Improved, stat.FollowUps),
+			})
+		}
+	}
+	return analysis, nil
+}
+
+// Analyze нь хэрэглэгчийн сүүлийн days хоногийн шинжилгээг тооцож, шошготой бүртгэл бүрийн
+// ai_mood_analysis.trigger_pattern_score, coping_effectiveness_score-д шошгуудынх нь хамгийн
+// өндөр оноог бичнэ. Шошгоо алдсан бүртгэлийн оноо NULL болно. Бичсэн бүртгэлийн тоог буцаана.
+func (s *moodEntryService) Analyze(userID uint, days int) (int, error) {
+	q := repository.AnalysisQuery{UserID: userID, Since: time.Now().AddDate(0, 0, -days)}
+	analysis, err := s.analyze(q, days)
+	if err != nil {
+		return 0, err
+	}
+	links, err := s.repo.EntryTagLinks(q)
+	if err != nil {
+		return 0, err
+	}
+
+	triggers := map[uint]tagPattern{}
+	for _, insight := range analysis.Triggers {
+		triggers[insight.TagID] = tagPattern{TagID: insight.TagID, Name: insight.Name, Score: insight.Score}
+	}
+	coping := map[uint]tagPattern{}
+	for _, insight := range analysis.Coping {
+		coping[insight.TagID] = tagPattern{TagID: insight.TagID, Name: insight.Name, Score: insight.Score}
+	}
+
+	type entryPatterns struct {
+		Triggers []tagPattern `json:"triggers"`
+		Coping   []tagPattern `json:"coping"`
+	}
+	var order []uint
+	patterns := map[uint]*entryPatterns{}
+	for _, link := range links {
+		entry := patterns[link.MoodEntryID]
+		if entry == nil {
+			entry = &entryPatterns{Triggers: []tagPattern{}, Coping: []tagPattern{}}
+			patterns[link.MoodEntryID] = entry
+			order = append(order, link.MoodEntryID)
+		}
+		if pattern, ok := triggers[link.TagID]; ok {
+			entry.Triggers = append(entry.Triggers, pattern)
+		}
+		if pattern, ok := coping[link.TagID]; ok {
+			entry.Coping = append(entry.Coping, pattern)
+		}
+	}
+
+	scores := make([]repository.EntryScore, 0, len(order))
+	for _, entryID := range order {
+		entry := patterns[entryID]
+		detected, err := json.Marshal(map[string]interface{}{"trigger_tags": entry.Triggers, "coping_tags": entry.Coping})
+		if err != nil {
+			return 0, err
+		}
+		scores = append(scores, repository.EntryScore{
+			MoodEntryID:  entryID,
+			UserID:       userID,
+			TriggerScore: maxScore(entry.Triggers),
+			CopingScore:  maxScore(entry.Coping),
+			Patterns:     detected,
+		})
+	}
+	if len(scores) == 0 {
+		return 0, nil
+	}
+	return len(scores), s.repo.SaveAnalyses(scores)
+}
+
+func maxScore(patterns []tagPattern) *int {
+	if len(patterns) == 0 {
+		return nil
+	}
+	score := patterns[0].Score
+	for _, pattern := range patterns[1:] {
+		score = max(score, pattern.Score)
+	}
+	return &score
+}
+
+// AnalyzeRecent нь сүүлийн analysisLookback-д бүртгэл хийсэн хэрэглэгчдийг шинжилнэ (scheduler).
+// Нэг хэрэглэгчийн алдаа бусдыг зогсоохгүй.
+func (s *moodEntryService) AnalyzeRecent() (int, error) {
+	userIDs, err := s.repo.UsersWithEntriesSince(time.Now().Add(-analysisLookback))
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	total := 0
+	for _, userID := range userIDs {
+		count, err := s.Analyze(userID, form.DefaultAnalysisDays)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		total += count
+	}
+	return total, errors.Join(errs...)
+}
+
+func (s *moodEntryService) EntryAnalysis(entryID uint) (*model.AIMoodAnalysis, error) {
+	analysis, err := s.repo.FindAnalysis(entryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMoodAnalysisNotFound
+	}
+	return analysis, err
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/mood/form"
+	"mindsteps/internal/mood/repository"
+)
+
+// CalendarMood нь өдрийн давамгайлсан mood unit (MoodUnit.DisplayColor, DisplayEmoji)
+type CalendarMood struct {
+	ID     uint   `json:"id"`
+	NameEn string `json:"name_en"`
+	NameMn string `json:"name_mn"`
+	Color  string `json:"color"`
+	Emoji  string `json:"emoji"`
+}
+
+// CalendarCell нь хуанлийн нэг өдөр. Бүртгэлгүй өдөр Mood нь null, Entries нь 0.
+type CalendarCell struct {
+	Date             string        `json:"date"`
+	Entries          int64         `json:"entries"`
+	AverageIntensity float64       `json:"average_intensity"`
+	Mood             *CalendarMood `json:"mood"`
+}
+
+type MoodCalendar struct {
+	Timezone string         `json:"timezone"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Days     []CalendarCell `json:"days"`
+}
+
+// CalendarDayDetail нь нэг өдрийн бүртгэл, тэмдэглэл, бясалгал, дууссан хичээлүүд.
+type CalendarDayDetail struct {
+	Timezone           string                     `json:"timezone"`
+	Date               string                     `json:"date"`
+	MoodEntries        []model.MoodEntries        `json:"mood_entries"`
+	Journals           []repository.DayJournal    `json:"journals"`
+	MeditationSessions []model.MeditationSessions `json:"meditation_sessions"`
+	Lessons            []repository.DayLesson     `json:"lessons"`
+}
+
+// Calendar нь [from, to] хугацааны өдөр бүрд (бүртгэлгүй өдрийг оруулаад) нэг нүд буцаана.
+func (s *moodEntryService) Calendar(userID uint, f *form.MoodCalendarForm) (*MoodCalendar, error) {
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := f.Range(time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.repo.CalendarDays(repository.StatsQuery{UserID: userID, Timezone: loc.String(), From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]repository.CalendarDay, len(days))
+	for _, day := range days {
+		byDate[day.Day.Format(time.DateOnly)] = day
+	}
+
+	calendar := &MoodCalendar{
+		Timezone: loc.String(),
+		From:     from.Format(time.DateOnly),
+		To:       to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Days:     []CalendarCell{},
+	}
+	for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+		cell := CalendarCell{Date: date.Format(time.DateOnly)}
+		if day, ok := byDate[cell.Date]; ok {
+			cell.Entries = day.Entries
+			cell.AverageIntensity = round2(day.AverageIntensity)
+			cell.Mood = &CalendarMood{
+				ID:     day.MoodUnitID,
+				NameEn: day.DisplayNameEn,
+				NameMn: day.DisplayNameMn,
+				Color:  day.DisplayColor,
+				Emoji:  day.DisplayEmoji,
+			}
+		}
+		calendar.Days = append(calendar.Days, cell)
+	}
+	return calendar, nil
+}
+
+// CalendarDay нь date (YYYY-MM-DD, хэрэглэгчийн цагаар) өдрийн бүх үйл ажиллагааг буцаана.
+func (s *moodEntryService) CalendarDay(userID uint, date string) (*CalendarDayDetail, error) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("огноо YYYY-MM-DD хэлбэртэй байх ёстой")
+	}
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := repository.StatsQuery{UserID: userID, Timezone: loc.String(), From: day, To: day.AddDate(0, 0, 1)}
+	detail := &CalendarDayDetail{Timezone: loc.String(), Date: date}
+	if detail.MoodEntries, err = s.repo.DayEntries(query); err != nil {
+		return nil, err
+	}
+	if detail.Journals, err = s.repo.DayJournals(query); err != nil {
+		return nil, err
+	}
+	if detail.MeditationSessions, err = s.repo.DayMeditations(query); err != nil {
+		return nil, err
+	}
+	if detail.Lessons, err = s.repo.DayLessons(query); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
@@ -1,47 +1,88 @@
 package service
 
 import (
-	"fmt"
+	"encoding/json"
 	"log"
 	"mindsteps/database/model"
 	gamification "mindsteps/internal/gamification/service"
 	"mindsteps/internal/mood/form"
 	"mindsteps/internal/mood/repository"
+	"mindsteps/internal/shared"
 	"time"
 )
 
 type MoodEntryService interface {
-	Create(form *form.MoodEntryForm) (*model.MoodEntries, error)
+	Create(form *form.MoodEntryForm) (*MoodCheckIn, error)
 	GetByID(id uint) (*model.MoodEntries, error)
-	Update(id uint, form *form.MoodEntryForm) (*model.MoodEntries, error)
+	CheckIn(entry *model.MoodEntries) (*MoodCheckIn, error)
+	Update(id uint, form *form.MoodEntryForm) (*MoodCheckIn, error)
 	Delete(id uint) error
 	ListByUserID(userID uint, page, limit int) ([]model.MoodEntries, int64, error)
-	GetStatistics(userID uint, days int) (map[string]interface{}, error)
+	Statistics(userID uint, f *form.MoodStatsForm) (*MoodStats, error)
+	Wheel(userID uint, f *form.MoodWheelForm) (*MoodWheel, error)
+	Calendar(userID uint, f *form.MoodCalendarForm) (*MoodCalendar, error)
+	CalendarDay(userID uint, date string) (*CalendarDayDetail, error)
+	Tags(userID uint, q *form.MoodTagQuery) ([]model.MoodTags, error)
+	Analysis(userID uint, f *form.MoodAnalysisForm) (*MoodAnalysis, error)
+	Analyze(userID uint, days int) (int, error)
+	AnalyzeRecent() (int, error)
+	EntryAnalysis(entryID uint) (*model.AIMoodAnalysis, error)
 	ListByMoodID() ([]model.MoodCategories, error)
 }
 
 type moodEntryService struct {
 	repo         repository.MoodEntryRepository
+	combinations repository.PlutchikCombinationRepository
 	gamification gamification.GamificationService
 }
 
-func NewMoodEntryService(repo repository.MoodEntryRepository, gamification gamification.GamificationService) MoodEntryService {
-	return &moodEntryService{repo: repo, gamification: gamification}
+func NewMoodEntryService(repo repository.MoodEntryRepository, combinations repository.PlutchikCombinationRepository, gamification gamification.GamificationService) MoodEntryService {
+	return &moodEntryService{repo: repo, combinations: combinations, gamification: gamification}
 }
 
 func (s *moodEntryService) ListByMoodID() ([]model.MoodCategories, error) {
 	return s.repo.ListByMoodID()
 }
 
-func (s *moodEntryService) Create(f *form.MoodEntryForm) (*model.MoodEntries, error) {
+func (s *moodEntryService) Create(f *form.MoodEntryForm) (*MoodCheckIn, error) {
 	if err := f.Validate(); err != nil {
 		return nil, err
 	}
 
-	entry := form.NewMoodEntryFromForm(*f)
-	entry.CreatedAt = time.Now()
+	loc, err := s.userLocation(f.UserID)
+	if err != nil {
+		return nil, err
+	}
+	entryDate, err := f.EntryDay(time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.Create(entry); err != nil {
+	plan := &wheelPlan{rows: []model.UserEmotionWheel{}, dyads: []model.PlutchikCombinations{}}
+	if len(f.Emotions) > 0 {
+		if plan, err = s.planWheel(f.UserID, f); err != nil {
+			return nil, err
+		}
+		if err := s.applyDominant(f, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := form.NewMoodEntryFromForm(*f, entryDate)
+	entry.CreatedAt = time.Now().In(shared.StoredLocation)
+	for i := range plan.rows {
+		plan.rows[i].RecordedAt = entry.CreatedAt
+	}
+
+	if err := s.repo.CreateWithEmotions(entry, plan.rows); err != nil {
+		return nil, err
+	}
+
+	tags := []model.MoodTags{}
+	if err := s.syncTags(entry, f); err != nil {
+		// Бүртгэл хадгалагдсан тул шошгогүй үлдээнэ, дараагийн засварт дахин үүснэ
+		log.Printf("Failed to sync mood tags for entry %d: %v", entry.ID, err)
+	} else if tags, err = s.repo.EntryTags(entry.ID); err != nil {
 		return nil, err
 	}
 
@@ -60,15 +101,18 @@ func (s *moodEntryService) Create(f *form.MoodEntryForm) (*model.MoodEntries, er
 		score += 5
 	}
 
-	//TODO
-	metadata := fmt.Sprintf(`{"emotion": "", "intensity": %d}`, entry.Intensity)
+	emotion := ""
+	if plan.dominant != nil {
+		emotion = plan.dominant.NameEn
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{"emotion": emotion, "intensity": entry.Intensity})
 
-	err := s.gamification.AddXP(
+	err = s.gamification.AddXP(
 		entry.UserID,
 		score,
 		"mood_entry",
 		entry.ID,
-		metadata,
+		string(metadata),
 	)
 
 	if err != nil {
@@ -76,23 +120,48 @@ func (s *moodEntryService) Create(f *form.MoodEntryForm) (*model.MoodEntries, er
 		log.Printf("Failed to award XP for user %d: %v", entry.UserID, err)
 	}
 
-	return entry, nil
+	return &MoodCheckIn{MoodEntries: entry, Emotions: plan.rows, Dyads: plan.dyads, Tags: tags}, nil
 }
 
 func (s *moodEntryService) GetByID(id uint) (*model.MoodEntries, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *moodEntryService) Update(id uint, f *form.MoodEntryForm) (*model.MoodEntries, error) {
+// Update нь бүртгэлийг шинэчилнэ. emotions өгсөн бол дугуй дээрх сэтгэл хөдлөлүүдийг солино,
+// өгөөгүй бол хуучнаараа үлдэнэ.
+func (s *moodEntryService) Update(id uint, f *form.MoodEntryForm) (*MoodCheckIn, error) {
 	// if err := f.Validate(); err != nil {
 	// 	return nil, err
 	// }
+	if err := f.ValidateEmotions(); err != nil {
+		return nil, err
+	}
 
 	entry, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if f.EntryDate != "" {
+		loc, err := s.userLocation(entry.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if entry.EntryDate, err = f.EntryDay(time.Now().In(loc)); err != nil {
+			return nil, err
+		}
+	}
+
+	var plan *wheelPlan
+	if len(f.Emotions) > 0 {
+		if plan, err = s.planWheel(entry.UserID, f); err != nil {
+			return nil, err
+		}
+		if f.Intensity == 0 {
+			f.Intensity = f.Dominant().Intensity
+		}
+	}
+
 	//entry.MoodID = f.MoodID
 	entry.Intensity = f.Intensity
 	entry.WhenFelt = f.WhenFelt
@@ -102,12 +171,29 @@ func (s *moodEntryService) Update(id uint, f *form.MoodEntryForm) (*model.MoodEn
 	entry.Location = f.Location
 	entry.Weather = f.Weather
 	//entry.RelatedValueIds = f.RelatedValueIds
-	entry.UpdatedAt = time.Now()
+	entry.UpdatedAt = time.Now().In(shared.StoredLocation)
 
 	if err := s.repo.Update(entry); err != nil {
 		return nil, err
 	}
-	return entry, nil
+	if err := s.syncTags(entry, f); err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return s.CheckIn(entry)
+	}
+
+	for i := range plan.rows {
+		plan.rows[i].RecordedAt = entry.CreatedAt
+	}
+	if err := s.repo.ReplaceEmotions(entry.ID, plan.rows); err != nil {
+		return nil, err
+	}
+	tags, err := s.repo.EntryTags(entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &MoodCheckIn{MoodEntries: entry, Emotions: plan.rows, Dyads: plan.dyads, Tags: tags}, nil
 }
 
 func (s *moodEntryService) Delete(id uint) error {
@@ -138,33 +224,3 @@ func (s *moodEntryService) ListByUserID(userID uint, page, limit int) ([]model.M
 
 	return entries, total, nil
 }
-
-func (s *moodEntryService) GetStatistics(userID uint, days int) (map[string]interface{}, error) {
-	toDate := time.Now()
-	fromDate := toDate.AddDate(0, 0, -days)
-
-	entries, err := s.repo.FindByDateRange(userID, fromDate, toDate)
-	if err != nil {
-		return nil, err
-	}
-
-	// Calculate statistics
-	stats := make(map[string]interface{})
-	stats["total_entries"] = len(entries)
-	stats["period_days"] = days
-
-	if len(entries) > 0 {
-		totalIntensity := 0
-		moodCounts := make(map[int]int)
-
-		for _, entry := range entries {
-			totalIntensity += entry.Intensity
-			moodCounts[entry.MoodUnitID]++
-		}
-
-		stats["average_intensity"] = float64(totalIntensity) / float64(len(entries))
-		stats["mood_distribution"] = moodCounts
-	}
-
-	return stats, nil
-}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"mindsteps/internal/mood/form"
+	"mindsteps/internal/mood/repository"
+	"mindsteps/internal/shared"
+)
+
+// MoodStats нь /mood-entries/stats-ийн хариу. Огноонууд хэрэглэгчийн цагийн бүсээр.
+type MoodStats struct {
+	Timezone string                  `json:"timezone"`
+	Bucket   string                  `json:"bucket"`
+	From     string                  `json:"from"`
+	To       string                  `json:"to"`
+	Summary  repository.StatsSummary `json:"summary"`
+	Previous PeriodComparison        `json:"previous"`
+	Trend    []TrendPoint            `json:"trend"`
+	// TrendSlope нь bucket тутамд дундаж эрчим хэдээр өөрчлөгдөж буйг (шугаман регресс) харуулна
+	TrendSlope float64                `json:"trend_slope"`
+	Emotions   []Share                `json:"emotions"`
+	Categories []Share                `json:"categories"`
+	Hours      []repository.StatsHour `json:"hours"`
+	DayParts   []DayPart              `json:"day_parts"`
+}
+
+// PeriodComparison нь ижил урттай өмнөх хугацаа, түүнээс гарсан өөрчлөлт (одоо - өмнөх).
+type PeriodComparison struct {
+	From                   string                  `json:"from"`
+	To                     string                  `json:"to"`
+	Summary                repository.StatsSummary `json:"summary"`
+	EntriesChange          int64                   `json:"entries_change"`
+	AverageIntensityChange float64                 `json:"average_intensity_change"`
+	ActiveDaysChange       int64                   `json:"active_days_change"`
+}
+
+type TrendPoint struct {
+	Bucket           string  `json:"bucket"`
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+	MinIntensity     int     `json:"min_intensity"`
+	MaxIntensity     int     `json:"max_intensity"`
+}
+
+// Share нь нийт бичлэгт эзлэх хувийг (Percent) нэмсэн StatsShare
+type Share struct {
+	repository.StatsShare
+	Percent float64 `json:"percent"`
+}
+
+// DayPart нь өдрийн хэсэг (FromHour-аас ToHour хүртэл, хоёулаа багтана)
+type DayPart struct {
+	Name             string  `json:"name"`
+	FromHour         int     `json:"from_hour"`
+	ToHour           int     `json:"to_hour"`
+	Entries          int64   `json:"entries"`
+	AverageIntensity float64 `json:"average_intensity"`
+}
+
+var dayParts = []DayPart{
+	{Name: "night", FromHour: 0, ToHour: 5},
+	{Name: "morning", FromHour: 6, ToHour: 11},
+	{Name: "afternoon", FromHour: 12, ToHour: 17},
+	{Name: "evening", FromHour: 18, ToHour: 23},
+}
+
+// userLocation нь хэрэглэгчийн users.timezone-ийг буцаана. Хоосон эсвэл буруу бол UTC.
+func (s *moodEntryService) userLocation(userID uint) (*time.Location, error) {
+	name, err := s.repo.UserTimezone(userID)
+	if err != nil {
+		return nil, err
+	}
+	return shared.UserLocation(name), nil
+}
+
+// Statistics нь хугацааны нэгтгэл, bucket-аарх эрчмийн өөрчлөлт, Plutchik сэтгэл хөдлөл болон
+// ангиллын тархалт, өдрийн цагийн хэв маягийг өмнөх ижил урттай хугацаатай харьцуулан буцаана.
+// Бүх тооцоо DB-д нэгтгэгдэнэ.
+func (s *moodEntryService) Statistics(userID uint, f *form.MoodStatsForm) (*MoodStats, error) {
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := f.Range(time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	query := repository.StatsQuery{UserID: userID, Timezone: loc.String(), From: from, To: to}
+	previous := query
+	previous.From, previous.To = previousPeriod(from, to, f.Bucket)
+
+	stats := &MoodStats{
+		Timezone: loc.String(),
+		Bucket:   f.Bucket,
+		From:     from.Format(time.DateOnly),
+		To:       to.AddDate(0, 0, -1).Format(time.DateOnly),
+	}
+	if stats.Summary, err = s.repo.StatsSummary(query); err != nil {
+		return nil, err
+	}
+	prevSummary, err := s.repo.StatsSummary(previous)
+	if err != nil {
+		return nil, err
+	}
+	stats.Previous = compare(stats.Summary, prevSummary, previous.From, previous.To)
+
+	buckets, err := s.repo.StatsTrend(query, f.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	stats.Trend, stats.TrendSlope = trend(buckets, from, f.Bucket)
+
+	emotions, err := s.repo.StatsEmotions(query)
+	if err != nil {
+		return nil, err
+	}
+	stats.Emotions = withPercent(emotions)
+
+	categories, err := s.repo.StatsCategories(query)
+	if err != nil {
+		return nil, err
+	}
+	stats.Categories = withPercent(categories)
+
+	if stats.Hours, err = s.repo.StatsHours(query); err != nil {
+		return nil, err
+	}
+	stats.DayParts = groupDayParts(stats.Hours)
+	return stats, nil
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// previousPeriod нь [from, to)-ийн өмнөх ижил урттай хугацааг календарийн өдрөөр буцаана.
+// Бүтэн сарууд бол өмнөх ижил тооны сар, week bucket-д долоо хоногийн эхлэлтэй таарахаар
+// бүтэн долоо хоногоор шилжүүлнэ.
+func previousPeriod(from, to time.Time, bucket string) (time.Time, time.Time) {
+	days := int(to.Sub(from).Hours() / 24)
+	switch bucket {
+	case form.BucketMonth:
+		if from.Day() == 1 && to.Day() == 1 {
+			months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+			return from.AddDate(0, -months, 0), from
+		}
+	case form.BucketWeek:
+		weeks := (days + 6) / 7
+		prevFrom := from.AddDate(0, 0, -7*weeks)
+		return prevFrom, prevFrom.AddDate(0, 0, days)
+	}
+	return from.AddDate(0, 0, -days), from
+}
+
+func compare(current, previous repository.StatsSummary, from, to time.Time) PeriodComparison {
+	return PeriodComparison{
+		From:                   from.Format(time.DateOnly),
+		To:                     to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Summary:                previous,
+		EntriesChange:          current.Entries - previous.Entries,
+		AverageIntensityChange: round2(current.AverageIntensity - previous.AverageIntensity),
+		ActiveDaysChange:       current.ActiveDays - previous.ActiveDays,
+	}
+}
+
+// bucketIndex нь from-оос хойш хэд дэх bucket болохыг буцаана (регрессийн x).
+func bucketIndex(from, at time.Time, bucket string) float64 {
+	switch bucket {
+	case form.BucketMonth:
+		return float64((at.Year()-from.Year())*12 + int(at.Month()-from.Month()))
+	case form.BucketWeek:
+		return math.Floor(at.Sub(from).Hours() / (24 * 7))
+	}
+	return math.Floor(at.Sub(from).Hours() / 24)
+}
+
+// trend нь bucket-уудыг форматлаж, дундаж эрчмийн шугаман регрессийн налалтыг тооцно.
+func trend(buckets []repository.StatsBucket, from time.Time, bucket string) ([]TrendPoint, float64) {
+	points := make([]TrendPoint, 0, len(buckets))
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, b := range buckets {
+		points = append(points, TrendPoint{
+			Bucket:           b.Bucket.Format(time.DateOnly),
+			Entries:          b.Entries,
+			AverageIntensity: round2(b.AverageIntensity),
+			MinIntensity:     b.MinIntensity,
+			MaxIntensity:     b.MaxIntensity,
+		})
+		x := bucketIndex(from, b.Bucket, bucket)
+		n++
+		sumX += x
+		sumY += b.AverageIntensity
+		sumXY += x * b.AverageIntensity
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return points, 0
+	}
+	return points, round2((n*sumXY - sumX*sumY) / denominator)
+}
+
+func withPercent(shares []repository.StatsShare) []Share {
+	var total int64
+	for _, share := range shares {
+		total += share.Entries
+	}
+	result := make([]Share, 0, len(shares))
+	for _, share := range shares {
+		share.AverageIntensity = round2(share.AverageIntensity)
+		item := Share{StatsShare: share}
+		if total > 0 {
+			item.Percent = math.Round(float64(share.Entries)*1000/float64(total)) / 10
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func groupDayParts(hours []repository.StatsHour) []DayPart {
+	parts := make([]DayPart, len(dayParts))
+	copy(parts, dayParts)
+	for i := range parts {
+		var weighted float64
+		for _, hour := range hours {
+			if hour.Hour >= parts[i].FromHour && hour.Hour <= parts[i].ToHour {
+				parts[i].Entries += hour.Entries
+				weighted += hour.AverageIntensity * float64(hour.Entries)
+			}
+		}
+		if parts[i].Entries > 0 {
+			parts[i].AverageIntensity = round2(weighted / float64(parts[i].Entries))
+		}
+	}
+	return parts
+}
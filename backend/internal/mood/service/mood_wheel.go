@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/mood/form"
+	"mindsteps/internal/mood/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrNoMoodUnitForEmotion = errors.New("сэтгэл хөдлөлд тохирох mood unit олдсонгүй, mood_unit_id илгээнэ үү")
+
+// MoodCheckIn нь бүртгэл, түүний Plutchik дугуй дээрх сэтгэл хөдлөлүүд болон тэдгээрээс
+// илэрсэн хослолууд (dyad), trigger, coping шошгууд. Бүртгэлийн талбарууд JSON-д шууд (entry-тэй адил) гарна.
+type MoodCheckIn struct {
+	*model.MoodEntries
+	Emotions []model.UserEmotionWheel     `json:"emotions"`
+	Dyads    []model.PlutchikCombinations `json:"dyads"`
+	Tags     []model.MoodTags             `json:"tags"`
+}
+
+// MoodWheel нь хугацааны нэгтгэсэн Plutchik дугуй. Огноонууд хэрэглэгчийн цагийн бүсээр.
+type MoodWheel struct {
+	Timezone string                 `json:"timezone"`
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	Entries  int64                  `json:"entries"`
+	Emotions []WheelEmotionShare    `json:"emotions"`
+	Dyads    []repository.WheelDyad `json:"dyads"`
+}
+
+// WheelEmotionShare нь нийт бүртгэлийн хэдэн хувьд (Percent) тухайн сэтгэл хөдлөл орсныг нэмнэ.
+type WheelEmotionShare struct {
+	repository.WheelEmotion
+	Percent float64 `json:"percent"`
+}
+
+// baseEmotionID нь Plutchik-ийн үндсэн сэтгэл хөдлөл (ecstasy, serenity -> joy г.м).
+func baseEmotionID(emotion *model.PlutchikEmotions) int {
+	if emotion.BaseEmotionID > 0 {
+		return emotion.BaseEmotionID
+	}
+	return emotion.ID
+}
+
+// wheelPlan нь бүртгэлд хадгалах user_emotion_wheel мөрүүд
+type wheelPlan struct {
+	rows     []model.UserEmotionWheel
+	dyads    []model.PlutchikCombinations
+	dominant *model.PlutchikEmotions
+}
+
+// planWheel нь сонгосон сэтгэл хөдлөлүүдийг шалгаж, хос бүрийн хослолыг GetByEmotionPair-аар
+// (үндсэн сэтгэл хөдлөлөөр) хайна. Мөр бүрд тухайн сэтгэл хөдлөлийн оролцсон хамгийн хүчтэй
+// (хоёр эрчмийн бага нь их) хослол detected_combination_id болж бичигдэнэ.
+func (s *moodEntryService) planWheel(userID uint, f *form.MoodEntryForm) (*wheelPlan, error) {
+	ids := make([]int, 0, len(f.Emotions))
+	for _, emotion := range f.Emotions {
+		ids = append(ids, emotion.PlutchikEmotionID)
+	}
+	found, err := s.repo.FindEmotions(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*model.PlutchikEmotions, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+
+	bases := make([]int, len(f.Emotions))
+	seenBase := map[int]bool{}
+	for i, emotion := range f.Emotions {
+		e, ok := byID[emotion.PlutchikEmotionID]
+		if !ok {
+			return nil, fmt.Errorf("plutchik_emotion_id %d олдсонгүй", emotion.PlutchikEmotionID)
+		}
+		bases[i] = baseEmotionID(e)
+		if seenBase[bases[i]] {
+			return nil, fmt.Errorf("нэг үндсэн сэтгэл хөдлөлийн хэд хэдэн түвшинг зэрэг сонгох боломжгүй")
+		}
+		seenBase[bases[i]] = true
+	}
+
+	type pairMatch struct {
+		combination *model.PlutchikCombinations
+		strength    int
+	}
+	best := make([]pairMatch, len(f.Emotions))
+	var matches []pairMatch
+	for i := 0; i < len(f.Emotions); i++ {
+		for j := i + 1; j < len(f.Emotions); j++ {
+			combination, err := s.combinations.GetByEmotionPair(bases[i], bases[j])
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			match := pairMatch{combination: combination, strength: min(f.Emotions[i].Intensity, f.Emotions[j].Intensity)}
+			matches = append(matches, match)
+			for _, k := range []int{i, j} {
+				if best[k].combination == nil || match.strength > best[k].strength {
+					best[k] = match
+				}
+			}
+		}
+	}
+
+	plan := &wheelPlan{
+		rows:     make([]model.UserEmotionWheel, 0, len(f.Emotions)),
+		dyads:    []model.PlutchikCombinations{},
+		dominant: byID[f.Dominant().PlutchikEmotionID],
+	}
+	for i, emotion := range f.Emotions {
+		row := model.UserEmotionWheel{
+			UserID:            userID,
+			PlutchikEmotionID: emotion.PlutchikEmotionID,
+			Intensity:         emotion.Intensity,
+			PlutchikEmotion:   byID[emotion.PlutchikEmotionID],
+		}
+		if best[i].combination != nil {
+			row.DetectedCombinationID = best[i].combination.ID
+			row.DetectedCombination = best[i].combination
+		}
+		plan.rows = append(plan.rows, row)
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].strength > matches[b].strength })
+	seen := map[int]bool{}
+	for _, match := range matches {
+		if !seen[match.combination.ID] {
+			seen[match.combination.ID] = true
+			plan.dyads = append(plan.dyads, *match.combination)
+		}
+	}
+	return plan, nil
+}
+
+// applyDominant нь emotions өгсөн үед дутуу intensity, mood_unit_id-г хамгийн хүчтэй
+// сэтгэл хөдлөлөөс бөглөнө.
+func (s *moodEntryService) applyDominant(f *form.MoodEntryForm, plan *wheelPlan) error {
+	if f.Intensity == 0 {
+		f.Intensity = f.Dominant().Intensity
+	}
+	if f.MoodUnitId != 0 {
+		return nil
+	}
+
+	unit, err := s.repo.MoodUnitByEmotion(plan.dominant.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) && baseEmotionID(plan.dominant) != plan.dominant.ID {
+		unit, err = s.repo.MoodUnitByEmotion(baseEmotionID(plan.dominant))
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNoMoodUnitForEmotion
+	}
+	if err != nil {
+		return err
+	}
+	f.MoodUnitId = int(unit.ID)
+	return nil
+}
+
+// CheckIn нь бүртгэлийн дугуй дээрх сэтгэл хөдлөл, хослолуудыг ачаална.
+func (s *moodEntryService) CheckIn(entry *model.MoodEntries) (*MoodCheckIn, error) {
+	emotions, err := s.repo.EntryEmotions(entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.repo.EntryTags(entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkIn := &MoodCheckIn{MoodEntries: entry, Emotions: emotions, Dyads: []model.PlutchikCombinations{}, Tags: tags}
+	seen := map[int]bool{}
+	for _, emotion := range emotions {
+		if combination := emotion.DetectedCombination; combination != nil && !seen[combination.ID] {
+			seen[combination.ID] = true
+			checkIn.Dyads = append(checkIn.Dyads, *combination)
+		}
+	}
+	return checkIn, nil
+}
+
+// Wheel нь хугацаанд тэмдэглэсэн сэтгэл хөдлөлүүдийг үндсэн сэтгэл хөдлөлөөр, илэрсэн
+// хослолуудыг тоогоор нь нэгтгэнэ.
+func (s *moodEntryService) Wheel(userID uint, f *form.MoodWheelForm) (*MoodWheel, error) {
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := f.Range(time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	query := repository.StatsQuery{UserID: userID, Timezone: loc.String(), From: from, To: to}
+
+	wheel := &MoodWheel{
+		Timezone: loc.String(),
+		From:     from.Format(time.DateOnly),
+		To:       to.AddDate(0, 0, -1).Format(time.DateOnly),
+	}
+	if wheel.Entries, err = s.repo.WheelEntries(query); err != nil {
+		return nil, err
+	}
+	emotions, err := s.repo.WheelEmotions(query)
+	if err != nil {
+		return nil, err
+	}
+	if wheel.Dyads, err = s.repo.WheelDyads(query); err != nil {
+		return nil, err
+	}
+
+	wheel.Emotions = make([]WheelEmotionShare, 0, len(emotions))
+	for _, emotion := range emotions {
+		emotion.AverageIntensity = round2(emotion.AverageIntensity)
+		share := WheelEmotionShare{WheelEmotion: emotion}
+		if wheel.Entries > 0 {
+			share.Percent = math.Round(float64(emotion.Entries)*1000/float64(wheel.Entries)) / 10
+		}
+		wheel.Emotions = append(wheel.Emotions, share)
+	}
+	return wheel, nil
+}
@@ -0,0 +1,37 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AppleIssuer = "https://appleid.apple.com"
+	// appleSecretTTL нь Apple client secret-ийн хүчинтэй хугацаа (Apple 6 сараас илүүг зөвшөөрөхгүй)
+	appleSecretTTL = time.Hour
+)
+
+// AppleClientSecret нь Apple-ийн шаарддаг ES256-аар гарын үсэг зурсан client secret үүсгэх функц буцаана.
+// privateKeyPEM нь Apple Developer-ээс татсан .p8 (PKCS8) түлхүүр.
+func AppleClientSecret(teamID, keyID, clientID, privateKeyPEM string) (func() (string, error), error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(strings.ReplaceAll(privateKeyPEM, `\n`, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("apple private key уншихад алдаа: %w", err)
+	}
+
+	return func() (string, error) {
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+			Issuer:    teamID,
+			Subject:   clientID,
+			Audience:  jwt.ClaimStrings{AppleIssuer},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(appleSecretTTL)),
+		})
+		token.Header["kid"] = keyID
+		return token.SignedString(key)
+	}, nil
+}
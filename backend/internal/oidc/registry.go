@@ -0,0 +1,134 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+
+	"mindsteps/config"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/oauth2"
+)
+
+const (
+	GoogleProviderName = "google"
+	AppleProviderName  = "apple"
+
+	GoogleIssuer = "https://accounts.google.com"
+)
+
+// CallbackPath нь provider-ийн redirect_uri-ийн API доторх зам
+const CallbackPath = "/api/v1/auth/oidc/%s/callback"
+
+// Registry нь нэрээр нь сонгох идэвхтэй provider-уудыг хадгална.
+type Registry struct {
+	providers map[string]*Provider
+}
+
+func NewRegistry(providers ...*Provider) *Registry {
+	r := &Registry{providers: make(map[string]*Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (*Provider, error) {
+	p, ok := r.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// AuthRequest нь нэг удаагийн нэвтрэлтийн state, nonce болон PKCE verifier
+type AuthRequest struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+func NewAuthRequest() (*AuthRequest, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	return &AuthRequest{State: state, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}, nil
+}
+
+var (
+	registry *Registry
+	stub     *Stub
+)
+
+// MustLoad нь тохиргоонд client ID нь өгөгдсөн provider-уудыг бүртгэнэ.
+// OIDC_LOCAL_STUB идэвхтэй бол API сервер дээр хуурамч issuer-ийг "local" нэрээр нэмнэ.
+func MustLoad() {
+	cfg := config.Get().OIDC
+	redirectURL := func(name string) string {
+		return strings.TrimRight(cfg.RedirectBaseURL, "/") + fmt.Sprintf(CallbackPath, name)
+	}
+
+	var providers []*Provider
+
+	if cfg.GoogleClientID != "" {
+		providers = append(providers, NewProvider(Config{
+			Name:         GoogleProviderName,
+			Issuer:       GoogleIssuer,
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  redirectURL(GoogleProviderName),
+			ExtraIssuers: []string{"accounts.google.com"},
+		}))
+	}
+
+	if cfg.AppleClientID != "" {
+		secret, err := AppleClientSecret(cfg.AppleTeamID, cfg.AppleKeyID, cfg.AppleClientID, cfg.ApplePrivateKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		providers = append(providers, NewProvider(Config{
+			Name:             AppleProviderName,
+			Issuer:           AppleIssuer,
+			ClientID:         cfg.AppleClientID,
+			RedirectURL:      redirectURL(AppleProviderName),
+			Scopes:           []string{"openid", "email", "name"},
+			ResponseMode:     "form_post",
+			ClientSecretFunc: secret,
+		}))
+	}
+
+	if cfg.LocalStub {
+		s, err := NewStub(strings.TrimRight(cfg.RedirectBaseURL, "/") + StubPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stub = s
+		providers = append(providers, NewProvider(Config{
+			Name:        StubProviderName,
+			Issuer:      s.Issuer(),
+			ClientID:    StubClientID,
+			RedirectURL: redirectURL(StubProviderName),
+		}))
+		log.Warnf("локал OIDC stub идэвхтэй байна: %s", s.Issuer())
+	}
+
+	registry = NewRegistry(providers...)
+}
+
+// Get нь MustLoad-аар бүртгэсэн provider-уудыг буцаана.
+func Get() *Registry {
+	if registry == nil {
+		log.Fatal("OIDC not loaded. Please call oidc.MustLoad before using oidc.Get.")
+	}
+	return registry
+}
+
+// LocalStub нь OIDC_LOCAL_STUB идэвхгүй бол nil буцаана.
+func LocalStub() *Stub {
+	return stub
+}
@@ -0,0 +1,203 @@
+// Package password нь нууц үгийн бодлого (урт, тэмдэгтийн төрөл, түгээмэл нууц үг,
+// email агуулах эсэх) болон өмнөх нууц үгийг дахин ашиглахаас сэргийлэх шалгалтыг агуулна.
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"mindsteps/config"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxBytes нь bcrypt-ийн хүлээж авах дээд урт
+const maxBytes = 72
+
+// MaxHistorySize нь password_history-д хэрэглэгч бүрээр хадгалах дээд мөрийн тоо
+const MaxHistorySize = 24
+
+var (
+	ErrCommon        = errors.New("энэ нууц үг хэт түгээмэл тул ашиглах боломжгүй")
+	ErrContainsEmail = errors.New("нууц үг таны email хаягийг агуулж болохгүй")
+	ErrReused        = errors.New("сүүлд ашигласан нууц үгээ дахин ашиглах боломжгүй")
+	ErrTooLong       = fmt.Errorf("нууц үг %d байтаас урт байж болохгүй", maxBytes)
+)
+
+//go:embed common_passwords.txt
+var commonList string
+
+var common = parseList(strings.NewReader(commonList))
+
+// Policy нь нууц үгэнд тавигдах шаардлага
+type Policy struct {
+	MinLength   int
+	MinClasses  int
+	HistorySize int
+	extra       map[string]struct{}
+}
+
+// Default нь тохиргоо ачаалагдаагүй үед (жишээ нь unit test) хэрэглэгдэх бодлого
+func Default() Policy {
+	return Policy{MinLength: 10, MinClasses: 3, HistorySize: 5}
+}
+
+var current = Default()
+
+// MustLoad нь PASSWORD_* тохиргооноос бодлогыг ачаална.
+func MustLoad() {
+	cfg := config.Get().Password
+
+	p := Policy{
+		MinLength:   cfg.MinLength,
+		MinClasses:  cfg.MinClasses,
+		HistorySize: min(cfg.HistorySize, MaxHistorySize),
+	}
+	if cfg.CommonList != "" {
+		f, err := os.Open(cfg.CommonList)
+		if err != nil {
+			log.Fatal("түгээмэл нууц үгийн жагсаалт уншихад алдаа: ", err)
+		}
+		defer f.Close()
+		p.extra = parseList(f)
+	}
+
+	current = p
+}
+
+// Get нь идэвхтэй бодлогыг буцаана.
+func Get() Policy {
+	return current
+}
+
+// Validate нь идэвхтэй бодлогоор нууц үгийг шалгана.
+func Validate(plain, email string) error {
+	return current.Validate(plain, email)
+}
+
+// Validate нь нууц үгийг бодлоготой тулгаж, эхний зөрчлийг буцаана.
+func (p Policy) Validate(plain, email string) error {
+	if len([]rune(plain)) < p.MinLength {
+		return fmt.Errorf("нууц үг хамгийн багадаа %d тэмдэгт байх ёстой", p.MinLength)
+	}
+	if len(plain) > maxBytes {
+		return ErrTooLong
+	}
+	if classes(plain) < p.MinClasses {
+		return fmt.Errorf("нууц үг жижиг үсэг, том үсэг, тоо, тусгай тэмдэгтээс дор хаяж %d төрлийг агуулах ёстой", p.MinClasses)
+	}
+	if p.isCommon(plain) {
+		return ErrCommon
+	}
+	if containsEmail(plain, email) {
+		return ErrContainsEmail
+	}
+	return nil
+}
+
+// Hash нь нууц үгийг бодлогоор шалгаж, сүүлийн HistorySize нууц үгийн аль нэгтэй
+// давхцаагүй бол bcrypt hash буцаана. previous нь одоогийн hash-аас эхлэн шинээс хуучин руу эрэмбэлэгдсэн байна.
+func (p Policy) Hash(plain, email string, previous ...string) (string, error) {
+	if err := p.Validate(plain, email); err != nil {
+		return "", err
+	}
+	if len(previous) > p.HistorySize {
+		previous = previous[:p.HistorySize]
+	}
+	if Reused(plain, previous...) {
+		return "", ErrReused
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Reused нь нууц үг өгөгдсөн bcrypt hash-уудын аль нэгтэй таарч байгаа эсэхийг шалгана.
+func Reused(plain string, hashes ...string) bool {
+	for _, hash := range hashes {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func classes(plain string) int {
+	var lower, upper, digit, special bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			special = true
+		}
+	}
+
+	count := 0
+	for _, ok := range []bool{lower, upper, digit, special} {
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+// isCommon: "Password123!" шиг төгсгөлд нь тоо, тэмдэгт залгасан хувилбарыг ч түгээмэлд тооцно.
+func (p Policy) isCommon(plain string) bool {
+	lowered := strings.ToLower(plain)
+	base := strings.TrimRightFunc(lowered, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, candidate := range []string{lowered, base} {
+		if candidate == "" {
+			continue
+		}
+		if _, ok := common[candidate]; ok {
+			return true
+		}
+		if _, ok := p.extra[candidate]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containsEmail нь бүтэн email эсвэл түүний @-ийн өмнөх хэсгийг (3-аас урт бол) хайна.
+func containsEmail(plain, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+
+	lowered := strings.ToLower(plain)
+	local, _, _ := strings.Cut(email, "@")
+	return strings.Contains(lowered, email) || (len(local) >= 3 && strings.Contains(lowered, local))
+}
+
+func parseList(r io.Reader) map[string]struct{} {
+	list := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list[line] = struct{}{}
+	}
+	return list
+}
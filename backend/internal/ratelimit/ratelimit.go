@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store нь fixed-window тоолуур. Key бүр window хугацааны дараа дахин 0-ээс эхэлнэ.
+type Store interface {
+	// Hit нь тоолуурыг нэгээр нэмж, шинэ утга болон цонх дуусах хүртэлх хугацааг буцаана.
+	Hit(key string, window time.Duration) (int64, time.Duration, error)
+	// Count нь тоолуурын одоогийн утгыг нэмэлгүйгээр буцаана.
+	Count(key string) (int64, time.Duration, error)
+	Reset(key string) error
+}
+
+// LimitError нь хязгаар хэтэрсэн үед буцаах алдаа. Handler үүнийг 429 болгоно.
+type LimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return e.Message
+}
+
+// RetryAfterSeconds нь Retry-After header-т тавих секунд
+func (e *LimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+func NewLimitError(retryAfter time.Duration) *LimitError {
+	minutes := int(math.Ceil(retryAfter.Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	return &LimitError{
+		Message:    fmt.Sprintf("Хэт олон оролдлого хийлээ. %d минутын дараа дахин оролдоно уу", minutes),
+		RetryAfter: retryAfter,
+	}
+}
+
+// New нь Redis-ийг үндсэн, Postgres-ийг нөөц болгон ашиглах Store үүсгэнэ.
+func New(db *gorm.DB) Store {
+	return &fallbackStore{
+		primary:  &redisStore{},
+		fallback: &postgresStore{db: db},
+	}
+}
+
+// Exceeded нь key-ийн тоолуур limit-д хүрсэн бол LimitError буцаана.
+func Exceeded(store Store, key string, limit int64) error {
+	count, ttl, err := store.Count(key)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return NewLimitError(ttl)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/pkg/redis"
+
+	"github.com/gofiber/fiber/v2/log"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+var errRedisUnavailable = errors.New("redis холбогдоогүй байна")
+
+const keyPrefix = "ratelimit:"
+
+// fallbackStore нь Redis ажиллахгүй үед Postgres руу шилжинэ.
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+	// usedFallback нь процесс эхэлснээс хойш Postgres-д тоолуур бичигдсэн эсэх
+	usedFallback atomic.Bool
+}
+
+func (s *fallbackStore) Hit(key string, window time.Duration) (int64, time.Duration, error) {
+	count, ttl, err := s.primary.Hit(key, window)
+	if err == nil {
+		return count, ttl, nil
+	}
+	logFallback(err)
+	s.usedFallback.Store(true)
+	return s.fallback.Hit(key, window)
+}
+
+func (s *fallbackStore) Count(key string) (int64, time.Duration, error) {
+	count, ttl, err := s.primary.Count(key)
+	if err == nil {
+		return count, ttl, nil
+	}
+	logFallback(err)
+	return s.fallback.Count(key)
+}
+
+// Reset нь Postgres-д зөвхөн fallback ашиглагдсан эсвэл Redis алдаа өгсөн үед хандана.
+func (s *fallbackStore) Reset(key string) error {
+	err := s.primary.Reset(key)
+	if err != nil {
+		logFallback(err)
+	}
+	if err == nil && !s.usedFallback.Load() {
+		return nil
+	}
+	return s.fallback.Reset(key)
+}
+
+func logFallback(err error) {
+	if !errors.Is(err, errRedisUnavailable) {
+		log.Warnf("rate limit Redis алдаа, Postgres руу шилжлээ: %v", err)
+	}
+}
+
+type redisStore struct{}
+
+// hitScript нь тоолуур болон түүний TTL-ийг нэг атомар алхамд тавина. TTL-гүй үлдсэн
+// түлхүүрт (өмнөх хувилбарын INCR/PEXPIRE-ийн хооронд тасарсан) мөн TTL онооно.
+var hitScript = goredis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 or redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+func (s *redisStore) Hit(key string, window time.Duration) (int64, time.Duration, error) {
+	client := redis.GetRedis()
+	if client == nil {
+		return 0, 0, errRedisUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result, err := hitScript.Run(ctx, client, []string{keyPrefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+func (s *redisStore) Count(key string) (int64, time.Duration, error) {
+	client := redis.GetRedis()
+	if client == nil {
+		return 0, 0, errRedisUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	count, err := client.Get(ctx, keyPrefix+key).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ttl, err := client.PTTL(ctx, keyPrefix+key).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, ttl, nil
+}
+
+func (s *redisStore) Reset(key string) error {
+	client := redis.GetRedis()
+	if client == nil {
+		return errRedisUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	return client.Del(ctx, keyPrefix+key).Err()
+}
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+func (s *postgresStore) Hit(key string, window time.Duration) (int64, time.Duration, error) {
+	now := time.Now()
+	var counter model.RateLimitCounters
+
+	err := s.db.Raw(`
+		INSERT INTO `+model.TableNameRateLimitCounters+` AS c (key, count, expires_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			count      = CASE WHEN c.expires_at <= ? THEN 1 ELSE c.count + 1 END,
+			expires_at = CASE WHEN c.expires_at <= ? THEN EXCLUDED.expires_at ELSE c.expires_at END
+		RETURNING key, count, expires_at`,
+		key, now.Add(window), now, now,
+	).Scan(&counter).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int64(counter.Count), time.Until(counter.ExpiresAt), nil
+}
+
+func (s *postgresStore) Count(key string) (int64, time.Duration, error) {
+	var counter model.RateLimitCounters
+	err := s.db.Where("key = ? AND expires_at > ?", key, time.Now()).First(&counter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(counter.Count), time.Until(counter.ExpiresAt), nil
+}
+
+func (s *postgresStore) Reset(key string) error {
+	return s.db.Where("key = ?", key).Delete(&model.RateLimitCounters{}).Error
+}
+
+// CleanupExpired нь Postgres дээрх хугацаа дууссан тоолууруудыг устгана.
+func CleanupExpired(db *gorm.DB) error {
+	return db.Where("expires_at <= ?", time.Now()).Delete(&model.RateLimitCounters{}).Error
+}
@@ -0,0 +1,52 @@
+package form
+
+import (
+	"fmt"
+	"regexp"
+
+	"mindsteps/internal/rbac"
+)
+
+var roleCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+// RoleForm нь role үүсгэх болон засахад ашиглагдах формын бүтэц.
+// - Code: Role-ийн давтагдашгүй код (зөвхөн үүсгэх үед, жижиг үсэг, тоо, "_", 2-20 тэмдэгт)
+// - Name: Role-ийн нэр
+// - Level: Эрэмбэ. Хэрэглэгч өөрийн level-ээс өндөр role үүсгэж, оноож чадахгүй
+// - Permissions: {"lesson": ["write"]} хэлбэрийн эрхүүд
+type RoleForm struct {
+	Code        string           `json:"code" validate:"omitempty,max=20"`
+	Name        string           `json:"name" validate:"required,max=50"`
+	Level       int16            `json:"level" validate:"min=1"`
+	Permissions rbac.Permissions `json:"permissions" validate:"required"`
+}
+
+// Validate нь RoleForm дээрх өгөгдлийг шалгаж, буруу тохиолдолд алдаа буцаана.
+// creating үнэн бол code-ийг мөн шалгана.
+func (f RoleForm) Validate(creating bool) error {
+	if creating && !roleCodePattern.MatchString(f.Code) {
+		return fmt.Errorf("code нь жижиг латин үсгээр эхэлсэн, 2-20 тэмдэгт (a-z, 0-9, _) байх ёстой")
+	}
+	if f.Name == "" || len([]rune(f.Name)) > 50 {
+		return fmt.Errorf("name 1-50 тэмдэгт байх ёстой")
+	}
+	if f.Level < 1 {
+		return fmt.Errorf("level 1-ээс их байх ёстой")
+	}
+	if len(f.Permissions) == 0 {
+		return fmt.Errorf("permissions хоосон байна")
+	}
+	return f.Permissions.Validate()
+}
+
+// AssignRoleForm нь хэрэглэгчид role оноох формын бүтэц
+type AssignRoleForm struct {
+	RoleID uint `json:"role_id" validate:"required"`
+}
+
+func (f AssignRoleForm) Validate() error {
+	if f.RoleID == 0 {
+		return fmt.Errorf("role_id хоосон байна")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/rbac"
+	"mindsteps/internal/rbac/form"
+	"mindsteps/internal/rbac/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(s service.RoleService) *RoleHandler {
+	return &RoleHandler{service: s}
+}
+
+func (h *RoleHandler) List(c *fiber.Ctx) error {
+	roles, err := h.service.List()
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	result := make([]fiber.Map, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, roleResponse(&role))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"roles":   result,
+	})
+}
+
+func (h *RoleHandler) Create(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.RoleForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	role, err := h.service.Create(tokenInfo, &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Role амжилттай үүслээ",
+		"role":    roleResponse(role),
+	})
+}
+
+func (h *RoleHandler) Update(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "role id буруу байна")
+	}
+
+	var f form.RoleForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	role, err := h.service.Update(tokenInfo, uint(id), &f)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Role амжилттай шинэчлэгдлээ",
+		"role":    roleResponse(role),
+	})
+}
+
+func (h *RoleHandler) ListUserRoles(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return shared.ResponseBadRequest(c, "user id буруу байна")
+	}
+
+	owners, err := h.service.ListUserRoles(uint(userID))
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	result := make([]fiber.Map, 0, len(owners))
+	for _, owner := range owners {
+		item := fiber.Map{
+			"id":             owner.ID,
+			"role_id":        owner.RoleID,
+			"status":         owner.Status,
+			"assigned_by_id": owner.AssignedByID,
+			"created_at":     owner.CreatedAt,
+			"revoked_at":     owner.RevokedAt,
+		}
+		if owner.Role != nil {
+			item["role"] = roleResponse(owner.Role)
+		}
+		result = append(result, item)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"roles":   result,
+	})
+}
+
+func (h *RoleHandler) Assign(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return shared.ResponseBadRequest(c, "user id буруу байна")
+	}
+
+	var f form.AssignRoleForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.Assign(tokenInfo, uint(userID), &f); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Role амжилттай оноогдлоо",
+	})
+}
+
+func (h *RoleHandler) Revoke(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return shared.ResponseBadRequest(c, "user id буруу байна")
+	}
+	roleID, err := c.ParamsInt("roleId")
+	if err != nil || roleID <= 0 {
+		return shared.ResponseBadRequest(c, "role id буруу байна")
+	}
+
+	if err := h.service.Revoke(tokenInfo, uint(userID), uint(roleID)); err != nil {
+		return respondError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Role амжилттай хасагдлаа",
+	})
+}
+
+func roleResponse(role *model.Roles) fiber.Map {
+	permissions, err := rbac.ParsePermissions(role.Permissions)
+	if err != nil {
+		permissions = rbac.Permissions{}
+	}
+	return fiber.Map{
+		"id":          role.ID,
+		"code":        role.Code,
+		"name":        role.Name,
+		"level":       role.Level,
+		"permissions": permissions,
+		"created_at":  role.CreatedAt,
+		"updated_at":  role.UpdatedAt,
+	}
+}
+
+func respondError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrUserNotFound):
+		return shared.ResponseNotFound(c)
+	case errors.Is(err, service.ErrRoleLevelTooHigh), errors.Is(err, service.ErrPermissionNotHeld):
+		return shared.ResponseForbidden(c)
+	default:
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+}
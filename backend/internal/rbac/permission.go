@@ -0,0 +1,127 @@
+package rbac
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"mindsteps/database/model"
+
+	"gorm.io/datatypes"
+)
+
+// Системд ашиглагдах эрхүүд "<resource>:<action>" хэлбэртэй.
+// roles.permissions баганад {"lesson": ["write"], "cache": ["manage"]} гэж хадгална.
+const (
+	PermLessonWrite     = "lesson:write"
+	PermPlutchikWrite   = "plutchik:write"
+	PermCacheManage     = "cache:manage"
+	PermRoleManage      = "role:manage"
+	PermAPIKeyManage    = "apikey:manage"
+	PermUserImpersonate = "user:impersonate"
+	PermRetentionManage = "retention:manage"
+	PermAuditRead       = "audit:read"
+	PermErrorManage     = "error:manage"
+	// API key-ээр (ML service, тайлангийн job) хэрэглэгчийн өгөгдөл унших эрхүүд
+	PermMoodRead  = "mood:read"
+	PermStatsRead = "stats:read"
+)
+
+// Wildcard нь бүх resource эсвэл action-ийг илэрхийлнэ. {"*": ["*"]} нь бүх эрх.
+const Wildcard = "*"
+
+// Known нь roles.permissions-д зөвшөөрөгдөх эрхүүдийн жагсаалт
+var Known = []string{
+	PermLessonWrite,
+	PermPlutchikWrite,
+	PermCacheManage,
+	PermRoleManage,
+	PermAPIKeyManage,
+	PermUserImpersonate,
+	PermRetentionManage,
+	PermAuditRead,
+	PermErrorManage,
+	PermMoodRead,
+	PermStatsRead,
+}
+
+// Permissions нь resource -> actions бүтэц
+type Permissions map[string][]string
+
+// ParsePermissions нь roles.permissions JSON-ийг задална. Хоосон бол хоосон map буцаана.
+func ParsePermissions(raw datatypes.JSON) (Permissions, error) {
+	perms := Permissions{}
+	if len(raw) == 0 {
+		return perms, nil
+	}
+	if err := json.Unmarshal(raw, &perms); err != nil {
+		return nil, fmt.Errorf("permissions JSON буруу байна: %w", err)
+	}
+	return perms, nil
+}
+
+// Validate нь зөвхөн Known жагсаалтад байгаа эсвэл wildcard эрхийг зөвшөөрнө.
+func (p Permissions) Validate() error {
+	for resource, actions := range p {
+		if len(actions) == 0 {
+			return fmt.Errorf("%s-д action заагаагүй байна", resource)
+		}
+		for _, action := range actions {
+			if !isKnown(resource, action) {
+				return fmt.Errorf("тодорхойгүй эрх: %s:%s", resource, action)
+			}
+		}
+	}
+	return nil
+}
+
+// Flatten нь {"lesson": ["write"]} -ийг ["lesson:write"] болгоно.
+func (p Permissions) Flatten() []string {
+	var result []string
+	for resource, actions := range p {
+		for _, action := range actions {
+			result = append(result, resource+":"+action)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Resolve нь хэрэглэгчийн идэвхтэй role-уудаас token-д орох role код,
+// давхардалгүй эрхүүд болон хамгийн өндөр level-ийг гаргана.
+func Resolve(roles []model.Roles) (codes []string, permissions []string, level int, err error) {
+	seen := map[string]bool{}
+	for _, role := range roles {
+		codes = append(codes, role.Code)
+		if int(role.Level) > level {
+			level = int(role.Level)
+		}
+
+		perms, err := ParsePermissions(role.Permissions)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("role %s: %w", role.Code, err)
+		}
+		for _, perm := range perms.Flatten() {
+			if !seen[perm] {
+				seen[perm] = true
+				permissions = append(permissions, perm)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return codes, permissions, level, nil
+}
+
+func isKnown(resource, action string) bool {
+	if resource == Wildcard {
+		return action == Wildcard
+	}
+	for _, known := range Known {
+		knownResource, knownAction, _ := strings.Cut(known, ":")
+		if knownResource == resource && (action == Wildcard || knownAction == action) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OwnerStatusActive  = "active"
+	OwnerStatusRevoked = "revoked"
+)
+
+type RoleRepository interface {
+	List() ([]model.Roles, error)
+	FindByID(id uint) (*model.Roles, error)
+	FindByCode(code string) (*model.Roles, error)
+	Create(role *model.Roles) error
+	Update(role *model.Roles) error
+	FindActiveByOwner(userID uint) ([]model.Roles, error)
+	ListAssignments(userID uint) ([]model.RoleOwners, error)
+	IsAssigned(userID, roleID uint) (bool, error)
+	Assign(owner *model.RoleOwners) error
+	Revoke(userID, roleID uint) (bool, error)
+}
+
+type roleRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepo{db: db}
+}
+
+func (r *roleRepo) List() ([]model.Roles, error) {
+	var roles []model.Roles
+	err := r.db.Order("level DESC, id").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepo) FindByID(id uint) (*model.Roles, error) {
+	var role model.Roles
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepo) FindByCode(code string) (*model.Roles, error) {
+	var role model.Roles
+	if err := r.db.Where("code = ?", code).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepo) Create(role *model.Roles) error {
+	omit := []string{"CreatedBy", "UpdatedBy"}
+	if role.CreatedByID == 0 {
+		omit = append(omit, "CreatedByID", "UpdatedByID")
+	}
+	return r.db.Omit(omit...).Create(role).Error
+}
+
+func (r *roleRepo) Update(role *model.Roles) error {
+	return r.db.Model(role).Select("name", "level", "permissions", "updated_by_id", "updated_at").Updates(role).Error
+}
+
+// FindActiveByOwner нь хэрэглэгчид идэвхтэй оноогдсон role-уудыг буцаана.
+func (r *roleRepo) FindActiveByOwner(userID uint) ([]model.Roles, error) {
+	var roles []model.Roles
+	err := r.db.
+		Joins("JOIN "+model.TableNameRoleOwners+" ro ON ro.role_id = "+model.TableNameRoles+".id").
+		Where("ro.owner_id = ? AND ro.status = ?", userID, OwnerStatusActive).
+		Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepo) ListAssignments(userID uint) ([]model.RoleOwners, error) {
+	var owners []model.RoleOwners
+	err := r.db.Preload("Role").
+		Where("owner_id = ?", userID).
+		Order("created_at DESC").
+		Find(&owners).Error
+	return owners, err
+}
+
+func (r *roleRepo) IsAssigned(userID, roleID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RoleOwners{}).
+		Where("owner_id = ? AND role_id = ? AND status = ?", userID, roleID, OwnerStatusActive).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Assign нь шинэ оноолт үүсгэнэ. CLI-аас оноосон үед assigned_by_id хоосон үлдэнэ.
+func (r *roleRepo) Assign(owner *model.RoleOwners) error {
+	omit := []string{"Role", "Owner", "AssignedBy", "RevokedAt"}
+	if owner.AssignedByID == 0 {
+		omit = append(omit, "AssignedByID")
+	}
+	return r.db.Omit(omit...).Create(owner).Error
+}
+
+// Revoke нь идэвхтэй оноолтыг revoked болгоно. Оноолт байгаагүй бол false буцаана.
+func (r *roleRepo) Revoke(userID, roleID uint) (bool, error) {
+	result := r.db.Model(&model.RoleOwners{}).
+		Where("owner_id = ? AND role_id = ? AND status = ?", userID, roleID, OwnerStatusActive).
+		Updates(map[string]interface{}{
+			"status":     OwnerStatusRevoked,
+			"revoked_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/rbac"
+	"mindsteps/internal/rbac/form"
+	"mindsteps/internal/rbac/repository"
+	userRepo "mindsteps/internal/user/repository"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type RoleService interface {
+	List() ([]model.Roles, error)
+	Create(actor *auth.Token, form *form.RoleForm) (*model.Roles, error)
+	Update(actor *auth.Token, id uint, form *form.RoleForm) (*model.Roles, error)
+	ListUserRoles(userID uint) ([]model.RoleOwners, error)
+	Assign(actor *auth.Token, userID uint, form *form.AssignRoleForm) error
+	Revoke(actor *auth.Token, userID, roleID uint) error
+}
+
+var (
+	ErrRoleNotFound        = errors.New("role олдсонгүй")
+	ErrUserNotFound        = errors.New("хэрэглэгч олдсонгүй")
+	ErrRoleLevelTooHigh    = errors.New("өөрийн level-ээс өндөр role-д хандах эрхгүй")
+	ErrRoleCodeTaken       = errors.New("ийм code-той role аль хэдийн бүртгэлтэй байна")
+	ErrRoleAlreadyAssigned = errors.New("хэрэглэгчид энэ role аль хэдийн оноогдсон байна")
+	ErrRoleNotAssigned     = errors.New("хэрэглэгчид энэ role оноогдоогүй байна")
+	ErrRevokeOwnRole       = errors.New("өөрөөсөө role хасах боломжгүй")
+	ErrAssignOwnRole       = errors.New("өөртөө role оноох боломжгүй")
+	ErrPermissionNotHeld   = errors.New("өөрт байхгүй эрхийг role-д олгох боломжгүй")
+)
+
+type roleService struct {
+	repo     repository.RoleRepository
+	userRepo userRepo.UserRepository
+	authRepo authRepo.AuthRepository
+}
+
+func NewRoleService(repo repository.RoleRepository, userRepo userRepo.UserRepository, authRepo authRepo.AuthRepository) RoleService {
+	return &roleService{repo: repo, userRepo: userRepo, authRepo: authRepo}
+}
+
+func (s *roleService) List() ([]model.Roles, error) {
+	return s.repo.List()
+}
+
+func (s *roleService) Create(actor *auth.Token, f *form.RoleForm) (*model.Roles, error) {
+	if err := f.Validate(true); err != nil {
+		return nil, err
+	}
+	if int(f.Level) > actor.Level {
+		return nil, ErrRoleLevelTooHigh
+	}
+	if err := checkGrant(actor, f.Permissions); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByCode(f.Code); err == nil {
+		return nil, ErrRoleCodeTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	permissions, err := json.Marshal(f.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	role := &model.Roles{
+		Code:        f.Code,
+		Name:        f.Name,
+		Level:       f.Level,
+		Permissions: datatypes.JSON(permissions),
+		CreatedByID: actor.UserID,
+		CreatedAt:   now,
+		UpdatedByID: actor.UserID,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Update нь role-ийн нэр, level, эрхийг өөрчилнө. Code өөрчлөгдөхгүй.
+// Шинэ эрх role эзэмшигчдийн дараагийн token шинэчлэлтээс хэрэгжинэ.
+func (s *roleService) Update(actor *auth.Token, id uint, f *form.RoleForm) (*model.Roles, error) {
+	if err := f.Validate(false); err != nil {
+		return nil, err
+	}
+
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if int(role.Level) > actor.Level || int(f.Level) > actor.Level {
+		return nil, ErrRoleLevelTooHigh
+	}
+	if err := checkGrant(actor, f.Permissions); err != nil {
+		return nil, err
+	}
+
+	permissions, err := json.Marshal(f.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Name = f.Name
+	role.Level = f.Level
+	role.Permissions = datatypes.JSON(permissions)
+	role.UpdatedByID = actor.UserID
+	role.UpdatedAt = time.Now()
+	if err := s.repo.Update(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleService) ListUserRoles(userID uint) ([]model.RoleOwners, error) {
+	return s.repo.ListAssignments(userID)
+}
+
+// Assign нь role-ийг хэрэглэгчид онооно. Өөртөө оноох, өөрт байхгүй эрхтэй role оноохыг хориглоно.
+func (s *roleService) Assign(actor *auth.Token, userID uint, f *form.AssignRoleForm) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if userID == actor.UserID {
+		return ErrAssignOwnRole
+	}
+
+	role, err := s.findRole(f.RoleID)
+	if err != nil {
+		return err
+	}
+	if int(role.Level) > actor.Level {
+		return ErrRoleLevelTooHigh
+	}
+	permissions, err := rbac.ParsePermissions(role.Permissions)
+	if err != nil {
+		return err
+	}
+	if err := checkGrant(actor, permissions); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	assigned, err := s.repo.IsAssigned(userID, role.ID)
+	if err != nil {
+		return err
+	}
+	if assigned {
+		return ErrRoleAlreadyAssigned
+	}
+
+	return s.repo.Assign(&model.RoleOwners{
+		RoleID:       role.ID,
+		OwnerID:      userID,
+		Status:       repository.OwnerStatusActive,
+		AssignedByID: actor.UserID,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// Revoke нь role-ийг хасаад хэрэглэгчийн бүх session-г хаана.
+// Ингэснээр хуучин эрхтэй access token дуусахыг хүлээлгүйгээр эрх шууд цуцлагдана.
+func (s *roleService) Revoke(actor *auth.Token, userID, roleID uint) error {
+	if userID == actor.UserID {
+		return ErrRevokeOwnRole
+	}
+
+	role, err := s.findRole(roleID)
+	if err != nil {
+		return err
+	}
+	if int(role.Level) > actor.Level {
+		return ErrRoleLevelTooHigh
+	}
+
+	revoked, err := s.repo.Revoke(userID, role.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrRoleNotAssigned
+	}
+
+	sessionIDs, err := s.authRepo.RevokeUserSessions(userID, "role_revoked")
+	if err != nil {
+		return fmt.Errorf("role хасагдсан боловч session хаахад алдаа гарлаа: %w", err)
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	return nil
+}
+
+// checkGrant: role-оор зөвхөн өөрт байгаа эрхээ л олгоно ({"*":["*"]} г.м-ээр эрхээ өсгөхөөс сэргийлнэ).
+func checkGrant(actor *auth.Token, permissions rbac.Permissions) error {
+	for _, permission := range permissions.Flatten() {
+		if !auth.HasPermission(actor.Permissions, permission) {
+			return ErrPermissionNotHeld
+		}
+	}
+	return nil
+}
+
+func (s *roleService) findRole(id uint) (*model.Roles, error) {
+	role, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
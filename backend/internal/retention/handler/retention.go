@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"mindsteps/internal/retention/service"
+	"mindsteps/internal/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RetentionHandler struct {
+	service service.RetentionService
+}
+
+func NewRetentionHandler(s service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{service: s}
+}
+
+func (h *RetentionHandler) ListPolicies(c *fiber.Ctx) error {
+	policies, err := h.service.Policies()
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"policies": policies,
+	})
+}
+
+// Report нь идэвхтэй policy бүрийг dry-run горимоор ажиллуулж юу өөрчлөгдөхийг харуулна.
+func (h *RetentionHandler) Report(c *fiber.Ctx) error {
+	reports, err := h.service.Run(true)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"reports": reports,
+	})
+}
+
+// Run нь идэвхтэй policy-уудыг хуваарь хүлээлгүй одоо хэрэглэнэ. ?dry_run=true үед Report-тэй адил.
+func (h *RetentionHandler) Run(c *fiber.Ctx) error {
+	dryRun := c.QueryBool("dry_run", false)
+
+	reports, err := h.service.Run(dryRun)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"reports": reports,
+	})
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mindsteps/database/model"
+
+	"gorm.io/gorm"
+)
+
+// deleted_data_log-д бичигдэх утгууд
+const (
+	MethodDelete    = "delete"
+	MethodAnonymize = "anonymize"
+	// MethodArchive нь мөрийг deleted_data_log.record_data руу зөөсөн (сэргээх боломжтой) бичлэг
+	MethodArchive = "archive"
+
+	deletedReason = "retention_policy"
+)
+
+// Target нь retention policy хэрэглэж болох хүснэгт.
+// TimeColumn-оор мөрийн насыг тооцно. Where нь нэмэлт нөхцөл (хоосон байж болно).
+// Anonymize хоосон бол тухайн хүснэгтэд anonymize арга хэрэглэх боломжгүй.
+type Target struct {
+	Table      string
+	TimeColumn string
+	Where      string
+	Anonymize  map[string]interface{}
+	// Anonymized нь аль хэдийн нууцлагдсан мөрийг дахин авахгүйн тулд хэрэглэгдэх нөхцөл
+	Anonymized string
+}
+
+// Targets нь data_retention_policies.table_name (schema-гүй) -> Target.
+// Policy-д бичигдсэн ч энд байхгүй хүснэгтийг хөдөлгөхгүй.
+var Targets = map[string]Target{
+	"auth_otp": {
+		Table:      model.TableNameAuthOTP,
+		TimeColumn: "expired_at",
+	},
+	"revoked_tokens": {
+		// Token-ий хугацаа дууссаны дараа хар жагсаалтад байх шаардлагагүй
+		Table:      model.TableNameRevokedTokens,
+		TimeColumn: "expires_at",
+	},
+	"user_data_access_log": {
+		Table:      model.TableNameUserDataAccessLog,
+		TimeColumn: "accessed_at",
+	},
+	"user_sessions": {
+		// user_data_access_log.session_id гадаад түлхүүртэй тул тэр лог нь эхэлж цэвэрлэгдэнэ
+		Table:      model.TableNameUserSessions,
+		TimeColumn: "expires_at",
+		Where:      "NOT EXISTS (SELECT 1 FROM " + model.TableNameUserDataAccessLog + " l WHERE l.session_id = t.id)",
+	},
+	"error_logs": {
+		Table:      model.TableNameErrorLogs,
+		TimeColumn: "created_at",
+		Anonymize: map[string]interface{}{
+			"user_id":      nil,
+			"ip_address":   nil,
+			"user_agent":   nil,
+			"request_body": nil,
+		},
+		Anonymized: "t.user_id IS NULL AND t.ip_address IS NULL AND t.user_agent IS NULL AND t.request_body IS NULL",
+	},
+}
+
+// LookupTarget нь "mindstep.auth_otp" болон "auth_otp" хэлбэрийг хоёуланг нь хүлээн авна.
+func LookupTarget(table string) (Target, bool) {
+	target, ok := Targets[strings.TrimPrefix(table, "mindstep.")]
+	return target, ok
+}
+
+type RetentionRepository interface {
+	Policies() ([]model.DataRetentionPolicies, error)
+	DuePolicies(now time.Time) ([]model.DataRetentionPolicies, error)
+	MarkCleanup(policyID int, lastAt, nextAt time.Time) error
+	Count(target Target, method string, cutoff time.Time) (int64, error)
+	Archive(target Target, cutoff time.Time, retentionDays, limit int) (int64, error)
+	Remove(target Target, method string, cutoff time.Time, limit int) (int64, error)
+	CountExpiredArchives(target Target, now time.Time) (int64, error)
+	DropExpiredArchives(target Target, now time.Time, limit int) (int64, error)
+}
+
+type retentionRepo struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (r *retentionRepo) Policies() ([]model.DataRetentionPolicies, error) {
+	var policies []model.DataRetentionPolicies
+	err := r.db.Order("id").Find(&policies).Error
+	return policies, err
+}
+
+// DuePolicies нь идэвхтэй бөгөөд цэвэрлэх хугацаа нь болсон (эсвэл хэзээ ч ажиллаагүй) policy-г буцаана.
+func (r *retentionRepo) DuePolicies(now time.Time) ([]model.DataRetentionPolicies, error) {
+	var policies []model.DataRetentionPolicies
+	err := r.db.Where("is_active = ? AND (next_cleanup_at IS NULL OR next_cleanup_at <= ?)", true, now).
+		Order("id").
+		Find(&policies).Error
+	return policies, err
+}
+
+func (r *retentionRepo) MarkCleanup(policyID int, lastAt, nextAt time.Time) error {
+	return r.db.Model(&model.DataRetentionPolicies{}).Where("id = ?", policyID).Updates(map[string]interface{}{
+		"last_cleanup_at": lastAt,
+		"next_cleanup_at": nextAt,
+		"updated_at":      time.Now(),
+	}).Error
+}
+
+// expiredWhere нь target-ийн cutoff-оос хуучин мөрүүдийн нөхцөл. Хүснэгт "t" нэртэй.
+func expiredWhere(target Target, method string) string {
+	where := "t." + target.TimeColumn + " < @cutoff"
+	if target.Where != "" {
+		where += " AND " + target.Where
+	}
+	if method == MethodAnonymize {
+		where += " AND NOT (" + target.Anonymized + ")"
+	}
+	return where
+}
+
+func (r *retentionRepo) Count(target Target, method string, cutoff time.Time) (int64, error) {
+	var count int64
+	err := r.db.Raw(
+		fmt.Sprintf("SELECT count(*) FROM %s t WHERE %s", target.Table, expiredWhere(target, method)),
+		map[string]interface{}{"cutoff": cutoff},
+	).Scan(&count).Error
+	return count, err
+}
+
+// Archive нь cutoff-оос хуучин мөрүүдийг deleted_data_log.record_data руу зөөнө.
+// Архив нь мөрийн огнооноос retentionDays хоногийн дараа DropExpiredArchives-аар устна.
+func (r *retentionRepo) Archive(target Target, cutoff time.Time, retentionDays, limit int) (int64, error) {
+	sql := fmt.Sprintf(`WITH batch AS (
+	SELECT t.* FROM %[1]s t WHERE %[2]s ORDER BY t.id LIMIT @limit FOR UPDATE SKIP LOCKED
+), changed AS (
+	DELETE FROM %[1]s t USING batch WHERE t.id = batch.id RETURNING batch.*
+)
+INSERT INTO %[3]s (user_id, table_name, record_id, record_data, deleted_reason, deletion_method, deleted_at, can_recover, recovery_expires_at)
+SELECT changed.user_id, @table, changed.id, to_jsonb(changed), @reason, @method, now(), true, changed.%[4]s + make_interval(days => @days)
+FROM changed`, target.Table, expiredWhere(target, MethodArchive), model.TableNameDeletedDataLog, target.TimeColumn)
+
+	result := r.db.Exec(sql, map[string]interface{}{
+		"cutoff": cutoff,
+		"limit":  limit,
+		"table":  target.Table,
+		"reason": deletedReason,
+		"method": MethodArchive,
+		"days":   retentionDays,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// Remove нь cutoff-оос хуучин мөрүүдийг устгах эсвэл нууцлаад мөр бүрийг deleted_data_log-д бичнэ.
+// Нэг дуудлага нэг transaction (нэг SQL) тул лог, өөрчлөлт хоёр салахгүй.
+func (r *retentionRepo) Remove(target Target, method string, cutoff time.Time, limit int) (int64, error) {
+	params := map[string]interface{}{
+		"cutoff": cutoff,
+		"limit":  limit,
+		"table":  target.Table,
+		"reason": deletedReason,
+		"method": method,
+	}
+
+	var change string
+	switch method {
+	case MethodDelete:
+		change = fmt.Sprintf("DELETE FROM %s t USING batch WHERE t.id = batch.id RETURNING batch.id, batch.user_id", target.Table)
+	case MethodAnonymize:
+		if len(target.Anonymize) == 0 {
+			return 0, fmt.Errorf("%s хүснэгтэд anonymize арга хэрэглэх боломжгүй", target.Table)
+		}
+		set := make([]string, 0, len(target.Anonymize))
+		for column, value := range target.Anonymize {
+			set = append(set, fmt.Sprintf("%s = @set_%s", column, column))
+			params["set_"+column] = value
+		}
+		change = fmt.Sprintf("UPDATE %s t SET %s FROM batch WHERE t.id = batch.id RETURNING batch.id, batch.user_id", target.Table, strings.Join(set, ", "))
+	default:
+		return 0, fmt.Errorf("deletion_method буруу байна: %s", method)
+	}
+
+	sql := fmt.Sprintf(`WITH batch AS (
+	SELECT t.id, t.user_id FROM %[1]s t WHERE %[2]s ORDER BY t.id LIMIT @limit FOR UPDATE SKIP LOCKED
+), changed AS (
+	%[3]s
+)
+INSERT INTO %[4]s (user_id, table_name, record_id, deleted_reason, deletion_method, deleted_at, can_recover)
+SELECT changed.user_id, @table, changed.id, @reason, @method, now(), false
+FROM changed`, target.Table, expiredWhere(target, method), change, model.TableNameDeletedDataLog)
+
+	result := r.db.Exec(sql, params)
+	return result.RowsAffected, result.Error
+}
+
+func expiredArchives(db *gorm.DB, target Target, now time.Time) *gorm.DB {
+	return db.Unscoped().Model(&model.DeletedDataLog{}).
+		Where("table_name = ? AND deletion_method = ? AND can_recover = ? AND recovery_expires_at <= ?", target.Table, MethodArchive, true, now)
+}
+
+func (r *retentionRepo) CountExpiredArchives(target Target, now time.Time) (int64, error) {
+	var count int64
+	err := expiredArchives(r.db, target, now).Count(&count).Error
+	return count, err
+}
+
+// DropExpiredArchives нь хадгалах хугацаа нь дууссан архивын агуулгыг арилгана.
+// Лог мөр нь үлдэнэ, зөвхөн record_data нь устна.
+func (r *retentionRepo) DropExpiredArchives(target Target, now time.Time, limit int) (int64, error) {
+	ids := expiredArchives(r.db, target, now).Select("id").Order("id").Limit(limit)
+	result := r.db.Unscoped().Model(&model.DeletedDataLog{}).
+		Where("id IN (?)", ids).
+		Updates(map[string]interface{}{"record_data": nil, "can_recover": false})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/retention/repository"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	// cleanupInterval нь policy дахин ажиллах хугацаа
+	cleanupInterval = 24 * time.Hour
+	// batchSize нь нэг SQL-ээр өөрчлөх мөрийн дээд тоо
+	batchSize = 500
+	// maxBatches нь нэг ажиллагаанд policy тус бүрд ажиллуулах batch-ийн дээд тоо.
+	// Хэтэрвэл үлдсэнийг дараагийн ажиллагаа үргэлжлүүлнэ.
+	maxBatches = 20
+)
+
+// Report нь нэг policy-ийн үр дүн. DryRun үед тоонууд нь өөрчлөгдөх байсан мөрийн тоо.
+type Report struct {
+	PolicyID        int    `json:"policy_id"`
+	Table           string `json:"table"`
+	DryRun          bool   `json:"dry_run"`
+	Method          string `json:"method"`
+	Archived        int64  `json:"archived"`
+	Removed         int64  `json:"removed"`
+	ArchivesExpired int64  `json:"archives_expired"`
+	// Complete нь хугацаа хэтэрсэн бүх мөр боловсруулагдсан эсэх (maxBatches-д хүрээгүй)
+	Complete bool   `json:"complete"`
+	Error    string `json:"error,omitempty"`
+}
+
+type RetentionService interface {
+	Policies() ([]model.DataRetentionPolicies, error)
+	RunDue() ([]Report, error)
+	Run(dryRun bool) ([]Report, error)
+}
+
+type retentionService struct {
+	repo repository.RetentionRepository
+}
+
+func NewRetentionService(repo repository.RetentionRepository) RetentionService {
+	return &retentionService{repo: repo}
+}
+
+func (s *retentionService) Policies() ([]model.DataRetentionPolicies, error) {
+	return s.repo.Policies()
+}
+
+// RunDue нь цэвэрлэх хугацаа нь болсон идэвхтэй policy-уудыг хэрэглэнэ (scheduler).
+func (s *retentionService) RunDue() ([]Report, error) {
+	policies, err := s.repo.DuePolicies(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(policies, false), nil
+}
+
+// Run нь бүх идэвхтэй policy-г хуваарь харгалзахгүй хэрэглэнэ. dryRun үед юу ч өөрчлөхгүй,
+// зөвхөн өөрчлөгдөх мөрийн тоог буцаана.
+func (s *retentionService) Run(dryRun bool) ([]Report, error) {
+	policies, err := s.repo.Policies()
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]model.DataRetentionPolicies, 0, len(policies))
+	for _, policy := range policies {
+		if policy.IsActive {
+			active = append(active, policy)
+		}
+	}
+	return s.apply(active, dryRun), nil
+}
+
+func (s *retentionService) apply(policies []model.DataRetentionPolicies, dryRun bool) []Report {
+	reports := make([]Report, 0, len(policies))
+	for i := range policies {
+		policy := &policies[i]
+		report := s.applyPolicy(policy, dryRun)
+		if report.Error != "" {
+			log.Errorf("retention policy %d (%s) алдаа: %s", policy.ID, policy.TableName_, report.Error)
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func (s *retentionService) applyPolicy(policy *model.DataRetentionPolicies, dryRun bool) Report {
+	now := time.Now()
+	report := Report{PolicyID: policy.ID, Table: policy.TableName_, DryRun: dryRun, Method: deletionMethod(policy)}
+
+	target, err := validatePolicy(policy)
+	if err == nil {
+		if dryRun {
+			err = s.count(target, policy, now, &report)
+		} else {
+			err = s.execute(target, policy, now, &report)
+		}
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if dryRun {
+		return report
+	}
+
+	// Алдаатай эсвэл дуусаагүй бол дараагийн ажиллагаанд дахин оролдоно
+	next := now.Add(cleanupInterval)
+	if err != nil || !report.Complete {
+		next = now
+	}
+	if err := s.repo.MarkCleanup(policy.ID, now, next); err != nil {
+		log.Errorf("retention policy %d төлөв шинэчлэхэд алдаа: %v", policy.ID, err)
+	}
+	return report
+}
+
+func deletionMethod(policy *model.DataRetentionPolicies) string {
+	if policy.DeletionMethod == "" {
+		return repository.MethodDelete
+	}
+	return policy.DeletionMethod
+}
+
+// archiveEnabled: archive_after_days нь retention_days-ээс бага үед л мөрийг эхлээд архивлана.
+func archiveEnabled(policy *model.DataRetentionPolicies) bool {
+	return policy.ArchiveAfterDays > 0 && policy.ArchiveAfterDays < policy.RetentionDays
+}
+
+func validatePolicy(policy *model.DataRetentionPolicies) (repository.Target, error) {
+	target, ok := repository.LookupTarget(policy.TableName_)
+	if !ok {
+		return target, fmt.Errorf("%s хүснэгтэд retention policy хэрэглэх боломжгүй", policy.TableName_)
+	}
+	if policy.RetentionDays <= 0 {
+		return target, fmt.Errorf("retention_days 0-ээс их байх ёстой")
+	}
+	switch deletionMethod(policy) {
+	case repository.MethodDelete:
+	case repository.MethodAnonymize:
+		if len(target.Anonymize) == 0 {
+			return target, fmt.Errorf("%s хүснэгтэд anonymize арга хэрэглэх боломжгүй", policy.TableName_)
+		}
+	default:
+		return target, fmt.Errorf("deletion_method буруу байна: %s (delete эсвэл anonymize)", policy.DeletionMethod)
+	}
+	return target, nil
+}
+
+func daysAgo(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+func (s *retentionService) count(target repository.Target, policy *model.DataRetentionPolicies, now time.Time, report *Report) error {
+	var err error
+	if archiveEnabled(policy) {
+		if report.Archived, err = s.repo.Count(target, repository.MethodArchive, daysAgo(now, policy.ArchiveAfterDays)); err != nil {
+			return err
+		}
+	}
+	if policy.AutoDelete {
+		if report.Removed, err = s.repo.Count(target, report.Method, daysAgo(now, policy.RetentionDays)); err != nil {
+			return err
+		}
+		if report.ArchivesExpired, err = s.repo.CountExpiredArchives(target, now); err != nil {
+			return err
+		}
+	}
+	report.Complete = true
+	return nil
+}
+
+// execute: эхлээд хадгалах хугацаа дууссан мөрийг устгаж (эсвэл нууцалж), дараа нь
+// архивлах насанд хүрсэн мөрийг архивлана. auto_delete=false үед зөвхөн архивлана.
+func (s *retentionService) execute(target repository.Target, policy *model.DataRetentionPolicies, now time.Time, report *Report) error {
+	budget := maxBatches
+	report.Complete = true
+
+	if policy.AutoDelete {
+		cutoff := daysAgo(now, policy.RetentionDays)
+		if err := runBatches(&budget, &report.Removed, &report.Complete, func() (int64, error) {
+			return s.repo.Remove(target, report.Method, cutoff, batchSize)
+		}); err != nil {
+			return err
+		}
+		if err := runBatches(&budget, &report.ArchivesExpired, &report.Complete, func() (int64, error) {
+			return s.repo.DropExpiredArchives(target, now, batchSize)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if archiveEnabled(policy) {
+		cutoff := daysAgo(now, policy.ArchiveAfterDays)
+		if err := runBatches(&budget, &report.Archived, &report.Complete, func() (int64, error) {
+			return s.repo.Archive(target, cutoff, policy.RetentionDays, batchSize)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBatches нь batch дутуу ирэх хүртэл step-ийг давтаж total-д нэмнэ.
+// budget дуусч зогсвол complete-ийг false болгоно.
+func runBatches(budget *int, total *int64, complete *bool, step func() (int64, error)) error {
+	for *budget > 0 {
+		*budget--
+		n, err := step()
+		*total += n
+		if err != nil {
+			return err
+		}
+		if n < batchSize {
+			return nil
+		}
+	}
+	*complete = false
+	return nil
+}
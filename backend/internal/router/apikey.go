@@ -0,0 +1,29 @@
+package router
+
+import (
+	"mindsteps/database"
+	apikeyHandler "mindsteps/internal/apikey/handler"
+	apikeyRepo "mindsteps/internal/apikey/repository"
+	apikeyService "mindsteps/internal/apikey/service"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/rbac"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAPIKeyRoutes(api fiber.Router) {
+	repo := apikeyRepo.NewAPIKeyRepository(database.DB)
+	svc := apikeyService.NewAPIKeyService(repo, ratelimit.New(database.DB))
+	h := apikeyHandler.NewAPIKeyHandler(svc)
+
+	// X-Api-Key header-ийг auth middleware-үүд энэ service-ээр шалгана
+	auth.APIKeys = svc
+
+	canManage := auth.RequirePermission(rbac.PermAPIKeyManage)
+
+	api.Get("/admin/api-keys", canManage, h.List)
+	api.Post("/admin/api-keys", canManage, h.Create)
+	api.Post("/admin/api-keys/:id/rotate", canManage, h.Rotate)
+	api.Delete("/admin/api-keys/:id", canManage, h.Revoke)
+}
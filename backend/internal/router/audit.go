@@ -0,0 +1,23 @@
+package router
+
+import (
+	"mindsteps/database"
+	auditHandler "mindsteps/internal/audit/handler"
+	auditRepo "mindsteps/internal/audit/repository"
+	auditService "mindsteps/internal/audit/service"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/rbac"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAuditRoutes(api fiber.Router) {
+	repo := auditRepo.NewAuditRepository(database.DB)
+	svc := auditService.NewAuditService(repo)
+	h := auditHandler.NewAuditHandler(svc)
+
+	canRead := auth.RequirePermission(rbac.PermAuditRead)
+
+	api.Get("/admin/audit-logs", canRead, h.List)
+	api.Get("/admin/audit-logs/:id", canRead, h.Get)
+}
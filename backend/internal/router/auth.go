@@ -1,12 +1,20 @@
 package router
 
 import (
+	"context"
 	"mindsteps/database"
 	"mindsteps/internal/auth"
 	authHandler "mindsteps/internal/auth/handler"
 	authRepo "mindsteps/internal/auth/repository"
 	authService "mindsteps/internal/auth/service"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/oidc"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/rbac"
+	rbacRepo "mindsteps/internal/rbac/repository"
+	"mindsteps/internal/scheduler"
 	userRepo "mindsteps/internal/user/repository"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,18 +22,60 @@ import (
 func RegisterAuthRoutes(api fiber.Router) {
 	userRepository := userRepo.NewUserRepository(database.DB)
 	authRepository := authRepo.NewAuthRepository(database.DB)
-	authSvc := authService.NewAuthService(userRepository, authRepository)
+	roleRepository := rbacRepo.NewRoleRepository(database.DB)
+	authSvc := authService.NewAuthService(userRepository, authRepository, roleRepository, mailer.Get(), ratelimit.New(database.DB))
 	h := authHandler.NewAuthHandler(authSvc)
+	auth.SecondFactor = authSvc
+	oidcHandler := authHandler.NewOIDCHandler(authSvc, oidc.Get())
 
 	authGroup := api.Group("/auth")
 
 	// Public routes
 	authGroup.Post("/register", h.Register)
 	authGroup.Post("/login", h.Login)
+	authGroup.Post("/refresh", h.Refresh)
 	authGroup.Post("/forgot-password", h.ForgotPassword)
 	authGroup.Post("/reset-password", h.ResetPassword)
 	authGroup.Post("/verify-otp", h.VerifyOTP)
+	authGroup.Post("/resend-otp", h.ResendOTP)
+	authGroup.Post("/not-me", h.NotMe)
+	authGroup.Post("/magic-link", h.SendMagicLink)
+	authGroup.Post("/magic-link/verify", h.LoginWithMagicLink)
+	authGroup.Post("/email-change/cancel", h.CancelEmailChange)
+
+	// Google, Apple-аар нэвтрэх (Apple callback-ийг POST-оор илгээнэ)
+	authGroup.Get("/oidc/:provider", oidcHandler.Start)
+	authGroup.Get("/oidc/:provider/callback", oidcHandler.Callback)
+	authGroup.Post("/oidc/:provider/callback", oidcHandler.Callback)
+
+	// Хоёр шатат баталгаажуулалт (TOTP). Login mfa_token буцаасан бол /mfa/verify-ээр session авна.
+	authGroup.Post("/mfa/verify", h.VerifyMFA)
 
 	// Protected routes
-	authGroup.Post("/logout", auth.TokenMiddleware, h.Logout)
+	authGroup.Post("/logout", auth.UnverifiedMiddleware, h.Logout)
+	authGroup.Post("/mfa/setup", auth.TokenMiddleware, auth.DenyImpersonation, h.SetupMFA)
+	authGroup.Post("/mfa/enable", auth.TokenMiddleware, auth.DenyImpersonation, h.EnableMFA)
+	authGroup.Post("/mfa/disable", auth.OtpMiddleware, auth.DenyImpersonation, h.DisableMFA)
+	authGroup.Post("/mfa/recovery-codes", auth.OtpMiddleware, auth.DenyImpersonation, h.RegenerateRecoveryCodes)
+
+	// Email солих: код шинэ хаяг руу, цуцлах холбоос хуучин хаяг руу очно.
+	// Буруу бичсэн email-ээ засах боломжтой байхаар баталгаажаагүй хэрэглэгчийг ч нэвтрүүлнэ.
+	authGroup.Post("/email-change", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.RequestEmailChange)
+	authGroup.Post("/email-change/confirm", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.ConfirmEmailChange)
+
+	// Тусламжийн ажилтан хэрэглэгчийн нэрийн өмнөөс (анхдагчаар зөвхөн уншиж) нэвтрэх.
+	// Хүсэлт бүр system_audit_log-д бичигдэнэ. Гарах (/auth/logout)-аар эрт дуусгаж болно.
+	api.Post("/admin/users/:id/impersonate", auth.RequirePermission(rbac.PermUserImpersonate), auth.DenyImpersonation, h.Impersonate)
+
+	// Хугацаа дууссан OTP болон rate limit тоолуурыг цэвэрлэнэ
+	scheduler.Register(scheduler.Job{
+		Name:     "otp-cleanup",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			if err := authRepository.CleanupExpiredOTPs(); err != nil {
+				return err
+			}
+			return ratelimit.CleanupExpired(database.DB)
+		},
+	})
 }
@@ -6,14 +6,15 @@ import (
 
 	"mindsteps/internal/auth"
 	"mindsteps/internal/cache/handler"
+	"mindsteps/internal/rbac"
 )
 
 // RegisterCacheRoutes нь cache удирдлагын route-уудыг бүртгэнэ
 func RegisterCacheRoutes(api fiber.Router) {
 	h := handler.NewCacheHandler()
 
-	// Admin эрхтэй байх ёстой
-	cache := api.Group("/cache", auth.TokenMiddleware) // auth.AdminMiddleware
+	// cache:manage эрхтэй admin л хандана
+	cache := api.Group("/cache", auth.RequirePermission(rbac.PermCacheManage))
 
 	// Cache цэвэрлэх endpoints
 	cache.Delete("/clear", h.ClearCache)                 // Бүх cache цэвэрлэх
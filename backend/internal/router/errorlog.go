@@ -0,0 +1,26 @@
+package router
+
+import (
+	"mindsteps/database"
+	"mindsteps/internal/auth"
+	errorLogHandler "mindsteps/internal/errorlog/handler"
+	errorLogRepo "mindsteps/internal/errorlog/repository"
+	errorLogService "mindsteps/internal/errorlog/service"
+	"mindsteps/internal/rbac"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterErrorLogRoutes(api fiber.Router) {
+	repo := errorLogRepo.NewErrorLogRepository(database.DB)
+	svc := errorLogService.NewErrorLogService(repo)
+	h := errorLogHandler.NewErrorLogHandler(svc)
+
+	canManage := auth.RequirePermission(rbac.PermErrorManage)
+
+	api.Get("/admin/errors", canManage, h.List)
+	api.Get("/admin/errors/groups", canManage, h.Groups)
+	api.Get("/admin/errors/:id", canManage, h.Get)
+	api.Post("/admin/errors/:id/resolve", canManage, auth.DenyImpersonation, h.Resolve)
+	api.Put("/admin/errors/:id/notes", canManage, auth.DenyImpersonation, h.Annotate)
+}
@@ -13,6 +13,7 @@ import (
 	"mindsteps/internal/lesson/handler"
 	"mindsteps/internal/lesson/repository"
 	"mindsteps/internal/lesson/service"
+	"mindsteps/internal/rbac"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -72,9 +73,8 @@ func RegisterLessonRoutes(api fiber.Router) {
 		}), h.GetAll)
 	}
 
-	// Admin routes (authentication required)
-	admin := api.Group("/admin/lessons", auth.TokenMiddleware)
-	// admin.Use(middleware.AuthRequired()) // Uncomment when auth middleware is ready
+	// Admin routes (lesson:write эрх шаардлагатай)
+	admin := api.Group("/admin/lessons", auth.RequirePermission(rbac.PermLessonWrite))
 	{
 		admin.Post("/", h.Create)                         // POST /api/admin/lessons
 		admin.Put("/:id", h.Update)                       // PUT /api/admin/lessons/:id
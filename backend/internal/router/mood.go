@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"mindsteps/database"
 	"mindsteps/internal/auth"
 	gamificationRepo "mindsteps/internal/gamification/repository"
@@ -8,8 +9,12 @@ import (
 	"mindsteps/internal/mood/handler"
 	"mindsteps/internal/mood/repository"
 	"mindsteps/internal/mood/service"
+	"mindsteps/internal/rbac"
+	"mindsteps/internal/scheduler"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
 )
 
 func RegisterMoodRoutes(api fiber.Router) {
@@ -21,19 +26,19 @@ func RegisterMoodRoutes(api fiber.Router) {
 	moodService := service.NewMoodService(moodRepo)
 	moodHandler := handler.NewMoodHandler(moodService)
 
+	// Plutchik Combination repository, service, handler
+	combRepo := repository.NewPlutchikCombinationRepository(database.DB)
+	combService := service.NewPlutchikCombinationService(combRepo)
+	combHandler := handler.NewPlutchikCombinationHandler(combService)
+
 	entryRepo := repository.NewMoodEntryRepository(database.DB)
-	entryService := service.NewMoodEntryService(entryRepo, gamificationService)
+	entryService := service.NewMoodEntryService(entryRepo, combRepo, gamificationService)
 	entryHandler := handler.NewMoodEntryHandler(entryService)
 
 	moodUnitRepo := repository.NewMoodUnitRepository(database.DB)
 	moodUnitService := service.NewMoodUnitService(moodUnitRepo)
 	moodUnitHandler := handler.NewMoodUnitHandler(moodUnitService)
 
-	// Plutchik Combination repository, service, handler
-	combRepo := repository.NewPlutchikCombinationRepository(database.DB)
-	combService := service.NewPlutchikCombinationService(combRepo)
-	combHandler := handler.NewPlutchikCombinationHandler(combService)
-
 	//moods := api.Group("/moods", auth.TokenMiddleware)
 
 	moods := api.Group("/moods/types", auth.TokenMiddleware)
@@ -42,8 +47,15 @@ func RegisterMoodRoutes(api fiber.Router) {
 
 	entries := api.Group("/mood-entries", auth.TokenMiddleware)
 	entries.Get("/me", entryHandler.ListByUserID)
+	entries.Get("/stats", entryHandler.Statistics)
+	entries.Get("/wheel", entryHandler.Wheel)
+	entries.Get("/calendar", entryHandler.Calendar)
+	entries.Get("/calendar/:date", entryHandler.CalendarDay)
+	entries.Get("/tags", entryHandler.Tags)
+	entries.Get("/analysis", entryHandler.Analysis)
 	entries.Post("/", entryHandler.Create)
 	entries.Get("/:id", entryHandler.GetByID)
+	entries.Get("/:id/analysis", entryHandler.EntryAnalysis)
 	entries.Put("/:id", entryHandler.Update)
 	entries.Delete("/:id", entryHandler.Delete)
 
@@ -59,10 +71,28 @@ func RegisterMoodRoutes(api fiber.Router) {
 	combinations.Get("/emotions", combHandler.EmotionList)
 	combinations.Get("/:id", combHandler.GetByID)
 
+	// ML service, тайлангийн job зэрэг системүүд X-Api-Key-ээр уншина
+	system := api.Group("/system", auth.APIKeyMiddleware)
+	system.Get("/users/:userId/mood-entries", auth.RequireScope(rbac.PermMoodRead), entryHandler.SystemListByUserID)
+	system.Get("/users/:userId/mood-stats", auth.RequireScope(rbac.PermStatsRead), entryHandler.SystemStatistics)
+
 	// ==================== ADMIN ONLY ROUTES ====================
 
 	// Admin: Plutchik Combinations - update only
-	adminCombo := api.Group("/admin/plutchik-combinations", auth.TokenMiddleware)
+	adminCombo := api.Group("/admin/plutchik-combinations", auth.RequirePermission(rbac.PermPlutchikWrite))
 	adminCombo.Put("/:id", combHandler.Update)
 
+	// Өчигдрөөс хойш бүртгэл хийсэн хэрэглэгчдийн trigger, coping оноог ai_mood_analysis-д шинэчилнэ
+	scheduler.Register(scheduler.Job{
+		Name:     "mood-tag-analysis",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			count, err := entryService.AnalyzeRecent()
+			if count > 0 {
+				log.Infof("mood tag analysis: %d бүртгэлийн оноо шинэчлэгдсэн", count)
+			}
+			return err
+		},
+	})
+
 }
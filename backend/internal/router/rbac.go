@@ -0,0 +1,33 @@
+package router
+
+import (
+	"mindsteps/database"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/rbac"
+	rbacHandler "mindsteps/internal/rbac/handler"
+	rbacRepo "mindsteps/internal/rbac/repository"
+	rbacService "mindsteps/internal/rbac/service"
+	userRepo "mindsteps/internal/user/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRBACRoutes(api fiber.Router) {
+	roleRepository := rbacRepo.NewRoleRepository(database.DB)
+	userRepository := userRepo.NewUserRepository(database.DB)
+	authRepository := authRepo.NewAuthRepository(database.DB)
+	svc := rbacService.NewRoleService(roleRepository, userRepository, authRepository)
+	h := rbacHandler.NewRoleHandler(svc)
+
+	// Group-д middleware өгвөл /admin prefix-тэй бусад route-д ч хамаарах тул route бүрт тавина
+	canManage := auth.RequirePermission(rbac.PermRoleManage)
+
+	api.Get("/admin/roles", canManage, h.List)
+	api.Post("/admin/roles", canManage, h.Create)
+	api.Put("/admin/roles/:id", canManage, h.Update)
+
+	api.Get("/admin/users/:id/roles", canManage, h.ListUserRoles)
+	api.Post("/admin/users/:id/roles", canManage, h.Assign)
+	api.Delete("/admin/users/:id/roles/:roleId", canManage, h.Revoke)
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"context"
+	"mindsteps/database"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/rbac"
+	retentionHandler "mindsteps/internal/retention/handler"
+	retentionRepo "mindsteps/internal/retention/repository"
+	retentionService "mindsteps/internal/retention/service"
+	"mindsteps/internal/scheduler"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+func RegisterRetentionRoutes(api fiber.Router) {
+	repo := retentionRepo.NewRetentionRepository(database.DB)
+	svc := retentionService.NewRetentionService(repo)
+	h := retentionHandler.NewRetentionHandler(svc)
+
+	canManage := auth.RequirePermission(rbac.PermRetentionManage)
+
+	api.Get("/admin/retention/policies", canManage, h.ListPolicies)
+	api.Get("/admin/retention/report", canManage, h.Report)
+	api.Post("/admin/retention/run", canManage, auth.DenyImpersonation, h.Run)
+
+	// data_retention_policies-ийн хуваарь (next_cleanup_at) болсон policy-уудыг хэрэглэнэ
+	scheduler.Register(scheduler.Job{
+		Name:     "data-retention",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			reports, err := svc.RunDue()
+			for _, report := range reports {
+				if report.Archived+report.Removed+report.ArchivesExpired > 0 {
+					log.Infof("retention %s: %d архивлагдсан, %d %s, %d архив устсан",
+						report.Table, report.Archived, report.Removed, report.Method, report.ArchivesExpired)
+				}
+			}
+			return err
+		},
+	})
+}
@@ -1,7 +1,13 @@
 package router
 
 import (
+	"net/http"
+
+	"mindsteps/internal/auth"
+	"mindsteps/internal/oidc"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 )
 
 // RegisterRoutes бүх API endpoint-уудыг бүртгэж, /api/v1 группийн дор нэгтгэнэ.
@@ -9,18 +15,34 @@ import (
 // кодын зохион байгуулалт болон өргөтгөх боломжийг сайжруулна.
 //
 // Бүртгэгдэж буй маршрутууд:
-//   - AuthRoutes: хэрэглэгчийн нэвтрэлт, бүртгэл, токен
+//   - AuthRoutes: хэрэглэгчийн нэвтрэлт, бүртгэл, токен, Google/Apple (OIDC)
 //   - UserRoutes: хэрэглэгчийн мэдээлэлтэй холбоотой үйлдлүүд
 //   - JournalRoutes: тэмдэглэл, бичлэгийн CRUD
 //   - CoreRoutes: үндсэн core value болон shared logic
 //   - LessonRoutes: сургалтын материал, хичээлтэй холбоотой API
-//   - MoodRoutes: хэрэглэгчийн сэтгэл санааны бүртгэл
+//   - MoodRoutes: хэрэглэгчийн сэтгэл санааны бүртгэл, системүүдийн X-Api-Key-ээр унших /system API
 //   - GoalRoutes: зорилго тодорхойлох, удирдах API
+//   - RBACRoutes: role үүсгэх, хэрэглэгчид оноох/хасах admin API
+//   - APIKeyRoutes: системүүдийн (ML service, тайлан) X-Api-Key удирдах admin API
+//   - RetentionRoutes: data_retention_policies-ийг хэрэглэх, dry-run тайлан харах admin API
+//   - AuditRoutes: system_audit_log-оос шүүж хайх admin API
+//   - ErrorLogRoutes: error_logs-ийг бүлэглэж харах, шийдэгдсэн гэж тэмдэглэх admin API
+//
+// Өөрчлөх (POST, PUT, PATCH, DELETE) хүсэлт бүр auth.AuditMiddleware-аар system_audit_log-д бичигдэнэ.
 //
 // Жич: RegisterCoreRoutes хоёр удаа дуудагдаж байгаа тул давхардал үүсэх магадлалтай,
 // нэгийг нь хасах эсвэл ялгаатай нэртэйгээр зохион байгуулах шаардлагатай.
 func RegisterRoutes(app *fiber.App) {
+	// Token шалгах public key-үүд (ML service, frontend)
+	app.Get("/.well-known/jwks.json", auth.JWKSHandler)
+
+	// Хөгжүүлэлтэд зориулсан хуурамч OIDC issuer (OIDC_LOCAL_STUB=true)
+	if stub := oidc.LocalStub(); stub != nil {
+		app.All(oidc.StubPath+"/*", adaptor.HTTPHandler(http.StripPrefix(oidc.StubPath, stub)))
+	}
+
 	api := app.Group("/api/v1")
+	api.Use(auth.AuditMiddleware)
 
 	RegisterAuthRoutes(api)
 	RegisterUserRoutes(api)
@@ -31,4 +53,9 @@ func RegisterRoutes(app *fiber.App) {
 	RegisterGoalRoutes(api)
 	RegistergamificationRoutes(api)
 	RegisterCacheRoutes(api)
+	RegisterRBACRoutes(api)
+	RegisterAPIKeyRoutes(api)
+	RegisterRetentionRoutes(api)
+	RegisterAuditRoutes(api)
+	RegisterErrorLogRoutes(api)
 }
@@ -1,24 +1,78 @@
 package router
 
 import (
+	"context"
+	"mindsteps/config"
 	"mindsteps/database"
 	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/scheduler"
 	userHandler "mindsteps/internal/user/handler"
 	userRepo "mindsteps/internal/user/repository"
 	userService "mindsteps/internal/user/service"
+	"mindsteps/pkg/cloudflare"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func RegisterUserRoutes(api fiber.Router) {
 	userRepository := userRepo.NewUserRepository(database.DB)
-	userSvc := userService.NewUserService(userRepository)
+	authRepository := authRepo.NewAuthRepository(database.DB)
+	cfg := config.Get().CloudApi
+	media := cloudflare.Bucket{Name: cfg.BucketName, CdnURL: cfg.CdnURL}
+	userSvc := userService.NewUserService(userRepository, authRepository, mailer.Get(), media)
 	h := userHandler.NewUserHandler(userSvc)
 
-	user := api.Group("/users", auth.TokenMiddleware)
+	// Group-д middleware өгвөл route бүр дээр солих боломжгүй тул route тус бүрд тавина.
+	// Бүртгэлээ хамгаалах үйлдлүүдийг имэйлээ баталгаажуулаагүй хэрэглэгчид ч зөвшөөрөх боловч
+	// тусламжийн ажилтан (impersonation) хийж болохгүй.
+	user := api.Group("/users")
+
+	// Устгах хүсэлтийн имэйлд ирсэн холбоосоор бүртгэлээ сэргээх (нэвтрэх боломжгүй тул public)
+	user.Post("/recover", h.RecoverAccount)
+
+	user.Get("/me", auth.TokenMiddleware, h.GetProfile)
+	user.Put("/me", auth.TokenMiddleware, h.UpdateProfile)
+	user.Post("/change-password", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.ChangePassword)
+	user.Delete("/me", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.DeleteAccount)
+
+	// Нэвтэрсэн төхөөрөмжүүд
+	user.Get("/me/sessions", auth.TokenMiddleware, h.ListSessions)
+	user.Delete("/me/sessions", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.RevokeOtherSessions)
+	user.Delete("/me/sessions/:id", auth.UnverifiedMiddleware, auth.DenyImpersonation, h.RevokeSession)
+
+	// Хувийн мэдээллээ татах (ZIP экспорт). Тусламжийн ажилтан хүсэлт үүсгэж болохгүй.
+	user.Get("/me/data-requests", auth.TokenMiddleware, h.ListDataRequests)
+	user.Get("/me/data-requests/:id", auth.TokenMiddleware, h.GetDataRequest)
+	user.Post("/me/data-requests", auth.TokenMiddleware, auth.DenyImpersonation, h.RequestDataExport)
+
+	// Сэргээх хугацаа нь дууссан бүртгэлүүдийг бүрмөсөн устгана
+	scheduler.Register(scheduler.Job{
+		Name:     "account-purge",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			count, err := userSvc.PurgeDueAccounts()
+			if count > 0 {
+				log.Infof("%d бүртгэл бүрмөсөн устгагдлаа", count)
+			}
+			return err
+		},
+	})
 
-	user.Get("/me", h.GetProfile)
-	user.Put("/me", h.UpdateProfile)
-	user.Post("/change-password", h.ChangePassword)
-	user.Delete("/me", h.DeleteAccount)
+	// Хүлээгдэж буй экспортуудыг бэлдэж, хугацаа нь дууссан файлуудыг устгана
+	scheduler.Register(scheduler.Job{
+		Name:     "data-export",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			count, err := userSvc.ProcessDataExports()
+			if count > 0 {
+				log.Infof("%d мэдээллийн экспорт бэлэн боллоо", count)
+			}
+			return err
+		},
+	})
 }
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Job нь тогтмол давтамжтай ажиллах background ажил
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+var (
+	mu   sync.Mutex
+	jobs []Job
+)
+
+// Register нь ажлыг бүртгэнэ. Start дуудагдахаас өмнө (route бүртгэх үед) дуудна.
+func Register(job Job) {
+	mu.Lock()
+	defer mu.Unlock()
+	jobs = append(jobs, job)
+}
+
+// Start нь бүртгэгдсэн ажил бүрийг тусдаа goroutine-д ажиллуулна. ctx цуцлагдахад зогсоно.
+func Start(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	log.Infof("background job эхэллээ: %s (%v тутам)", job.Name, job.Interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			execute(ctx, job)
+		}
+	}
+}
+
+func execute(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("background job %s panic: %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Errorf("background job %s алдаа: %v", job.Name, err)
+		return
+	}
+	log.Debugf("background job %s дууслаа (%v)", job.Name, time.Since(start))
+}
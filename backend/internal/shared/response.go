@@ -2,6 +2,8 @@
 package shared
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 )
@@ -56,6 +58,18 @@ func ResponseNotFound(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"message": "Мэдээлэл олдсонгүй"})
 }
 
+// ResponseTooManyRequests хариу буцаагч - httpStatusCode 429
+// Retry-After header-т дахин оролдох хүртэлх секундийг тавина
+func ResponseTooManyRequests(c *fiber.Ctx, message string, retryAfter int) error {
+	if retryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"message":     message,
+		"retry_after": retryAfter,
+	})
+}
+
 // ResponseErr хариу буцаагч - httpStatusCode 500
 func ResponseErr(c *fiber.Ctx, message string) error {
 	log.Errorf("path: %s | message: %s", c.Path(), message)
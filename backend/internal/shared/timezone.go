@@ -0,0 +1,35 @@
+package shared
+
+import "time"
+
+// StoredLocation нь timestamp without time zone баганууд хадгалагдах бүс.
+// database/postgres.go-ийн session TimeZone, NowFunc үүнийг ашиглана.
+var StoredLocation = time.FixedZone("Asia/Ulaanbaatar", 8*60*60)
+
+// FromStored нь DB-ээс уншсан timestamp-ийн цагийг (UTC байршилтай ирдэг) StoredLocation-ийн
+// цаг гэж үзээд бодит мөч болгоно.
+func FromStored(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), StoredLocation)
+}
+
+// UserLocation нь users.timezone (IANA нэр)-ийг ачаална. Хоосон эсвэл буруу бол UTC.
+func UserLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DateOf нь t-ийн өөрийн бүсээрх огноог (date багана шиг) UTC шөнө дунд болгоно.
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DaysBetween нь from-оос to хүртэлх хуанлийн өдрийн зөрүү (тус бүрийн өөрийн бүсээр).
+func DaysBetween(from, to time.Time) int {
+	return int(DateOf(to).Sub(DateOf(from)).Hours() / 24)
+}
@@ -5,9 +5,7 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
-	"strings"
 	"time"
-	"unicode"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -88,64 +86,6 @@ func IsValidEmail(email string) bool {
 	return ok
 }
 
-func IsValidPassword(password string) (bool, string) {
-	// Check if the password is at least 8 characters long
-	if len(password) < 8 {
-		return false, "Password must be at least 8 characters long"
-	}
-
-	// Check if the password contains at least one lowercase letter
-	hasLower := false
-	for _, char := range password {
-		if unicode.IsLower(char) {
-			hasLower = true
-			break
-		}
-	}
-	if !hasLower {
-		return false, "Password must contain at least one lowercase letter"
-	}
-
-	// Check if the password contains at least one uppercase letter
-	hasUpper := false
-	for _, char := range password {
-		if unicode.IsUpper(char) {
-			hasUpper = true
-			break
-		}
-	}
-	if !hasUpper {
-		return false, "Password must contain at least one uppercase letter"
-	}
-
-	// Check if the password contains at least one digit
-	hasDigit := false
-	for _, char := range password {
-		if unicode.IsDigit(char) {
-			hasDigit = true
-			break
-		}
-	}
-	if !hasDigit {
-		return false, "Password must contain at least one digit"
-	}
-
-	// Check if the password contains at least one special character
-	hasSpecial := false
-	specialChars := "!@#$%^&*()-_=+[]{};:'\"<>,.?/"
-	for _, char := range password {
-		if strings.ContainsRune(specialChars, char) {
-			hasSpecial = true
-			break
-		}
-	}
-	if !hasSpecial {
-		return false, "Password must contain at least one special character"
-	}
-
-	return true, ""
-}
-
 // Checks if val is a phone number
 func isPhoneNo(fl validator.FieldLevel) bool {
 	field := fl.Field()
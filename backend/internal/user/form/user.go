@@ -2,6 +2,7 @@ package form
 
 import (
 	"fmt"
+	"time"
 )
 
 // UpdateProfileForm нь хэрэглэгчийн профайл шинэчлэхэд ашиглагдах формын бүтэц.
@@ -55,3 +56,62 @@ func (f ChangePasswordForm) Validate() error {
 	}
 	return nil
 }
+
+// DeleteAccountForm нь бүртгэл устгахаас өмнө дахин баталгаажуулах формын бүтэц.
+// - Password: Одоогийн нууц үг (хоёр шатат баталгаажуулалт идэвхгүй үед)
+// - Code: Authenticator app-ийн код эсвэл сэргээх код (хоёр шатат баталгаажуулалт идэвхтэй үед)
+type DeleteAccountForm struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+func (f DeleteAccountForm) Validate() error {
+	if f.Password == "" && f.Code == "" {
+		return fmt.Errorf("password эсвэл code хоосон байна")
+	}
+	return nil
+}
+
+// RecoverAccountForm нь бүртгэл устгах хүсэлтийн имэйлд ирсэн сэргээх холбоосын token
+type RecoverAccountForm struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (f RecoverAccountForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("token хоосон байна")
+	}
+	return nil
+}
+
+// DataExportForm нь хувийн мэдээллээ татах хүсэлтийн бүтэц.
+// - Tables: Экспортлох хэсгүүд (хоосон бол бүгд): profile, journals, mood_entries, goals, values, lesson_progress, scoring_history
+// - DateFrom, DateTo: Огнооны хязгаар (YYYY-MM-DD, хоёр тал нь багтана, хоосон байж болно)
+type DataExportForm struct {
+	Tables   []string `json:"tables"`
+	DateFrom string   `json:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string   `json:"date_to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+func (f DataExportForm) Validate() error {
+	_, _, err := f.Range()
+	return err
+}
+
+// Range нь огнооны хязгаарыг задалж буцаана. Өгөөгүй хил тэг утгатай байна.
+func (f DataExportForm) Range() (from, to time.Time, err error) {
+	if f.DateFrom != "" {
+		if from, err = time.Parse(time.DateOnly, f.DateFrom); err != nil {
+			return from, to, fmt.Errorf("date_from YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+	}
+	if f.DateTo != "" {
+		if to, err = time.Parse(time.DateOnly, f.DateTo); err != nil {
+			return from, to, fmt.Errorf("date_to YYYY-MM-DD хэлбэртэй байх ёстой")
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("date_to нь date_from-оос өмнө байж болохгүй")
+	}
+	return from, to, nil
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"mindsteps/database/model"
 	"mindsteps/internal/auth"
 	"mindsteps/internal/shared"
 	"mindsteps/internal/user/form"
+	"mindsteps/internal/user/repository"
 	"mindsteps/internal/user/service"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -80,7 +84,7 @@ func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
-	if err := h.service.ChangePassword(tokenInfo.UserID, &f); err != nil {
+	if err := h.service.ChangePassword(tokenInfo.UserID, tokenInfo.SessionID, &f); err != nil {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
@@ -96,12 +100,210 @@ func (h *UserHandler) DeleteAccount(c *fiber.Ctx) error {
 		return shared.ResponseUnauthorized(c)
 	}
 
-	if err := h.service.DeleteAccount(tokenInfo.UserID); err != nil {
+	var f form.DeleteAccountForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.DeleteAccount(tokenInfo.UserID, &f); err != nil {
 		return shared.ResponseBadRequest(c, err.Error())
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Таны бүртгэл амжилттай устгагдлаа",
+		"message": "Таны бүртгэл идэвхгүй боллоо. 30 хоногийн дотор имэйлээр ирсэн холбоосоор сэргээх боломжтой, дараа нь бүрмөсөн устгагдана",
 	})
 }
+
+func (h *UserHandler) RecoverAccount(c *fiber.Ctx) error {
+	var f form.RecoverAccountForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	if err := h.service.RecoverAccount(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Таны бүртгэл сэргээгдлээ. Дахин нэвтэрнэ үү",
+	})
+}
+
+func (h *UserHandler) ListSessions(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	sessions, err := h.service.ListSessions(tokenInfo.UserID)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	result := make([]fiber.Map, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, fiber.Map{
+			"id":            session.ID,
+			"device_info":   session.DeviceInfo,
+			"ip_address":    session.IPAddress,
+			"user_agent":    session.UserAgent,
+			"last_activity": session.LastActivity,
+			"created_at":    session.CreatedAt,
+			"expires_at":    session.ExpiresAt,
+			"is_current":    session.ID == tokenInfo.SessionID,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"sessions": result,
+	})
+}
+
+func (h *UserHandler) RevokeSession(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "session id буруу байна")
+	}
+
+	if err := h.service.RevokeSession(tokenInfo.UserID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return shared.ResponseNotFound(c)
+		}
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Төхөөрөмжөөс амжилттай гаргалаа",
+	})
+}
+
+func (h *UserHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	count, err := h.service.RevokeOtherSessions(tokenInfo.UserID, tokenInfo.SessionID)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success":       true,
+		"message":       "Бусад төхөөрөмжөөс амжилттай гаргалаа",
+		"revoked_count": count,
+	})
+}
+
+func (h *UserHandler) RequestDataExport(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	var f form.DataExportForm
+	if err := c.BodyParser(&f); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+	if err := f.Validate(); err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	req, err := h.service.RequestDataExport(tokenInfo.UserID, &f)
+	if err != nil {
+		return shared.ResponseBadRequest(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Хүсэлтийг хүлээн авлаа. Файл бэлэн болмогц мэдэгдэл, имэйл илгээнэ",
+		"request": dataRequestResponse(req),
+	})
+}
+
+func (h *UserHandler) ListDataRequests(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	requests, err := h.service.ListDataRequests(tokenInfo.UserID)
+	if err != nil {
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	result := make([]fiber.Map, 0, len(requests))
+	for i := range requests {
+		result = append(result, dataRequestResponse(&requests[i]))
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"requests": result,
+	})
+}
+
+func (h *UserHandler) GetDataRequest(c *fiber.Ctx) error {
+	tokenInfo := auth.GetTokenInfo(c)
+	if tokenInfo == nil {
+		return shared.ResponseUnauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return shared.ResponseBadRequest(c, "хүсэлтийн id буруу байна")
+	}
+
+	req, err := h.service.GetDataRequest(tokenInfo.UserID, uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrDataRequestNotFound) {
+			return shared.ResponseNotFound(c)
+		}
+		return shared.ResponseErr(c, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"request": dataRequestResponse(req),
+	})
+}
+
+// dataRequestResponse: татах холбоосыг зөвхөн бэлэн, хугацаа нь дуусаагүй үед буцаана.
+func dataRequestResponse(req *model.UserDataRequests) fiber.Map {
+	result := fiber.Map{
+		"id":               req.ID,
+		"status":           req.Status,
+		"tables":           service.RequestedTables(req),
+		"export_format":    req.ExportFormat,
+		"date_range_start": nil,
+		"date_range_end":   nil,
+		"requested_at":     req.RequestedAt,
+		"processed_at":     nil,
+		"download_url":     nil,
+		"expires_at":       nil,
+		"error_message":    req.ErrorMessage,
+	}
+	if !req.DateRangeStart.IsZero() {
+		result["date_range_start"] = req.DateRangeStart.Format(time.DateOnly)
+	}
+	if !req.DateRangeEnd.IsZero() {
+		result["date_range_end"] = req.DateRangeEnd.Format(time.DateOnly)
+	}
+	if !req.ProcessedAt.IsZero() {
+		result["processed_at"] = req.ProcessedAt
+	}
+	if req.Status == repository.DataRequestCompleted && time.Now().Before(req.ExportExpiresAt) {
+		result["download_url"] = req.ExportFileURL
+		result["expires_at"] = req.ExportExpiresAt
+	}
+	return result
+}
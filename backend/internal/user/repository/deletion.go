@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mindsteps/database/model"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// deleted_data_log.deletion_method-д бичигдэх утгууд
+const (
+	// DeletionDeactivate нь сэргээх хугацаа дуусахыг хүлээж буй (users.is_active = false) бүртгэл
+	DeletionDeactivate = "deactivate"
+	DeletionRecovered  = "recovered"
+	DeletionPurged     = "purge"
+	DeletionDelete     = "delete"
+	DeletionAnonymize  = "anonymize"
+)
+
+// purgeStep нь нэг хүснэгтээс хэрэглэгчийн мөрийг устгах (set == nil) эсвэл нууцлах алхам.
+// where нь "?"-д user_id авна. Гадаад түлхүүрийн дарааллаар (хүүхэд хүснэгт эхэлж) эрэмбэлэгдсэн.
+type purgeStep struct {
+	table string
+	where string
+	set   map[string]interface{}
+}
+
+func userScope(column string) string {
+	return column + " = ?"
+}
+
+var purgeSteps = []purgeStep{
+	// Тэмдэглэл, сэтгэл санааны бичлэгээс хамаарах шинжилгээ
+	{table: model.TableNameAIJournalDetailedAnalysis, where: userScope("user_id")},
+	{table: model.TableNameAIMoodAnalysis, where: userScope("user_id")},
+	{table: model.TableNameUserEmotionWheel, where: userScope("user_id")},
+	{table: model.TableNameLessonRecommendations, where: userScope("user_id")},
+	{table: model.TableNameAIProgressTracking, where: userScope("user_id")},
+	{table: model.TableNameAIWeeklyMoodDeepAnalysis, where: userScope("user_id")},
+	{table: model.TableNameDetectedPatterns, where: userScope("user_id")},
+	{table: model.TableNameUserInsights, where: userScope("user_id")},
+	// Зорилго, үнэт зүйлс, тэмдэглэл
+	{table: model.TableNameGoalMilestones, where: "goal_id IN (SELECT id FROM " + model.TableNameGoals + " WHERE user_id = ?)"},
+	{table: model.TableNameGoals, where: userScope("user_id")},
+	{table: model.TableNameValueReflections, where: userScope("user_id")},
+	{table: model.TableNameMoodEntryTags, where: "tag_id IN (SELECT id FROM " + model.TableNameMoodTags + " WHERE user_id = ?)"},
+	{table: model.TableNameMoodTags, where: userScope("user_id")},
+	{table: model.TableNameMoodEntries, where: userScope("user_id")},
+	{table: model.TableNameJournals, where: userScope("user_id")},
+	{table: model.TableNameCoreValues, where: userScope("user_id")},
+	// Явц, оноо, тохиргоо
+	{table: model.TableNameMeditationSessions, where: userScope("user_id")},
+	{table: model.TableNameScoringHistory, where: userScope("user_id")},
+	{table: model.TableNameUserConsciousnessTracking, where: userScope("user_id")},
+	{table: model.TableNameUserAchievements, where: userScope("user_id")},
+	{table: model.TableNameUserStreaks, where: userScope("user_id")},
+	{table: model.TableNameUserGamification, where: userScope("user_id")},
+	{table: model.TableNameUserLessonProgress, where: userScope("user_id")},
+	{table: model.TableNameLessonReactions, where: userScope("user_id")},
+	{table: model.TableNameProgressReports, where: userScope("user_id")},
+	{table: model.TableNameNotifications, where: userScope("user_id")},
+	{table: model.TableNameUserPreferences, where: userScope("user_id")},
+	// Бусдын хариулт parent_id-аар холбогдсон байж болох тул сэтгэгдлийг устгахгүй, агуулгыг нь арилгана
+	{table: model.TableNameLessonComments, where: userScope("user_id"), set: map[string]interface{}{"content": "", "is_deleted": true}},
+	// Нэвтрэлт, баталгаажуулалт
+	{table: model.TableNameAuthOTP, where: userScope("user_id")},
+	{table: model.TableNamePasswordHistory, where: userScope("user_id")},
+	{table: model.TableNameUserEmailChanges, where: userScope("user_id")},
+	{table: model.TableNameUserIdentities, where: userScope("user_id")},
+	{table: model.TableNameUserRecoveryCodes, where: userScope("user_id")},
+	{table: model.TableNameUserMfa, where: userScope("user_id")},
+	{table: model.TableNameUserDataRequests, where: userScope("user_id")},
+	{table: model.TableNameRoleOwners, where: userScope("owner_id")},
+	{table: model.TableNameRevokedTokens, where: userScope("user_id")},
+	{table: model.TableNameUserDataAccessLog, where: "user_id = ? OR session_id IN (SELECT id FROM " + model.TableNameUserSessions + " WHERE user_id = ?)"},
+	{table: model.TableNameUserSessions, where: userScope("user_id")},
+	// Алдааны лог системд хэрэгтэй тул хэрэглэгчтэй холбоосыг л таслана
+	{table: model.TableNameErrorLogs, where: userScope("user_id"), set: map[string]interface{}{"user_id": gorm.Expr("NULL")}},
+	// Audit лог үйлдлийн түүхийг хадгална. Хэрэглэгчийн тухай (ажилтны хийсэн) бичлэгийн агуулгыг,
+	// хэрэглэгчийн өөрийн бичлэгийн холбоос, агуулга, төхөөрөмжийн мэдээллийг арилгана.
+	{table: model.TableNameSystemAuditLog, where: "entity_type = 'users' AND entity_id = ?", set: map[string]interface{}{
+		"old_value": gorm.Expr("NULL"),
+		"new_value": gorm.Expr("NULL"),
+	}},
+	{table: model.TableNameSystemAuditLog, where: userScope("user_id"), set: map[string]interface{}{
+		"user_id":    gorm.Expr("NULL"),
+		"old_value":  gorm.Expr("NULL"),
+		"new_value":  gorm.Expr("NULL"),
+		"ip_address": gorm.Expr("NULL"),
+		"user_agent": "",
+	}},
+}
+
+// ScheduleDeletion нь бүртгэлийг идэвхгүй болгож, сэргээх хугацааг deleted_data_log-д бичнэ.
+func (r *userRepo) ScheduleDeletion(entry *model.DeletedDataLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Users{}).
+			Where("id = ? AND deleted_at IS NULL", entry.UserID).
+			Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Omit("User", "DeletedBy").Create(entry).Error
+	})
+}
+
+// FindPendingDeletion нь сэргээх боломжтой хүлээгдэж буй устгалтыг буцаана.
+// deleted_data_log.deleted_at нь soft delete биш, устгасан огноо тул Unscoped ашиглана.
+func (r *userRepo) FindPendingDeletion(userID uint) (*model.DeletedDataLog, error) {
+	var entry model.DeletedDataLog
+	err := r.db.Unscoped().
+		Where("user_id = ? AND table_name = ? AND deletion_method = ? AND can_recover = ?", userID, model.TableNameUsers, DeletionDeactivate, true).
+		Order("id DESC").
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RecoverDeletion нь хугацаа дуусаагүй бол устгалтыг цуцалж бүртгэлийг идэвхжүүлнэ.
+func (r *userRepo) RecoverDeletion(entry *model.DeletedDataLog) (bool, error) {
+	recovered := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&model.DeletedDataLog{}).
+			Where("id = ? AND deletion_method = ? AND can_recover = ? AND recovery_expires_at > ?", entry.ID, DeletionDeactivate, true, time.Now()).
+			Updates(map[string]interface{}{"can_recover": false, "deletion_method": DeletionRecovered})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Model(&model.Users{}).Where("id = ?", entry.UserID).
+			Updates(map[string]interface{}{"is_active": true, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		recovered = true
+		return nil
+	})
+	return recovered, err
+}
+
+// DueDeletions нь сэргээх хугацаа нь дууссан устгалтуудыг буцаана.
+func (r *userRepo) DueDeletions(now time.Time, limit int) ([]model.DeletedDataLog, error) {
+	var entries []model.DeletedDataLog
+	err := r.db.Unscoped().
+		Where("table_name = ? AND deletion_method = ? AND can_recover = ? AND recovery_expires_at <= ?", model.TableNameUsers, DeletionDeactivate, true, now).
+		Order("recovery_expires_at").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// MediaURLs нь R2-оос устгах шаардлагатай хэрэглэгчийн файлуудын URL-уудыг буцаана.
+func (r *userRepo) MediaURLs(userID uint) ([]string, error) {
+	var urls []string
+	err := r.db.Raw(
+		"SELECT COALESCE(profile_picture, '') FROM "+model.TableNameUsers+" WHERE id = ? "+
+			"UNION SELECT COALESCE(pdf_url, '') FROM "+model.TableNameProgressReports+" WHERE user_id = ? "+
+			"UNION SELECT COALESCE(export_file_url, '') FROM "+model.TableNameUserDataRequests+" WHERE user_id = ?",
+		userID, userID, userID,
+	).Scan(&urls).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := urls[:0]
+	for _, url := range urls {
+		if url != "" {
+			result = append(result, url)
+		}
+	}
+	return result, nil
+}
+
+// Purge нь хэрэглэгчийн мэдээллийг бүх хүснэгтээс нэг transaction-д устгаж эсвэл нууцална.
+// Хүснэгт бүрийн тоог deleted_data_log-д бичиж, users мөрийг нэргүй болгоно (лог, audit-ийн
+// гадаад түлхүүр хадгалагдана). Энэ хооронд сэргээгдсэн бол юу ч хийхгүй, false буцаана.
+func (r *userRepo) Purge(entry *model.DeletedDataLog) (bool, error) {
+	purged := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&model.DeletedDataLog{}).
+			Where("id = ? AND deletion_method = ? AND can_recover = ?", entry.ID, DeletionDeactivate, true).
+			Updates(map[string]interface{}{"can_recover": false, "deletion_method": DeletionPurged})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		now := time.Now()
+		for _, step := range purgeSteps {
+			args := make([]interface{}, countPlaceholders(step.where))
+			for i := range args {
+				args[i] = entry.UserID
+			}
+
+			var res *gorm.DB
+			method := DeletionDelete
+			if step.set == nil {
+				res = tx.Exec("DELETE FROM "+step.table+" WHERE "+step.where, args...)
+			} else {
+				method = DeletionAnonymize
+				res = tx.Table(step.table).Where(step.where, args...).Updates(step.set)
+			}
+			if res.Error != nil {
+				return fmt.Errorf("%s: %w", step.table, res.Error)
+			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+
+			if err := createPurgeLog(tx, entry, step.table, method, res.RowsAffected, now); err != nil {
+				return err
+			}
+		}
+
+		anonymized := map[string]interface{}{
+			"name":              "Устгагдсан хэрэглэгч",
+			"email":             fmt.Sprintf("deleted-%d@deleted.invalid", entry.UserID),
+			"password":          "",
+			"profile_picture":   "",
+			"is_active":         false,
+			"is_email_verified": false,
+			"updated_at":        now,
+			"deleted_at":        now,
+		}
+		if err := tx.Model(&model.Users{}).Where("id = ?", entry.UserID).Updates(anonymized).Error; err != nil {
+			return err
+		}
+		if err := createPurgeLog(tx, entry, model.TableNameUsers, DeletionAnonymize, 1, now); err != nil {
+			return err
+		}
+
+		purged = true
+		return nil
+	})
+	return purged, err
+}
+
+func createPurgeLog(tx *gorm.DB, entry *model.DeletedDataLog, table, method string, rows int64, now time.Time) error {
+	data, err := json.Marshal(map[string]interface{}{"rows": rows, "deletion_log_id": entry.ID})
+	if err != nil {
+		return err
+	}
+	return tx.Omit("User", "DeletedBy", "RecoveryExpiresAt").Create(&model.DeletedDataLog{
+		UserID:         entry.UserID,
+		TableName_:     table,
+		RecordData:     datatypes.JSON(data),
+		DeletedReason:  entry.DeletedReason,
+		DeletionMethod: method,
+		DeletedAt:      gorm.DeletedAt{Time: now, Valid: true},
+		DeletedByID:    entry.DeletedByID,
+	}).Error
+}
+
+func countPlaceholders(where string) int {
+	count := 0
+	for _, r := range where {
+		if r == '?' {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"time"
+
+	"mindsteps/database/model"
+)
+
+// user_data_requests.status-д бичигдэх утгууд
+const (
+	DataRequestPending    = "pending"
+	DataRequestProcessing = "processing"
+	DataRequestCompleted  = "completed"
+	DataRequestFailed     = "failed"
+	// DataRequestExpired нь татах холбоосын хугацаа дуусч файл нь устгагдсан хүсэлт
+	DataRequestExpired = "expired"
+
+	DataRequestTypeExport = "export"
+)
+
+// ExportSection нь экспортын ZIP доторх нэг файлын (JSON, CSV) эх хүснэгт.
+// Key нь хэрэглэгчийн сонгох нэр бөгөөд нэг Key хэд хэдэн файл үүсгэж болно.
+// where нь "?"-д user_id авна. dateColumn хоосон бол огнооны хязгаар хэрэглэхгүй.
+type ExportSection struct {
+	Key        string
+	File       string
+	table      string
+	columns    string
+	where      string
+	dateColumn string
+}
+
+// ExportSections нь экспортод орох хүснэгтүүд. Нууц үг, шифрлэлтийн түлхүүр зэрэг
+// дотоод баганыг гаргахгүйн тулд баганыг тус бүрд нь зааж өгнө.
+var ExportSections = []ExportSection{
+	{
+		Key: "profile", File: "profile", table: model.TableNameUsers, where: "id = ?",
+		columns: "id, uuid, name, email, total_score, current_level, level_progress, profile_picture, timezone, language, is_email_verified, email_verified_at, last_login, login_count, created_at, updated_at",
+	},
+	{
+		Key: "journals", File: "journals", table: model.TableNameJournals, where: "user_id = ? AND deleted_at IS NULL", dateColumn: "created_at",
+		columns: "id, title, content, word_count, sentiment_score, is_private, tags, created_at, updated_at",
+	},
+	{
+		Key: "mood_entries", File: "mood_entries", table: model.TableNameMoodEntries, where: "user_id = ?", dateColumn: "entry_date",
+		columns: "id, mood_unit_id, entry_date, intensity, when_felt, trigger_event, coping_strategy, notes, location, weather, core_value_id, created_at, updated_at",
+	},
+	{
+		Key: "goals", File: "goals", table: model.TableNameGoals, where: "user_id = ? AND deleted_at IS NULL", dateColumn: "created_at",
+		columns: "id, value_id, title, description, goal_type, target_date, status, progress_percentage, is_public, priority, created_at, updated_at, completed_at",
+	},
+	{
+		Key: "goals", File: "goal_milestones", table: model.TableNameGoalMilestones, dateColumn: "created_at",
+		where:   "goal_id IN (SELECT id FROM " + model.TableNameGoals + " WHERE user_id = ? AND deleted_at IS NULL)",
+		columns: "id, goal_id, title, description, target_date, is_completed, completed_at, sort_order, created_at",
+	},
+	{
+		Key: "values", File: "core_values", table: model.TableNameCoreValues, where: "user_id = ?", dateColumn: "created_at",
+		columns: "id, maslow_level_id, name, description, priority_order, color, icon, is_active, created_at, updated_at",
+	},
+	{
+		Key: "values", File: "value_reflections", table: model.TableNameValueReflections, where: "user_id = ?", dateColumn: "reflection_date",
+		columns: "id, value_id, source_type, source_id, reflection_date, alignment_score, notes, created_at",
+	},
+	{
+		Key: "lesson_progress", File: "lesson_progress", table: model.TableNameUserLessonProgress, where: "user_id = ?", dateColumn: "created_at",
+		columns: "id, lesson_id, progress_percentage, status, time_spent, last_accessed, completion_date, rating, review_text, is_bookmarked, created_at, updated_at",
+	},
+	{
+		Key: "scoring_history", File: "scoring_history", table: model.TableNameScoringHistory, where: "user_id = ?", dateColumn: "created_at",
+		columns: "id, source_type, source_id, points_earned, points_type, multiplier, description, metadata, created_at",
+	},
+}
+
+// ExportTable нь нэг хэсгийн мөрүүд, баганын дарааллаар.
+type ExportTable struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+func (r *userRepo) CreateDataRequest(req *model.UserDataRequests) error {
+	omit := []string{"User", "ProcessedBy", "ProcessedByID", "ProcessingStartedAt", "ProcessedAt", "ExportExpiresAt"}
+	if req.DateRangeStart.IsZero() {
+		omit = append(omit, "DateRangeStart")
+	}
+	if req.DateRangeEnd.IsZero() {
+		omit = append(omit, "DateRangeEnd")
+	}
+	return r.db.Omit(omit...).Create(req).Error
+}
+
+func (r *userRepo) ListDataRequests(userID uint) ([]model.UserDataRequests, error) {
+	var requests []model.UserDataRequests
+	err := r.db.Where("user_id = ?", userID).Order("requested_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+func (r *userRepo) FindDataRequest(userID, id uint) (*model.UserDataRequests, error) {
+	var req model.UserDataRequests
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// HasOpenDataRequest нь хүлээгдэж эсвэл боловсруулагдаж буй хүсэлт байгаа эсэхийг шалгана.
+func (r *userRepo) HasOpenDataRequest(userID uint, requestType string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.UserDataRequests{}).
+		Where("user_id = ? AND request_type = ? AND status IN ?", userID, requestType, []string{DataRequestPending, DataRequestProcessing}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ClaimDataRequests нь хүлээгдэж буй хүсэлтүүдийг processing төлөвт оруулж буцаана.
+// staleBefore-оос өмнө эхэлсэн processing хүсэлт (worker унасан) дахин авагдана.
+// Олон instance зэрэг ажиллахад нэг хүсэлтийг нэг л worker авна.
+func (r *userRepo) ClaimDataRequests(requestType string, staleBefore time.Time, limit int) ([]model.UserDataRequests, error) {
+	const claimable = "request_type = ? AND (status = ? OR (status = ? AND processing_started_at < ?))"
+
+	var candidates []model.UserDataRequests
+	err := r.db.Where(claimable, requestType, DataRequestPending, DataRequestProcessing, staleBefore).
+		Order("requested_at").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]model.UserDataRequests, 0, len(candidates))
+	for _, req := range candidates {
+		now := time.Now()
+		result := r.db.Model(&model.UserDataRequests{}).
+			Where("id = ? AND "+claimable, req.ID, requestType, DataRequestPending, DataRequestProcessing, staleBefore).
+			Updates(map[string]interface{}{"status": DataRequestProcessing, "processing_started_at": now})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 1 {
+			req.Status = DataRequestProcessing
+			req.ProcessingStartedAt = now
+			claimed = append(claimed, req)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *userRepo) CompleteDataRequest(req *model.UserDataRequests) error {
+	return r.db.Model(&model.UserDataRequests{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+		"status":            DataRequestCompleted,
+		"processed_at":      req.ProcessedAt,
+		"export_file_url":   req.ExportFileURL,
+		"export_expires_at": req.ExportExpiresAt,
+		"error_message":     "",
+	}).Error
+}
+
+func (r *userRepo) FailDataRequest(id uint, message string) error {
+	return r.db.Model(&model.UserDataRequests{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        DataRequestFailed,
+		"processed_at":  time.Now(),
+		"error_message": message,
+	}).Error
+}
+
+// ExpiredDataRequests нь татах холбоосын хугацаа нь дууссан экспортуудыг буцаана.
+func (r *userRepo) ExpiredDataRequests(now time.Time, limit int) ([]model.UserDataRequests, error) {
+	var requests []model.UserDataRequests
+	err := r.db.Where("status = ? AND export_expires_at <= ?", DataRequestCompleted, now).
+		Order("export_expires_at").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *userRepo) ExpireDataRequest(id uint) error {
+	return r.db.Model(&model.UserDataRequests{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": DataRequestExpired, "export_file_url": ""}).Error
+}
+
+// ExportRows нь тухайн хэсгийн хэрэглэгчид хамаарах мөрүүдийг from-to (огноо, хоёр тал нь багтана)
+// хязгаарт буцаана. Тэг утгатай хил хэрэглэгдэхгүй.
+func (r *userRepo) ExportRows(section ExportSection, userID uint, from, to time.Time) (*ExportTable, error) {
+	query := r.db.Table(section.table).Select(section.columns).Where(section.where, userID)
+	if section.dateColumn != "" {
+		if !from.IsZero() {
+			query = query.Where(section.dateColumn+" >= ?", from)
+		}
+		if !to.IsZero() {
+			query = query.Where(section.dateColumn+" < ?", to.AddDate(0, 0, 1))
+		}
+	}
+
+	rows, err := query.Order("id").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ExportTable{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table, rows.Err()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"mindsteps/database/model"
+	"mindsteps/internal/password"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,10 +13,26 @@ type UserRepository interface {
 	FindByID(id uint) (*model.Users, error)
 	FindByEmail(email string) (*model.Users, error)
 	Update(user *model.Users) error
-	Delete(id uint) error
 	UpdateLastLogin(id uint) error
 	UpdatePassword(id uint, hashedPassword string) error
+	PasswordHistory(id uint, limit int) ([]string, error)
 	IncrementLoginCount(id uint) error
+	ScheduleDeletion(entry *model.DeletedDataLog) error
+	FindPendingDeletion(userID uint) (*model.DeletedDataLog, error)
+	RecoverDeletion(entry *model.DeletedDataLog) (bool, error)
+	DueDeletions(now time.Time, limit int) ([]model.DeletedDataLog, error)
+	MediaURLs(userID uint) ([]string, error)
+	Purge(entry *model.DeletedDataLog) (bool, error)
+	CreateDataRequest(req *model.UserDataRequests) error
+	ListDataRequests(userID uint) ([]model.UserDataRequests, error)
+	FindDataRequest(userID, id uint) (*model.UserDataRequests, error)
+	HasOpenDataRequest(userID uint, requestType string) (bool, error)
+	ClaimDataRequests(requestType string, staleBefore time.Time, limit int) ([]model.UserDataRequests, error)
+	CompleteDataRequest(req *model.UserDataRequests) error
+	FailDataRequest(id uint, message string) error
+	ExpiredDataRequests(now time.Time, limit int) ([]model.UserDataRequests, error)
+	ExpireDataRequest(id uint) error
+	ExportRows(section ExportSection, userID uint, from, to time.Time) (*ExportTable, error)
 }
 
 type userRepo struct {
@@ -50,18 +67,51 @@ func (r *userRepo) Update(user *model.Users) error {
 	return r.db.Save(user).Error
 }
 
-func (r *userRepo) Delete(id uint) error {
-	now := time.Now()
-	return r.db.Model(&model.Users{}).Where("id = ?", id).Update("deleted_at", now).Error
-}
-
 func (r *userRepo) UpdateLastLogin(id uint) error {
 	now := time.Now()
 	return r.db.Model(&model.Users{}).Where("id = ?", id).Update("last_login", now).Error
 }
 
+// UpdatePassword нь нууц үгийг солихдоо хуучин hash-ийг password_history-д хадгалж,
+// хамгийн сүүлийн password.MaxHistorySize-аас бусдыг устгана.
 func (r *userRepo) UpdatePassword(id uint, hashedPassword string) error {
-	return r.db.Model(&model.Users{}).Where("id = ?", id).Update("password", hashedPassword).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous string
+		if err := tx.Model(&model.Users{}).Where("id = ?", id).Pluck("password", &previous).Error; err != nil {
+			return err
+		}
+		if previous != "" {
+			if err := tx.Create(&model.PasswordHistory{
+				UserID:       id,
+				PasswordHash: previous,
+				CreatedAt:    time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.Users{}).Where("id = ?", id).Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		keep := tx.Model(&model.PasswordHistory{}).Select("id").
+			Where("user_id = ?", id).Order("id DESC").Limit(password.MaxHistorySize)
+		return tx.Where("user_id = ? AND id NOT IN (?)", id, keep).Delete(&model.PasswordHistory{}).Error
+	})
+}
+
+// PasswordHistory нь өмнөх нууц үгийн hash-уудыг шинээс нь эхлэн limit хүртэл буцаана.
+func (r *userRepo) PasswordHistory(id uint, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	err := r.db.Model(&model.PasswordHistory{}).
+		Where("user_id = ?", id).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	return hashes, err
 }
 
 func (r *userRepo) IncrementLoginCount(id uint) error {
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/shared"
+	userForm "mindsteps/internal/user/form"
+	"mindsteps/internal/user/repository"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// deletionGraceDays нь бүртгэл устгах хүсэлтээс хойш сэргээх боломжтой хоног
+	deletionGraceDays = 30
+	// purgeBatchSize нь нэг удаагийн ажиллагаанд бүрмөсөн устгах бүртгэлийн дээд тоо
+	purgeBatchSize = 50
+	// userMediaPrefix нь хэрэглэгчийн R2 файлуудын угтвар: users/<id>/
+	userMediaPrefix = "users/"
+)
+
+var (
+	ErrDeletionPending      = errors.New("бүртгэл устгах хүсэлт аль хэдийн хүлээгдэж байна")
+	ErrSecondFactorRequired = errors.New("хоёр шатат баталгаажуулалтын кодоо оруулна уу")
+	ErrInvalidPassword      = errors.New("password буруу байна")
+)
+
+// MediaStore нь хэрэглэгчийн R2 дээрх файлуудыг хадгалж, устгана (cloudflare.Bucket).
+type MediaStore interface {
+	Put(objectName, contentType string, file io.Reader, objectSize int64) error
+	PresignedURL(objectName string, expiry time.Duration) (string, error)
+	RemoveURL(fileURL string) error
+	RemovePrefix(prefix string) error
+}
+
+// DeleteAccount нь бүртгэлийг шууд устгахгүй, идэвхгүй болгож бүх төхөөрөмжөөс гаргана.
+// Хулгайлагдсан access token-оор устгуулахгүйн тулд нууц үг эсвэл MFA кодоор дахин баталгаажуулна.
+// deletionGraceDays хоногийн дотор имэйлийн холбоосоор сэргээж болно, дараа нь PurgeDueAccounts устгана.
+func (s *userService) DeleteAccount(userID uint, f *userForm.DeleteAccountForm) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(user, f); err != nil {
+		return err
+	}
+	if _, err := s.repo.FindPendingDeletion(userID); err == nil {
+		return ErrDeletionPending
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	entry := &model.DeletedDataLog{
+		UserID:            user.ID,
+		TableName_:        model.TableNameUsers,
+		RecordID:          user.ID,
+		DeletedReason:     "user_request",
+		DeletionMethod:    repository.DeletionDeactivate,
+		DeletedAt:         gorm.DeletedAt{Time: now, Valid: true},
+		DeletedByID:       user.ID,
+		CanRecover:        true,
+		RecoveryExpiresAt: now.AddDate(0, 0, deletionGraceDays),
+	}
+	if err := s.repo.ScheduleDeletion(entry); err != nil {
+		return err
+	}
+
+	sessionIDs, err := s.authRepo.RevokeUserSessions(user.ID, "account_deleted")
+	if err != nil {
+		return err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	token, err := auth.CreatePurposeToken(&auth.Token{
+		UserID: user.ID,
+		Nonce:  shared.UintToString(entry.ID),
+	}, auth.PurposeAccountRecovery, time.Duration(deletionGraceDays*24*60))
+	if err != nil {
+		return err
+	}
+	s.sendDeletionMail(user, mailer.TemplateAccountDeletion, entry, mailer.Link("/account/recover", url.Values{"token": {token}}))
+
+	return nil
+}
+
+// reauthenticate нь MFA идэвхтэй бол app эсвэл сэргээх кодыг, үгүй бол одоогийн нууц үгийг шалгана.
+func (s *userService) reauthenticate(user *model.Users, f *userForm.DeleteAccountForm) error {
+	mfa, err := s.authRepo.FindMFA(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if mfa != nil && !mfa.EnabledAt.IsZero() {
+		if f.Code == "" || auth.SecondFactor == nil {
+			return ErrSecondFactorRequired
+		}
+		return auth.SecondFactor.VerifySecondFactor(user.ID, f.Code)
+	}
+
+	if f.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(f.Password)) != nil {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// RecoverAccount нь устгах хүсэлтийн имэйлд ирсэн холбоосоор бүртгэлийг сэргээнэ.
+// Дараа нь хэрэглэгч ердийнхөөр нэвтэрнэ.
+func (s *userService) RecoverAccount(f *userForm.RecoverAccountForm) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	claims, err := auth.ReadPurposeToken(f.Token, auth.PurposeAccountRecovery)
+	if err != nil {
+		return err
+	}
+
+	entry, err := s.repo.FindPendingDeletion(claims.UserID)
+	if err != nil || shared.UintToString(entry.ID) != claims.Nonce {
+		return auth.ErrInvalidLinkToken
+	}
+
+	recovered, err := s.repo.RecoverDeletion(entry)
+	if err != nil {
+		return err
+	}
+	if !recovered {
+		return auth.ErrInvalidLinkToken
+	}
+
+	if user, err := s.repo.FindByID(entry.UserID); err == nil {
+		s.sendDeletionMail(user, mailer.TemplateAccountRecovered, entry, "")
+	}
+	return nil
+}
+
+// PurgeDueAccounts нь сэргээх хугацаа нь дууссан бүртгэлүүдийн мэдээллийг бүрмөсөн устгаж
+// (эсвэл нууцалж), R2 дээрх файлуудыг арилгана. Устгасан бүртгэлийн тоог буцаана.
+func (s *userService) PurgeDueAccounts() (int, error) {
+	entries, err := s.repo.DueDeletions(time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := range entries {
+		entry := &entries[i]
+		purged, err := s.purge(entry)
+		if err != nil {
+			log.Errorf("бүртгэл устгахад алдаа (user %d): %v", entry.UserID, err)
+			continue
+		}
+		if purged {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *userService) purge(entry *model.DeletedDataLog) (bool, error) {
+	// Имэйл хаяг нууцлагдахаас өмнө авна
+	user, err := s.repo.FindByID(entry.UserID)
+	if err != nil {
+		return false, err
+	}
+	urls, err := s.repo.MediaURLs(entry.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	sessionIDs, err := s.authRepo.RevokeUserSessions(entry.UserID, "account_deleted")
+	if err != nil {
+		return false, err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	purged, err := s.repo.Purge(entry)
+	if err != nil {
+		return false, err
+	}
+	if !purged {
+		// Энэ хооронд сэргээгдсэн
+		return false, nil
+	}
+
+	// Файлын алдаа өгөгдлийн устгалтыг буцаахгүй, логт үлдэнэ
+	if s.media != nil {
+		for _, fileURL := range urls {
+			if err := s.media.RemoveURL(fileURL); err != nil {
+				log.Errorf("R2 файл устгахад алдаа (user %d): %v", entry.UserID, err)
+			}
+		}
+		if err := s.media.RemovePrefix(userMediaPrefix + shared.UintToString(entry.UserID) + "/"); err != nil {
+			log.Errorf("R2 файл устгахад алдаа (user %d): %v", entry.UserID, err)
+		}
+	}
+
+	s.sendDeletionMail(user, mailer.TemplateAccountDeleted, entry, "")
+	log.Infof("бүртгэл бүрмөсөн устгагдлаа (user %d)", entry.UserID)
+	return true, nil
+}
+
+// sendDeletionMail: имэйлийн алдаа устгах, сэргээх үйлдлийг зогсоохгүй.
+func (s *userService) sendDeletionMail(user *model.Users, tpl mailer.Template, entry *model.DeletedDataLog, recoverURL string) {
+	if s.mailer == nil {
+		return
+	}
+
+	purgeAt := entry.RecoveryExpiresAt
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		purgeAt = purgeAt.In(loc)
+	}
+
+	msg, err := mailer.Render(tpl, user.Language, user.Email, mailer.AccountDeletionData{
+		Name:       user.Name,
+		RecoverURL: recoverURL,
+		PurgeAt:    purgeAt.Format("2006-01-02 15:04 MST"),
+		GraceDays:  deletionGraceDays,
+	})
+	if err == nil {
+		err = s.mailer.Send(msg)
+	}
+	if err != nil {
+		log.Errorf("бүртгэл устгах имэйл илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/shared"
+	userForm "mindsteps/internal/user/form"
+	"mindsteps/internal/user/repository"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// exportLinkDays нь татах холбоосын хугацаа. Presigned URL 7 хоногоос урт байж болохгүй.
+	exportLinkDays = 7
+	// exportBatchSize нь нэг удаагийн ажиллагаанд боловсруулах хүсэлтийн дээд тоо
+	exportBatchSize = 5
+	// exportStaleAfter нь processing төлөвт гацсан хүсэлтийг дахин авах хугацаа
+	exportStaleAfter = time.Hour
+	exportFormat     = "zip"
+)
+
+var (
+	ErrDataRequestPending  = errors.New("мэдээлэл татах хүсэлт аль хэдийн боловсруулагдаж байна")
+	ErrDataRequestNotFound = errors.New("хүсэлт олдсонгүй")
+	ErrExportUnavailable   = errors.New("файлын сан тохируулагдаагүй байна")
+)
+
+// ExportKeys нь сонгож болох экспортын хэсгүүдийг дарааллаар нь буцаана.
+func ExportKeys() []string {
+	keys := make([]string, 0, len(repository.ExportSections))
+	for _, section := range repository.ExportSections {
+		if !slices.Contains(keys, section.Key) {
+			keys = append(keys, section.Key)
+		}
+	}
+	return keys
+}
+
+// RequestedTables нь user_data_requests.requested_tables (Postgres массив)-ийг задална.
+func RequestedTables(req *model.UserDataRequests) []string {
+	var tables pq.StringArray
+	if err := tables.Scan(req.RequestedTables); err != nil {
+		return nil
+	}
+	return tables
+}
+
+// RequestDataExport нь хувийн мэдээлэл татах хүсэлтийг бүртгэнэ. Файлыг ProcessDataExports
+// ар талд бэлдэж, бэлэн болмогц хэрэглэгчид мэдэгдэнэ.
+func (s *userService) RequestDataExport(userID uint, f *userForm.DataExportForm) (*model.UserDataRequests, error) {
+	from, to, err := f.Range()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := ExportKeys()
+	tables := f.Tables
+	if len(tables) == 0 {
+		tables = keys
+	}
+	selected := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !slices.Contains(keys, table) {
+			return nil, fmt.Errorf("tables буруу байна: %s (боломжит: %v)", table, keys)
+		}
+		if !slices.Contains(selected, table) {
+			selected = append(selected, table)
+		}
+	}
+
+	open, err := s.repo.HasOpenDataRequest(userID, repository.DataRequestTypeExport)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrDataRequestPending
+	}
+
+	requested, err := pq.StringArray(selected).Value()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	req := &model.UserDataRequests{
+		UserID:          userID,
+		RequestType:     repository.DataRequestTypeExport,
+		Status:          repository.DataRequestPending,
+		RequestedTables: requested.(string),
+		DateRangeStart:  from,
+		DateRangeEnd:    to,
+		RequestedAt:     now,
+		ExportFormat:    exportFormat,
+		CreatedAt:       now,
+	}
+	if err := s.repo.CreateDataRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *userService) ListDataRequests(userID uint) ([]model.UserDataRequests, error) {
+	return s.repo.ListDataRequests(userID)
+}
+
+func (s *userService) GetDataRequest(userID, id uint) (*model.UserDataRequests, error) {
+	req, err := s.repo.FindDataRequest(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDataRequestNotFound
+	}
+	return req, err
+}
+
+// ProcessDataExports нь хүлээгдэж буй экспортуудын ZIP файлыг бэлдэж R2-д байршуулаад,
+// хугацаа нь дууссан экспортын файлуудыг устгана. Бэлэн болсон экспортын тоог буцаана.
+func (s *userService) ProcessDataExports() (int, error) {
+	s.expireDataExports()
+
+	requests, err := s.repo.ClaimDataRequests(repository.DataRequestTypeExport, time.Now().Add(-exportStaleAfter), exportBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := range requests {
+		req := &requests[i]
+		if err := s.export(req); err != nil {
+			log.Errorf("мэдээллийн экспорт бэлдэхэд алдаа (request %d): %v", req.ID, err)
+			if err := s.repo.FailDataRequest(req.ID, err.Error()); err != nil {
+				log.Errorf("экспортын төлөв шинэчлэхэд алдаа (request %d): %v", req.ID, err)
+			}
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (s *userService) export(req *model.UserDataRequests) error {
+	if s.media == nil {
+		return ErrExportUnavailable
+	}
+	user, err := s.repo.FindByID(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	archive, err := s.buildExportArchive(req)
+	if err != nil {
+		return err
+	}
+
+	objectName := exportObjectName(req)
+	if err := s.media.Put(objectName, "application/zip", bytes.NewReader(archive), int64(len(archive))); err != nil {
+		return err
+	}
+	downloadURL, err := s.media.PresignedURL(objectName, exportLinkDays*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	req.ProcessedAt = time.Now()
+	req.ExportFileURL = downloadURL
+	req.ExportExpiresAt = req.ProcessedAt.AddDate(0, 0, exportLinkDays)
+	if err := s.repo.CompleteDataRequest(req); err != nil {
+		return err
+	}
+
+	s.notifyDataExport(user, req)
+	return nil
+}
+
+// exportObjectName нь users/<id>/ угтвар дор байрлана, тиймээс бүртгэл устгахад хамт устна.
+func exportObjectName(req *model.UserDataRequests) string {
+	return fmt.Sprintf("%s%d/exports/%d.zip", userMediaPrefix, req.UserID, req.ID)
+}
+
+// buildExportArchive нь сонгосон хэсэг бүрийг <file>.json болон <file>.csv болгож ZIP-д хийнэ.
+func (s *userService) buildExportArchive(req *model.UserDataRequests) ([]byte, error) {
+	tables := RequestedTables(req)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range repository.ExportSections {
+		if !slices.Contains(tables, section.Key) {
+			continue
+		}
+
+		table, err := s.repo.ExportRows(section, req.UserID, req.DateRangeStart, req.DateRangeEnd)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeExportJSON(archive, section.File+".json", table); err != nil {
+			return nil, err
+		}
+		if err := writeExportCSV(archive, section.File+".csv", table); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeExportJSON(archive *zip.Writer, name string, table *repository.ExportTable) error {
+	rows := make([]map[string]interface{}, 0, len(table.Rows))
+	for _, values := range table.Rows {
+		row := make(map[string]interface{}, len(table.Columns))
+		for i, column := range table.Columns {
+			row[column] = exportValue(values[i])
+		}
+		rows = append(rows, row)
+	}
+
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func writeExportCSV(archive *zip.Writer, name string, table *repository.ExportTable) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Columns); err != nil {
+		return err
+	}
+	for _, values := range table.Rows {
+		record := make([]string, len(values))
+		for i, value := range values {
+			if v := exportValue(value); v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportValue нь DB-ийн утгыг JSON, CSV-д ойлгомжтой хэлбэрт оруулна.
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return value
+}
+
+// expireDataExports нь татах хугацаа нь дууссан экспортын файлыг R2-аас устгана.
+func (s *userService) expireDataExports() {
+	if s.media == nil {
+		return
+	}
+
+	requests, err := s.repo.ExpiredDataRequests(time.Now(), purgeBatchSize)
+	if err != nil {
+		log.Errorf("хугацаа дууссан экспорт авахад алдаа: %v", err)
+		return
+	}
+	for _, req := range requests {
+		if err := s.media.RemoveURL(req.ExportFileURL); err != nil {
+			log.Errorf("экспортын файл устгахад алдаа (request %d): %v", req.ID, err)
+			continue
+		}
+		if err := s.repo.ExpireDataRequest(req.ID); err != nil {
+			log.Errorf("экспортын төлөв шинэчлэхэд алдаа (request %d): %v", req.ID, err)
+		}
+	}
+}
+
+// notifyDataExport: мэдэгдэл, имэйлийн алдаа экспортыг буцаахгүй, хэрэглэгч жагсаалтаас татаж болно.
+func (s *userService) notifyDataExport(user *model.Users, req *model.UserDataRequests) {
+	expiresAt := req.ExportExpiresAt
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		expiresAt = expiresAt.In(loc)
+	}
+	expires := expiresAt.Format("2006-01-02 15:04 MST")
+
+	metadata, _ := json.Marshal(map[string]interface{}{"data_request_id": req.ID})
+	if err := s.authRepo.CreateNotification(&model.Notifications{
+		UserID:           user.ID,
+		NotificationType: "data_export_ready",
+		Title:            "Таны мэдээллийн экспорт бэлэн боллоо",
+		Message:          "Файлыг " + expires + " хүртэл татаж авах боломжтой",
+		ActionURL:        "/settings/data-requests/" + shared.UintToString(req.ID),
+		ActionLabel:      "Татах",
+		SentAt:           req.ProcessedAt,
+		Metadata:         datatypes.JSON(metadata),
+		Priority:         "normal",
+		CreatedAt:        req.ProcessedAt,
+	}); err != nil {
+		log.Errorf("экспортын мэдэгдэл үүсгэхэд алдаа (user %d): %v", user.ID, err)
+	}
+
+	if s.mailer == nil {
+		return
+	}
+	msg, err := mailer.Render(mailer.TemplateDataExportReady, user.Language, user.Email, mailer.DataExportData{
+		Name:        user.Name,
+		DownloadURL: req.ExportFileURL,
+		ExpiresAt:   expires,
+	})
+	if err == nil {
+		err = s.mailer.Send(msg)
+	}
+	if err != nil {
+		log.Errorf("экспортын имэйл илгээхэд алдаа (user %d): %v", user.ID, err)
+	}
+}
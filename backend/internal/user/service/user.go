@@ -1,8 +1,13 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/password"
 	userForm "mindsteps/internal/user/form"
 	"mindsteps/internal/user/repository"
 	"time"
@@ -13,16 +18,30 @@ import (
 type UserService interface {
 	GetProfile(userID uint) (*model.Users, error)
 	UpdateProfile(userID uint, form *userForm.UpdateProfileForm) (*model.Users, error)
-	ChangePassword(userID uint, form *userForm.ChangePasswordForm) error
-	DeleteAccount(userID uint) error
+	ChangePassword(userID, sessionID uint, form *userForm.ChangePasswordForm) error
+	DeleteAccount(userID uint, form *userForm.DeleteAccountForm) error
+	RecoverAccount(form *userForm.RecoverAccountForm) error
+	PurgeDueAccounts() (int, error)
+	RequestDataExport(userID uint, form *userForm.DataExportForm) (*model.UserDataRequests, error)
+	ListDataRequests(userID uint) ([]model.UserDataRequests, error)
+	GetDataRequest(userID, id uint) (*model.UserDataRequests, error)
+	ProcessDataExports() (int, error)
+	ListSessions(userID uint) ([]model.UserSessions, error)
+	RevokeSession(userID, sessionID uint) error
+	RevokeOtherSessions(userID, currentSessionID uint) (int, error)
 }
 
+var ErrSessionNotFound = errors.New("session олдсонгүй")
+
 type userService struct {
-	repo repository.UserRepository
+	repo     repository.UserRepository
+	authRepo authRepo.AuthRepository
+	mailer   mailer.Mailer
+	media    MediaStore
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, authRepo authRepo.AuthRepository, mailer mailer.Mailer, media MediaStore) UserService {
+	return &userService{repo: repo, authRepo: authRepo, mailer: mailer, media: media}
 }
 
 func (s *userService) GetProfile(userID uint) (*model.Users, error) {
@@ -61,7 +80,8 @@ func (s *userService) UpdateProfile(userID uint, f *userForm.UpdateProfileForm)
 	return user, nil
 }
 
-func (s *userService) ChangePassword(userID uint, f *userForm.ChangePasswordForm) error {
+// ChangePassword нь нууц үгийг солиод одоогийн session-оос бусад бүх төхөөрөмжийг гаргана.
+func (s *userService) ChangePassword(userID, sessionID uint, f *userForm.ChangePasswordForm) error {
 	if err := f.Validate(); err != nil {
 		return err
 	}
@@ -76,15 +96,55 @@ func (s *userService) ChangePassword(userID uint, f *userForm.ChangePasswordForm
 		return fmt.Errorf("current_password буруу байна")
 	}
 
-	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(f.NewPassword), bcrypt.DefaultCost)
+	// Бодлого болон сүүлийн нууц үгнүүдтэй тулгаж hash үүсгэнэ
+	policy := password.Get()
+	history, err := s.repo.PasswordHistory(userID, policy.HistorySize)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := policy.Hash(f.NewPassword, user.Email, append([]string{user.Password}, history...)...)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+
+	sessionIDs, err := s.authRepo.RevokeOtherSessions(userID, sessionID, "password_change")
 	if err != nil {
 		return err
 	}
+	auth.InvalidateSessions(sessionIDs...)
 
-	return s.repo.UpdatePassword(userID, string(hashedPassword))
+	return nil
 }
 
-func (s *userService) DeleteAccount(userID uint) error {
-	return s.repo.Delete(userID)
+func (s *userService) ListSessions(userID uint) ([]model.UserSessions, error) {
+	return s.authRepo.ListActiveSessions(userID)
+}
+
+func (s *userService) RevokeSession(userID, sessionID uint) error {
+	session, err := s.authRepo.FindSessionByID(sessionID)
+	if err != nil || session.UserID != userID || !session.IsActive {
+		return ErrSessionNotFound
+	}
+
+	if err := s.authRepo.RevokeSession(session.ID, "user_revoked"); err != nil {
+		return err
+	}
+	auth.InvalidateSessions(session.ID)
+
+	return nil
+}
+
+// RevokeOtherSessions нь одоогийн session-оос бусад бүх төхөөрөмжийг гаргаж, хаасан тоог буцаана.
+func (s *userService) RevokeOtherSessions(userID, currentSessionID uint) (int, error) {
+	sessionIDs, err := s.authRepo.RevokeOtherSessions(userID, currentSessionID, "user_revoked")
+	if err != nil {
+		return 0, err
+	}
+	auth.InvalidateSessions(sessionIDs...)
+
+	return len(sessionIDs), nil
 }
@@ -116,3 +116,67 @@ func GetPresignedURL(ctx context.Context, bucketName string, objectName string,
 
 	return presignedURL.String(), nil
 }
+
+// Bucket resolves public object URLs (direct R2 or CDN) back to object names.
+type Bucket struct {
+	Name   string
+	CdnURL string
+}
+
+// ObjectName returns the object name for a URL that points into the bucket.
+// Query strings (e.g. presigned URL signatures) are ignored.
+// URLs hosted elsewhere are reported as not belonging to the bucket.
+func (b Bucket) ObjectName(fileURL string) (string, bool) {
+	if fileURL == "" || b.Name == "" {
+		return "", false
+	}
+	fileURL, _, _ = strings.Cut(fileURL, "?")
+	if b.CdnURL != "" {
+		if name, ok := strings.CutPrefix(fileURL, strings.TrimRight(b.CdnURL, "/")+"/"); ok && name != "" {
+			return name, true
+		}
+	}
+	if R2Client != nil {
+		endpoint := strings.TrimRight(R2Client.EndpointURL().String(), "/")
+		if name, ok := strings.CutPrefix(fileURL, endpoint+"/"+b.Name+"/"); ok && name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Put uploads an object into the bucket.
+func (b Bucket) Put(objectName, contentType string, file io.Reader, objectSize int64) error {
+	_, err := PutObject(b.Name, objectName, contentType, file, objectSize)
+	return err
+}
+
+// PresignedURL returns a download URL for objectName that expires after expiry.
+func (b Bucket) PresignedURL(objectName string, expiry time.Duration) (string, error) {
+	return GetPresignedURL(context.Background(), b.Name, objectName, expiry)
+}
+
+// RemoveURL deletes the object behind fileURL. URLs outside the bucket are ignored.
+func (b Bucket) RemoveURL(fileURL string) error {
+	name, ok := b.ObjectName(fileURL)
+	if !ok {
+		return nil
+	}
+	return DeleteObject(b.Name, name)
+}
+
+// RemovePrefix deletes every object whose name starts with prefix.
+func (b Bucket) RemovePrefix(prefix string) error {
+	if R2Client == nil {
+		return fmt.Errorf("R2 client is not loaded")
+	}
+
+	ctx := context.Background()
+	objects := R2Client.ListObjects(ctx, b.Name, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for result := range R2Client.RemoveObjects(ctx, b.Name, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to delete object %s: %w", result.ObjectName, result.Err)
+		}
+	}
+	return nil
+}
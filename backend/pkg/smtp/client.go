@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/smtp"
+	"strings"
 	"time"
 
 	"mindsteps/config"
@@ -21,6 +22,8 @@ type EmailConfig struct {
 	SMTPPort     int
 	SMTPUser     string
 	SMTPPassword string
+	From         string
+	AuthMethod   string // AuthPlain эсвэл AuthXOAuth2
 	ClientID     string
 	TenantID     string
 	ClientSecret string
@@ -33,9 +36,13 @@ type OAuthTokenResponse struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
-// SMTPClient represents the SMTP client with OAuth support
+const (
+	AuthPlain   = "plain"
+	AuthXOAuth2 = "xoauth2"
+)
+
+// SMTPClient represents the SMTP client with PLAIN or OAuth support
 type SMTPClient struct {
-	client      *smtp.Client
 	config      *EmailConfig
 	accessToken string
 	tokenExpiry time.Time
@@ -80,13 +87,39 @@ func (a *OAuthAuthenticator) Next(fromServer []byte, more bool) ([]byte, error)
 	return nil, nil
 }
 
-// newSMTPClient creates a new SMTP client
-func newSMTPClient(cfg *EmailConfig) *SMTPClient {
+// NewSMTPClient creates a new SMTP client
+func NewSMTPClient(cfg *EmailConfig) *SMTPClient {
+	if cfg.From == "" {
+		cfg.From = cfg.SMTPUser
+	}
+	if cfg.AuthMethod == "" {
+		cfg.AuthMethod = AuthPlain
+	}
 	return &SMTPClient{
 		config: cfg,
 	}
 }
 
+// authenticator returns smtp.Auth for the configured AuthMethod
+func (c *SMTPClient) authenticator() (smtp.Auth, error) {
+	switch strings.ToLower(c.config.AuthMethod) {
+	case AuthPlain:
+		return smtp.PlainAuth("", c.config.SMTPUser, c.config.SMTPPassword, c.config.SMTPServer), nil
+	case AuthXOAuth2:
+		if c.accessToken == "" || time.Now().After(c.tokenExpiry) {
+			if err := c.obtainAccessToken(); err != nil {
+				return nil, fmt.Errorf("token retrieval failed: %v", err)
+			}
+		}
+		return &OAuthAuthenticator{
+			email:       c.config.SMTPUser,
+			accessToken: c.accessToken,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported SMTP auth method: %s", c.config.AuthMethod)
+	}
+}
+
 // getOAuthConfig creates OAuth2 configuration for Microsoft Exchange
 func (c *SMTPClient) getOAuthConfig() *oauth2.Config {
 	return &oauth2.Config{
@@ -115,12 +148,12 @@ func (c *SMTPClient) obtainAccessToken() error {
 	return nil
 }
 
-// connectSMTP establishes a new SMTP connection
-func (c *SMTPClient) connectSMTP() error {
-	if c.accessToken == "" || time.Now().After(c.tokenExpiry) {
-		if err := c.obtainAccessToken(); err != nil {
-			return fmt.Errorf("token retrieval failed: %v", err)
-		}
+// connectSMTP establishes a new authenticated SMTP connection.
+// The caller owns the returned client and must Quit it.
+func (c *SMTPClient) connectSMTP() (*smtp.Client, error) {
+	auth, err := c.authenticator()
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -132,17 +165,13 @@ func (c *SMTPClient) connectSMTP() error {
 
 	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", c.config.SMTPServer, c.config.SMTPPort))
 	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+		return nil, fmt.Errorf("failed to connect to SMTP server: %v", err)
 	}
-	defer func() {
-		if conn != nil {
-			conn.Close()
-		}
-	}()
 
 	client, err := smtp.NewClient(conn, c.config.SMTPServer)
 	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %v", err)
+		conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %v", err)
 	}
 
 	tlsConfig := &tls.Config{
@@ -152,68 +181,60 @@ func (c *SMTPClient) connectSMTP() error {
 	}
 
 	if err := client.StartTLS(tlsConfig); err != nil {
-		return fmt.Errorf("failed to start TLS: %v", err)
-	}
-
-	auth := &OAuthAuthenticator{
-		email:       c.config.SMTPUser,
-		accessToken: c.accessToken,
+		client.Close()
+		return nil, fmt.Errorf("failed to start TLS: %v", err)
 	}
 
 	if err := client.Auth(auth); err != nil {
-		return fmt.Errorf("SMTP server authentication failed: %v", err)
+		client.Close()
+		return nil, fmt.Errorf("SMTP server authentication failed: %v", err)
 	}
 
-	c.client = client
-	return nil
+	return client, nil
 }
 
-// SendEmail sends an email using the SMTP client
+// SendEmail sends a plain text email using the SMTP client
 func (c *SMTPClient) SendEmail(to, subject, body string) (string, error) {
-	// Ensure we have an active SMTP connection
-	if c.client == nil {
-		if err := c.connectSMTP(); err != nil {
-			return "", err
-		}
-	}
-
-	// Generate unique message ID
 	messageID := uuid.New().String()
+	msg := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMessage-ID: <%s@mindsteps>\r\n\r\n%s",
+		c.config.From, to, subject, messageID, body))
 
-	// Prepare message
-	from := c.config.SMTPUser
-	msg := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMessage-ID: <%s@binary.systems.mn>\r\n\r\n%s",
-		from, to, subject, messageID, body))
+	return messageID, c.SendRaw(to, msg)
+}
+
+// SendRaw sends an already composed RFC 5322 message
+func (c *SMTPClient) SendRaw(to string, msg []byte) error {
+	client, err := c.connectSMTP()
+	if err != nil {
+		return err
+	}
+	defer client.Quit()
 
 	// Set sender and recipient
-	if err := c.client.Mail(from); err != nil {
-		return "", fmt.Errorf("failed to set 'From' address: %v", err)
+	if err := client.Mail(c.config.From); err != nil {
+		return fmt.Errorf("failed to set 'From' address: %v", err)
 	}
-	if err := c.client.Rcpt(to); err != nil {
-		return "", fmt.Errorf("failed to set 'To' address: %v", err)
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("failed to set 'To' address: %v", err)
 	}
 
 	// Send message data
-	w, err := c.client.Data()
+	w, err := client.Data()
 	if err != nil {
-		return "", fmt.Errorf("failed to get data writer: %v", err)
+		return fmt.Errorf("failed to get data writer: %v", err)
 	}
-	defer w.Close()
 
-	_, err = w.Write(msg)
-	if err != nil {
-		return "", fmt.Errorf("failed to write message: %v", err)
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write message: %v", err)
 	}
 
-	return messageID, nil
+	return w.Close()
 }
 
-// CloseClient closes the SMTP client connection
-func (c *SMTPClient) CloseClient() error {
-	if c.client != nil {
-		return c.client.Quit()
-	}
-	return nil
+// From returns the configured sender address
+func (c *SMTPClient) From() string {
+	return c.config.From
 }
 
 // Global SMTP client
@@ -225,18 +246,29 @@ func Load() error {
 	cfg := config.Get().Smtp
 
 	// Create new SMTP client
-	smtpClient = newSMTPClient(&EmailConfig{
+	smtpClient = NewSMTPClient(&EmailConfig{
 		SMTPServer:   cfg.SMTPServer,
 		SMTPPort:     cfg.SMTPPort, // Standard TLS port
 		SMTPUser:     cfg.SMTPUser,
 		SMTPPassword: cfg.SMTPPassword,
+		From:         cfg.From,
+		AuthMethod:   cfg.AuthMethod,
 		ClientID:     cfg.ClientID,
 		TenantID:     cfg.TenantID,
 		ClientSecret: cfg.ClientSecret,
 	})
 
 	// Attempt initial connection
-	return smtpClient.connectSMTP()
+	client, err := smtpClient.connectSMTP()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// GetClient returns the global SMTP client
+func GetClient() *SMTPClient {
+	return smtpClient
 }
 
 // SendEmail wrapper function
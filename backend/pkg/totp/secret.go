@@ -0,0 +1,67 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// KeySize нь SealSecret, OpenSecret-ийн түлхүүрийн урт (AES-256)
+const KeySize = 32
+
+// sealedPrefix нь шифрлэлтийн хувилбар, дараа түлхүүр солиход ялгахад хэрэглэнэ
+const sealedPrefix = "v1:"
+
+// SealSecret нь нууцыг AES-256-GCM-ээр нууц бүрт санамсаргүй nonce ашиглан шифрлэнэ.
+// Үр дүн нь "v1:" + base64(nonce || ciphertext).
+func SealSecret(key []byte, secret string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret нь SealSecret-ийн үр дүнг тайлна. Түлхүүр буруу эсвэл өгөгдөл өөрчлөгдсөн бол алдаа.
+func OpenSecret(key []byte, sealed string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	encoded, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return "", fmt.Errorf("totp: шифрлэлтийн хувилбар танигдсангүй")
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("totp: шифрлэсэн нууц хэт богино")
+	}
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("totp: нууцыг тайлж чадсангүй: %w", err)
+	}
+	return string(plain), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("totp: түлхүүр %d байт байх ёстой", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
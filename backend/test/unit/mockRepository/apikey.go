@@ -0,0 +1,53 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) List() ([]model.APIKeys, error) {
+	args := m.Called()
+	return args.Get(0).([]model.APIKeys), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByID(id uint) (*model.APIKeys, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKeys), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(prefix string) (*model.APIKeys, error) {
+	args := m.Called(prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKeys), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Create(key *model.APIKeys) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Rotate(old *model.APIKeys, key *model.APIKeys, graceUntil time.Time) error {
+	args := m.Called(old, key, graceUntil)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id uint, ipAddress string) error {
+	args := m.Called(id, ipAddress)
+	return args.Error(0)
+}
@@ -0,0 +1,222 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuthRepository) CreateOTP(otp *model.AuthOTP) error {
+	args := m.Called(otp)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindValidOTP(email, otpCode, otpType string) (*model.AuthOTP, error) {
+	args := m.Called(email, otpCode, otpType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuthOTP), args.Error(1)
+}
+
+func (m *MockAuthRepository) MarkOTPAsUsed(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) InvalidateOTPs(userID uint, otpType string) error {
+	args := m.Called(userID, otpType)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) CreateSession(session *model.UserSessions) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindSessionByID(id uint) (*model.UserSessions, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserSessions), args.Error(1)
+}
+
+func (m *MockAuthRepository) RotateSession(id uint, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	args := m.Called(id, oldHash, newHash, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) RevokeSession(id uint, reason string) error {
+	args := m.Called(id, reason)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) RevokeUserSessions(userID uint, reason string) ([]uint, error) {
+	args := m.Called(userID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockAuthRepository) RevokeOtherSessions(userID, keepID uint, reason string) ([]uint, error) {
+	args := m.Called(userID, keepID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListActiveSessions(userID uint) ([]model.UserSessions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UserSessions), args.Error(1)
+}
+
+func (m *MockAuthRepository) TouchSession(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) CreateRevokedToken(revoked *model.RevokedTokens) error {
+	args := m.Called(revoked)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) IsTokenRevoked(tokenHash string) (bool, error) {
+	args := m.Called(tokenHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) KnownLoginOrigin(userID uint, deviceInfo, ipAddress string) (bool, bool, error) {
+	args := m.Called(userID, deviceInfo, ipAddress)
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAuthRepository) CreateAccessLog(entry *model.UserDataAccessLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) CleanupExpiredOTPs() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindIdentity(provider, subject string) (*model.UserIdentities, error) {
+	args := m.Called(provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserIdentities), args.Error(1)
+}
+
+func (m *MockAuthRepository) CreateIdentity(identity *model.UserIdentities) error {
+	args := m.Called(identity)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) TouchIdentity(id uint, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindMFA(userID uint) (*model.UserMfa, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserMfa), args.Error(1)
+}
+
+func (m *MockAuthRepository) SaveMFA(mfa *model.UserMfa) error {
+	args := m.Called(mfa)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) EnableMFA(userID uint, step int64, recoveryHashes []string) error {
+	args := m.Called(userID, step, recoveryHashes)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) DeleteMFA(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) UseMFAStep(userID uint, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	args := m.Called(userID, hashes)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) UseRecoveryCode(userID uint, hash string) (bool, error) {
+	args := m.Called(userID, hash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) ConsumeOTP(userID uint, otpCode, otpType string) (bool, error) {
+	args := m.Called(userID, otpCode, otpType)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) CreateEmailChange(change *model.UserEmailChanges) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindPendingEmailChange(userID uint) (*model.UserEmailChanges, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserEmailChanges), args.Error(1)
+}
+
+func (m *MockAuthRepository) FindEmailChange(id uint) (*model.UserEmailChanges, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserEmailChanges), args.Error(1)
+}
+
+func (m *MockAuthRepository) ConfirmEmailChange(change *model.UserEmailChanges) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) CancelEmailChange(change *model.UserEmailChanges) (bool, error) {
+	args := m.Called(change)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) CreateAuditLog(entry *model.SystemAuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) AuditSnapshot(table string, id uint) (map[string]interface{}, error) {
+	args := m.Called(table, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockAuthRepository) CreateNotification(notification *model.Notifications) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
@@ -0,0 +1,47 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"mindsteps/internal/errorlog/form"
+	"mindsteps/internal/errorlog/repository"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockErrorLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockErrorLogRepository) Record(entry *model.ErrorLogs) (uint, error) {
+	args := m.Called(entry)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockErrorLogRepository) List(filter *form.ErrorLogFilter) ([]model.ErrorLogs, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.ErrorLogs), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockErrorLogRepository) Groups(filter *form.ErrorLogFilter) ([]repository.ErrorGroup, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]repository.ErrorGroup), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockErrorLogRepository) FindByID(id uint) (*model.ErrorLogs, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ErrorLogs), args.Error(1)
+}
+
+func (m *MockErrorLogRepository) Resolve(id, resolvedByID uint, notes string, at time.Time) error {
+	args := m.Called(id, resolvedByID, notes, at)
+	return args.Error(0)
+}
+
+func (m *MockErrorLogRepository) Annotate(id uint, notes string) error {
+	args := m.Called(id, notes)
+	return args.Error(0)
+}
@@ -0,0 +1,59 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGamificationService struct {
+	mock.Mock
+}
+
+func (m *MockGamificationService) GetUserGamification(userID uint) (*model.UserGamification, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserGamification), args.Error(1)
+}
+
+func (m *MockGamificationService) AddXP(userID uint, points int, sourceType string, sourceID uint, metadata string) error {
+	args := m.Called(userID, points, sourceType, sourceID, metadata)
+	return args.Error(0)
+}
+
+type MockGamificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockGamificationRepository) GetByUserID(userID uint) (*model.UserGamification, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserGamification), args.Error(1)
+}
+
+func (m *MockGamificationRepository) UpdateProgress(stats *model.UserGamification) error {
+	args := m.Called(stats)
+	return args.Error(0)
+}
+
+func (m *MockGamificationRepository) CreateScoreHistory(history *model.ScoringHistory) error {
+	args := m.Called(history)
+	return args.Error(0)
+}
+
+func (m *MockGamificationRepository) GetLevelByScore(score int) (*model.UserLevels, error) {
+	args := m.Called(score)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserLevels), args.Error(1)
+}
+
+func (m *MockGamificationRepository) UserTimezone(userID uint) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,45 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockKeyRepository) ListKeys(keyName string) ([]model.EncryptionKeys, error) {
+	args := m.Called(keyName)
+	return args.Get(0).([]model.EncryptionKeys), args.Error(1)
+}
+
+func (m *MockKeyRepository) FindByVersion(keyName string, version int) (*model.EncryptionKeys, error) {
+	args := m.Called(keyName, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EncryptionKeys), args.Error(1)
+}
+
+func (m *MockKeyRepository) LatestVersion(keyName string) (int, error) {
+	args := m.Called(keyName)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockKeyRepository) Create(key *model.EncryptionKeys) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockKeyRepository) Activate(keyName string, version int, retireAt time.Time) error {
+	args := m.Called(keyName, version, retireAt)
+	return args.Error(0)
+}
+
+func (m *MockKeyRepository) Retire(keyName string, version int, at time.Time) error {
+	args := m.Called(keyName, version, at)
+	return args.Error(0)
+}
@@ -0,0 +1,16 @@
+package mockRepository
+
+import (
+	"mindsteps/internal/mailer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(msg *mailer.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
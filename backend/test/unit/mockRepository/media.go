@@ -0,0 +1,32 @@
+package mockRepository
+
+import (
+	"io"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMediaStore struct {
+	mock.Mock
+}
+
+func (m *MockMediaStore) RemoveURL(fileURL string) error {
+	args := m.Called(fileURL)
+	return args.Error(0)
+}
+
+func (m *MockMediaStore) RemovePrefix(prefix string) error {
+	args := m.Called(prefix)
+	return args.Error(0)
+}
+
+func (m *MockMediaStore) Put(objectName, contentType string, file io.Reader, objectSize int64) error {
+	args := m.Called(objectName, contentType, file, objectSize)
+	return args.Error(0)
+}
+
+func (m *MockMediaStore) PresignedURL(objectName string, expiry time.Duration) (string, error) {
+	args := m.Called(objectName, expiry)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,262 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"mindsteps/internal/mood/repository"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMoodEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockMoodEntryRepository) Create(entry *model.MoodEntries) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) GetByID(id uint) (*model.MoodEntries, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MoodEntries), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) Update(entry *model.MoodEntries) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) ListByUserID(userID uint, limit int, offset int) ([]model.MoodEntries, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]model.MoodEntries), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) CountByUserID(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) FindByDateRange(userID uint, fromDate, toDate time.Time) ([]model.MoodEntries, error) {
+	args := m.Called(userID, fromDate, toDate)
+	return args.Get(0).([]model.MoodEntries), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) ListByMoodID() ([]model.MoodCategories, error) {
+	args := m.Called()
+	return args.Get(0).([]model.MoodCategories), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) UserTimezone(userID uint) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) StatsSummary(q repository.StatsQuery) (repository.StatsSummary, error) {
+	args := m.Called(q)
+	return args.Get(0).(repository.StatsSummary), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) StatsTrend(q repository.StatsQuery, bucket string) ([]repository.StatsBucket, error) {
+	args := m.Called(q, bucket)
+	return args.Get(0).([]repository.StatsBucket), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) StatsEmotions(q repository.StatsQuery) ([]repository.StatsShare, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.StatsShare), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) StatsCategories(q repository.StatsQuery) ([]repository.StatsShare, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.StatsShare), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) StatsHours(q repository.StatsQuery) ([]repository.StatsHour, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.StatsHour), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) FindEmotions(ids []int) ([]model.PlutchikEmotions, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]model.PlutchikEmotions), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) MoodUnitByEmotion(emotionID int) (*model.MoodUnit, error) {
+	args := m.Called(emotionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MoodUnit), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) CreateWithEmotions(entry *model.MoodEntries, emotions []model.UserEmotionWheel) error {
+	args := m.Called(entry, emotions)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) ReplaceEmotions(entryID uint, emotions []model.UserEmotionWheel) error {
+	args := m.Called(entryID, emotions)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) EntryEmotions(entryID uint) ([]model.UserEmotionWheel, error) {
+	args := m.Called(entryID)
+	return args.Get(0).([]model.UserEmotionWheel), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) WheelEntries(q repository.StatsQuery) (int64, error) {
+	args := m.Called(q)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) WheelEmotions(q repository.StatsQuery) ([]repository.WheelEmotion, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.WheelEmotion), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) WheelDyads(q repository.StatsQuery) ([]repository.WheelDyad, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.WheelDyad), args.Error(1)
+}
+
+type MockPlutchikCombinationRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlutchikCombinationRepository) Create(combination *model.PlutchikCombinations) error {
+	args := m.Called(combination)
+	return args.Error(0)
+}
+
+func (m *MockPlutchikCombinationRepository) GetByID(id int) (*model.PlutchikCombinations, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PlutchikCombinations), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) Update(combination *model.PlutchikCombinations) error {
+	args := m.Called(combination)
+	return args.Error(0)
+}
+
+func (m *MockPlutchikCombinationRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPlutchikCombinationRepository) List(limit int, offset int) ([]model.PlutchikCombinations, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]model.PlutchikCombinations), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) ListAll() ([]model.PlutchikCombinations, error) {
+	args := m.Called()
+	return args.Get(0).([]model.PlutchikCombinations), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) GetByEmotionPair(emotion1ID, emotion2ID int) (*model.PlutchikCombinations, error) {
+	args := m.Called(emotion1ID, emotion2ID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PlutchikCombinations), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) ListByType(combinationType string) ([]model.PlutchikCombinations, error) {
+	args := m.Called(combinationType)
+	return args.Get(0).([]model.PlutchikCombinations), args.Error(1)
+}
+
+func (m *MockPlutchikCombinationRepository) EmotionList(limit int, offset int) ([]model.PlutchikEmotions, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]model.PlutchikEmotions), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) CalendarDays(q repository.StatsQuery) ([]repository.CalendarDay, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.CalendarDay), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) DayEntries(q repository.StatsQuery) ([]model.MoodEntries, error) {
+	args := m.Called(q)
+	return args.Get(0).([]model.MoodEntries), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) DayJournals(q repository.StatsQuery) ([]repository.DayJournal, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.DayJournal), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) DayMeditations(q repository.StatsQuery) ([]model.MeditationSessions, error) {
+	args := m.Called(q)
+	return args.Get(0).([]model.MeditationSessions), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) DayLessons(q repository.StatsQuery) ([]repository.DayLesson, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.DayLesson), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) SyncEntryTags(userID, entryID uint, kind string, names []string) error {
+	args := m.Called(userID, entryID, kind, names)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) EntryTags(entryID uint) ([]model.MoodTags, error) {
+	args := m.Called(entryID)
+	return args.Get(0).([]model.MoodTags), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) SearchTags(userID uint, kind, prefix string, limit int) ([]model.MoodTags, error) {
+	args := m.Called(userID, kind, prefix, limit)
+	return args.Get(0).([]model.MoodTags), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) AnalysisBaseline(q repository.AnalysisQuery) (repository.AnalysisBaseline, error) {
+	args := m.Called(q)
+	return args.Get(0).(repository.AnalysisBaseline), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) TagStats(q repository.AnalysisQuery) ([]repository.TagStat, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.TagStat), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) EntryTagLinks(q repository.AnalysisQuery) ([]repository.EntryTagLink, error) {
+	args := m.Called(q)
+	return args.Get(0).([]repository.EntryTagLink), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) SaveAnalyses(scores []repository.EntryScore) error {
+	args := m.Called(scores)
+	return args.Error(0)
+}
+
+func (m *MockMoodEntryRepository) FindAnalysis(entryID uint) (*model.AIMoodAnalysis, error) {
+	args := m.Called(entryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AIMoodAnalysis), args.Error(1)
+}
+
+func (m *MockMoodEntryRepository) UsersWithEntriesSince(since time.Time) ([]uint, error) {
+	args := m.Called(since)
+	return args.Get(0).([]uint), args.Error(1)
+}
@@ -0,0 +1,26 @@
+package mockRepository
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) Hit(key string, window time.Duration) (int64, time.Duration, error) {
+	args := m.Called(key, window)
+	return args.Get(0).(int64), args.Get(1).(time.Duration), args.Error(2)
+}
+
+func (m *MockRateLimitStore) Count(key string) (int64, time.Duration, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Get(1).(time.Duration), args.Error(2)
+}
+
+func (m *MockRateLimitStore) Reset(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
@@ -0,0 +1,53 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+	"mindsteps/internal/retention/repository"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRetentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetentionRepository) Policies() ([]model.DataRetentionPolicies, error) {
+	args := m.Called()
+	return args.Get(0).([]model.DataRetentionPolicies), args.Error(1)
+}
+
+func (m *MockRetentionRepository) DuePolicies(now time.Time) ([]model.DataRetentionPolicies, error) {
+	args := m.Called(now)
+	return args.Get(0).([]model.DataRetentionPolicies), args.Error(1)
+}
+
+func (m *MockRetentionRepository) MarkCleanup(policyID int, lastAt, nextAt time.Time) error {
+	args := m.Called(policyID, lastAt, nextAt)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) Count(target repository.Target, method string, cutoff time.Time) (int64, error) {
+	args := m.Called(target, method, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRetentionRepository) Archive(target repository.Target, cutoff time.Time, retentionDays, limit int) (int64, error) {
+	args := m.Called(target, cutoff, retentionDays, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRetentionRepository) Remove(target repository.Target, method string, cutoff time.Time, limit int) (int64, error) {
+	args := m.Called(target, method, cutoff, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRetentionRepository) CountExpiredArchives(target repository.Target, now time.Time) (int64, error) {
+	args := m.Called(target, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRetentionRepository) DropExpiredArchives(target repository.Target, now time.Time, limit int) (int64, error) {
+	args := m.Called(target, now, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package mockRepository
+
+import (
+	"mindsteps/database/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) List() ([]model.Roles, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Roles), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByID(id uint) (*model.Roles, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Roles), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByCode(code string) (*model.Roles, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Roles), args.Error(1)
+}
+
+func (m *MockRoleRepository) Create(role *model.Roles) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Update(role *model.Roles) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) FindActiveByOwner(userID uint) ([]model.Roles, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Roles), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListAssignments(userID uint) ([]model.RoleOwners, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.RoleOwners), args.Error(1)
+}
+
+func (m *MockRoleRepository) IsAssigned(userID, roleID uint) (bool, error) {
+	args := m.Called(userID, roleID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoleRepository) Assign(owner *model.RoleOwners) error {
+	args := m.Called(owner)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Revoke(userID, roleID uint) (bool, error) {
+	args := m.Called(userID, roleID)
+	return args.Bool(0), args.Error(1)
+}
@@ -2,6 +2,8 @@ package mockRepository
 
 import (
 	"mindsteps/database/model"
+	"mindsteps/internal/user/repository"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -36,11 +38,6 @@ func (m *MockUserRepository) Update(user *model.Users) error {
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Delete(id uint) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
 func (m *MockUserRepository) UpdateLastLogin(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -51,7 +48,104 @@ func (m *MockUserRepository) UpdatePassword(id uint, hashedPassword string) erro
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) PasswordHistory(id uint, limit int) ([]string, error) {
+	args := m.Called(id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockUserRepository) IncrementLoginCount(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) ScheduleDeletion(entry *model.DeletedDataLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindPendingDeletion(userID uint) (*model.DeletedDataLog, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DeletedDataLog), args.Error(1)
+}
+
+func (m *MockUserRepository) RecoverDeletion(entry *model.DeletedDataLog) (bool, error) {
+	args := m.Called(entry)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) DueDeletions(now time.Time, limit int) ([]model.DeletedDataLog, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]model.DeletedDataLog), args.Error(1)
+}
+
+func (m *MockUserRepository) MediaURLs(userID uint) ([]string, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) Purge(entry *model.DeletedDataLog) (bool, error) {
+	args := m.Called(entry)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateDataRequest(req *model.UserDataRequests) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDataRequests(userID uint) ([]model.UserDataRequests, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.UserDataRequests), args.Error(1)
+}
+
+func (m *MockUserRepository) FindDataRequest(userID, id uint) (*model.UserDataRequests, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserDataRequests), args.Error(1)
+}
+
+func (m *MockUserRepository) HasOpenDataRequest(userID uint, requestType string) (bool, error) {
+	args := m.Called(userID, requestType)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimDataRequests(requestType string, staleBefore time.Time, limit int) ([]model.UserDataRequests, error) {
+	args := m.Called(requestType, staleBefore, limit)
+	return args.Get(0).([]model.UserDataRequests), args.Error(1)
+}
+
+func (m *MockUserRepository) CompleteDataRequest(req *model.UserDataRequests) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FailDataRequest(id uint, message string) error {
+	args := m.Called(id, message)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ExpiredDataRequests(now time.Time, limit int) ([]model.UserDataRequests, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]model.UserDataRequests), args.Error(1)
+}
+
+func (m *MockUserRepository) ExpireDataRequest(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ExportRows(section repository.ExportSection, userID uint, from, to time.Time) (*repository.ExportTable, error) {
+	args := m.Called(section, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ExportTable), args.Error(1)
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/apikey/form"
+	apikeyService "mindsteps/internal/apikey/service"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/ratelimit"
+	"mindsteps/internal/rbac"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_Verify_GrantsScopesWithinRateLimit(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockAPIKeyRepository)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := apikeyService.NewAPIKeyService(mockRepo, mockLimiter)
+
+	var record *model.APIKeys
+	mockRepo.On("Create", mock.AnythingOfType("*model.APIKeys")).Run(func(args mock.Arguments) {
+		record = args.Get(0).(*model.APIKeys)
+		record.ID = 3
+	}).Return(nil)
+
+	actor := &auth.Token{UserID: 1, Permissions: []string{rbac.PermLessonWrite, rbac.PermAPIKeyManage}}
+	_, key, err := svc.Create(actor, &form.APIKeyForm{
+		Name:               "ml-service",
+		Scopes:             rbac.Permissions{"lesson": {"write"}},
+		RateLimitPerMinute: 2,
+	})
+	require.NoError(t, err)
+
+	mockRepo.On("FindByPrefix", record.Prefix).Return(record, nil)
+	mockRepo.On("TouchLastUsed", uint(3), "10.0.0.5").Return(nil)
+	mockLimiter.On("Hit", "apikey:"+record.Prefix, time.Minute).Return(int64(1), time.Minute, nil).Once()
+	mockLimiter.On("Hit", "apikey:"+record.Prefix, time.Minute).Return(int64(3), 20*time.Second, nil).Once()
+
+	// Act
+	claims, verifyErr := svc.Verify(key, "10.0.0.5")
+	_, limitErr := svc.Verify(key, "10.0.0.5")
+	_, wrongErr := svc.Verify("msk_"+record.Prefix+"_wrong-secret", "10.0.0.5")
+
+	// Assert
+	require.NoError(t, verifyErr)
+	assert.Equal(t, uint(0), claims.UserID)
+	assert.Equal(t, "ml-service", claims.SystemCode)
+	assert.Equal(t, uint(3), claims.APIKeyID)
+	assert.Equal(t, []string{rbac.PermLessonWrite}, claims.Permissions)
+	assert.NotContains(t, record.KeyHash, key)
+	var rateErr *ratelimit.LimitError
+	assert.ErrorAs(t, limitErr, &rateErr)
+	assert.ErrorIs(t, wrongErr, auth.ErrInvalidAPIKey)
+	mockRepo.AssertNumberOfCalls(t, "TouchLastUsed", 1)
+}
+
+// stubAPIKeys нь key -> system identity буцаах auth.APIKeyVerifier
+type stubAPIKeys map[string]*auth.Token
+
+func (s stubAPIKeys) Verify(key, ipAddress string) (*auth.Token, error) {
+	if claims, ok := s[key]; ok {
+		return claims, nil
+	}
+	return nil, auth.ErrInvalidAPIKey
+}
+
+func TestAPIKeyMiddleware_RequiresKeyWithScope(t *testing.T) {
+	// Arrange
+	previous := auth.APIKeys
+	auth.APIKeys = stubAPIKeys{
+		"msk_ml":     {SystemCode: "ml-service", Permissions: []string{rbac.PermMoodRead}},
+		"msk_report": {SystemCode: "reports", Permissions: []string{rbac.PermStatsRead}},
+	}
+	t.Cleanup(func() { auth.APIKeys = previous })
+
+	app := fiber.New()
+	system := app.Group("/system", auth.APIKeyMiddleware)
+	system.Get("/mood", auth.RequireScope(rbac.PermMoodRead), func(c *fiber.Ctx) error {
+		return c.SendString(auth.GetTokenInfo(c).SystemCode)
+	})
+	get := func(key string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/system/mood", nil)
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Act
+	allowed := get("msk_ml")
+	wrongScope := get("msk_report")
+	invalid := get("msk_unknown")
+	missing := get("")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, allowed)
+	assert.Equal(t, fiber.StatusForbidden, wrongScope)
+	assert.Equal(t, fiber.StatusUnauthorized, invalid)
+	assert.Equal(t, fiber.StatusUnauthorized, missing)
+}
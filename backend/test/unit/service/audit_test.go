@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/shared"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuditApp(t *testing.T, mockAuthRepo *mockRepository.MockAuthRepository) *fiber.App {
+	previousAudit := auth.Audit
+	auth.Audit = mockAuthRepo
+	t.Cleanup(func() { auth.Audit = previousAudit })
+
+	app := fiber.New()
+	api := app.Group("/api/v1")
+	api.Use(auth.AuditMiddleware)
+	return app
+}
+
+func auditEntries(mockAuthRepo *mockRepository.MockAuthRepository) []*model.SystemAuditLog {
+	var entries []*model.SystemAuditLog
+	for _, call := range mockAuthRepo.Calls {
+		if call.Method == "CreateAuditLog" {
+			entries = append(entries, call.Arguments.Get(0).(*model.SystemAuditLog))
+		}
+	}
+	return entries
+}
+
+func TestAuditMiddleware_UpdateRecordsOnlyChangedFields(t *testing.T) {
+	// Arrange
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	app := newAuditApp(t, mockAuthRepo)
+	app.Put("/api/v1/journals/5", func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"success": true}) })
+
+	mockAuthRepo.On("AuditSnapshot", model.TableNameJournals, uint(5)).
+		Return(map[string]interface{}{"id": 5, "content": "хуучин", "word_count": 1, "is_private": true}, nil).Once()
+	mockAuthRepo.On("AuditSnapshot", model.TableNameJournals, uint(5)).
+		Return(map[string]interface{}{"id": 5, "content": "шинэ тэмдэглэл", "word_count": 2, "is_private": true}, nil).Once()
+	mockAuthRepo.On("CreateAuditLog", mock.AnythingOfType("*model.SystemAuditLog")).Return(nil)
+
+	// Act
+	req := httptest.NewRequest(fiber.MethodPut, "/api/v1/journals/5", strings.NewReader(`{"content":"шинэ тэмдэглэл"}`))
+	req.Header.Set(auth.APIKeyHeader, "msk_test")
+	resp, err := app.Test(req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	entries := auditEntries(mockAuthRepo)
+	require.Len(t, entries, 1)
+	assert.Equal(t, auth.AuditUpdate, entries[0].ActionType)
+	assert.Equal(t, "journals", entries[0].EntityType)
+	assert.Equal(t, uint(5), entries[0].EntityID)
+	var oldValue, newValue map[string]interface{}
+	require.NoError(t, json.Unmarshal(entries[0].OldValue, &oldValue))
+	require.NoError(t, json.Unmarshal(entries[0].NewValue, &newValue))
+	assert.Equal(t, map[string]interface{}{"content": auth.Redacted, "word_count": float64(1)}, oldValue)
+	assert.Equal(t, map[string]interface{}{"content": auth.Redacted, "word_count": float64(2)}, newValue)
+}
+
+func TestAuditMiddleware_RedactsPasswordsAndSkipsFailures(t *testing.T) {
+	// Arrange
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	app := newAuditApp(t, mockAuthRepo)
+	app.Post("/api/v1/auth/register", func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"success": true}) })
+	app.Post("/api/v1/auth/login", shared.ResponseUnauthorized)
+	mockAuthRepo.On("CreateAuditLog", mock.AnythingOfType("*model.SystemAuditLog")).Return(nil)
+
+	body := `{"email":"test@example.com","password":"Secret123!","confirm_password":"Secret123!"}`
+	post := func(path string) int {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Act
+	registered := post("/api/v1/auth/register")
+	failed := post("/api/v1/auth/login")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, registered)
+	assert.Equal(t, fiber.StatusUnauthorized, failed)
+	entries := auditEntries(mockAuthRepo)
+	require.Len(t, entries, 1)
+	assert.Equal(t, auth.AuditCreate, entries[0].ActionType)
+	assert.Equal(t, "/api/v1/auth/register", entries[0].EntityType)
+	assert.NotContains(t, string(entries[0].NewValue), "Secret123!")
+	assert.Contains(t, string(entries[0].NewValue), "test@example.com")
+	mockAuthRepo.AssertNotCalled(t, "AuditSnapshot", mock.Anything, mock.Anything)
+}
+
+func TestAuditMiddleware_SkipsSnapshotWithoutCredentials(t *testing.T) {
+	// Arrange
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	app := newAuditApp(t, mockAuthRepo)
+	app.Put("/api/v1/journals/5", shared.ResponseUnauthorized)
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPut, "/api/v1/journals/5", strings.NewReader(`{"content":"шинэ"}`)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	mockAuthRepo.AssertNotCalled(t, "AuditSnapshot", mock.Anything, mock.Anything)
+	assert.Empty(t, auditEntries(mockAuthRepo))
+}
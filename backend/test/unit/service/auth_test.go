@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authRepo "mindsteps/internal/auth/repository"
+	authService "mindsteps/internal/auth/service"
+	"mindsteps/internal/mailer"
+	"mindsteps/internal/ratelimit"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	// Arrange
+	auth.Gjwt = &mockRepository.MockGJWT{}
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, mockRoleRepo, new(mockRepository.MockMailer), new(mockRepository.MockRateLimitStore))
+
+	oldHash := authRepo.HashToken("secret")
+	mockAuthRepo.On("FindSessionByID", uint(5)).Return(&model.UserSessions{
+		ID:        5,
+		UserID:    1,
+		TokenHash: oldHash,
+		IsActive:  true,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.Users{ID: 1, IsActive: true}, nil)
+	mockRoleRepo.On("FindActiveByOwner", uint(1)).Return([]model.Roles{}, nil)
+	mockAuthRepo.On("FindMFA", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockAuthRepo.On("RotateSession", uint(5), oldHash, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockAuthRepo.On("CreateRevokedToken", mock.AnythingOfType("*model.RevokedTokens")).Return(nil)
+
+	// Act
+	pair, err := svc.Refresh(&authForm.RefreshTokenForm{RefreshToken: "5.secret"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "mocked-token", pair.AccessToken)
+	assert.NotEqual(t, "5.secret", pair.RefreshToken)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ReuseRevokesSession(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), new(mockRepository.MockMailer), new(mockRepository.MockRateLimitStore))
+
+	mockAuthRepo.On("FindSessionByID", uint(5)).Return(&model.UserSessions{
+		ID:        5,
+		UserID:    1,
+		TokenHash: authRepo.HashToken("current"),
+		IsActive:  true,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockAuthRepo.On("IsTokenRevoked", authRepo.HashToken("old")).Return(true, nil)
+	mockAuthRepo.On("RevokeSession", uint(5), "refresh_reuse").Return(nil)
+
+	// Act
+	pair, err := svc.Refresh(&authForm.RefreshTokenForm{RefreshToken: "5.old"})
+
+	// Assert
+	assert.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthService_ForgotPassword_SendsResetCode(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), mockMailer, mockLimiter)
+
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(0), time.Duration(0), nil)
+	mockLimiter.On("Hit", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(int64(1), time.Minute, nil)
+
+	var otp *model.AuthOTP
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(&model.Users{ID: 1, Name: "Test", Email: "test@example.com", Language: "en"}, nil)
+	mockAuthRepo.On("InvalidateOTPs", uint(1), "password_reset").Return(nil)
+	mockAuthRepo.On("CreateOTP", mock.AnythingOfType("*model.AuthOTP")).Run(func(args mock.Arguments) {
+		otp = args.Get(0).(*model.AuthOTP)
+	}).Return(nil)
+	mockMailer.On("Send", mock.MatchedBy(func(msg *mailer.Message) bool {
+		return msg.To == "test@example.com" && strings.Contains(msg.Text, otp.OtpCode)
+	})).Return(nil)
+
+	// Act
+	err := svc.ForgotPassword(&authForm.ForgotPasswordForm{Email: "test@example.com"}, auth.ClientInfo{IPAddress: "127.0.0.1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "password_reset", otp.OtpType)
+	assert.Len(t, otp.OtpCode, 6)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyOTP_InvalidatesAfterMaxFailures(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), new(mockRepository.MockMailer), mockLimiter)
+
+	userKey := "otp-fail:user:test@example.com:email_verification"
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(4), 10*time.Minute, nil)
+	mockLimiter.On("Hit", "otp-fail:ip:127.0.0.1", mock.AnythingOfType("time.Duration")).Return(int64(5), 10*time.Minute, nil)
+	mockLimiter.On("Hit", userKey, mock.AnythingOfType("time.Duration")).Return(int64(5), 10*time.Minute, nil)
+	mockAuthRepo.On("FindValidOTP", "Test@Example.com", "000000", "email_verification").Return(nil, assert.AnError)
+	mockUserRepo.On("FindByEmail", "Test@Example.com").Return(&model.Users{ID: 1, Email: "test@example.com"}, nil)
+	mockAuthRepo.On("InvalidateOTPs", uint(1), "email_verification").Return(nil)
+
+	// Act
+	err := svc.VerifyOTP(&authForm.VerifyOTPForm{Email: "Test@Example.com", OTPCode: "000000"}, auth.ClientInfo{IPAddress: "127.0.0.1"})
+
+	// Assert
+	var limitErr *ratelimit.LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 600, limitErr.RetryAfterSeconds())
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_LocksAfterRepeatedFailures(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), new(mockRepository.MockMailer), mockLimiter)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(&model.Users{ID: 1, Email: "test@example.com", Password: string(hashed), IsActive: true}, nil)
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(0), time.Duration(0), nil)
+	mockLimiter.On("Hit", "login-fail:ip:127.0.0.1", time.Hour).Return(int64(5), time.Hour, nil)
+	mockLimiter.On("Hit", "login-fail:email:test@example.com", 24*time.Hour).Return(int64(6), time.Hour, nil)
+	mockLimiter.On("Hit", "login-lock:email:test@example.com", 2*time.Minute).Return(int64(1), 2*time.Minute, nil)
+	mockAuthRepo.On("CreateAccessLog", mock.MatchedBy(func(entry *model.UserDataAccessLog) bool {
+		return entry.UserID == 1 && entry.AccessType == "login" && entry.AccessReason == "invalid_password"
+	})).Return(nil)
+
+	// Act
+	pair, _, err := svc.Login(&authForm.LoginForm{Email: "test@example.com", Password: "wrong-password"}, auth.ClientInfo{IPAddress: "127.0.0.1"})
+
+	// Assert
+	var limitErr *ratelimit.LimitError
+	assert.Nil(t, pair)
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 120, limitErr.RetryAfterSeconds())
+	mockLimiter.AssertExpectations(t)
+	mockAuthRepo.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/mailer"
+	userForm "mindsteps/internal/user/form"
+	userRepo "mindsteps/internal/user/repository"
+	userService "mindsteps/internal/user/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestUserService_DeleteAccount_DeactivatesAndCanRecover(t *testing.T) {
+	// Arrange
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	auth.Gjwt = auth.NewGJWT("test", key, map[string]*rsa.PublicKey{"test": &key.PublicKey})
+
+	mockRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, mockMailer, nil)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &model.Users{ID: 1, Name: "Test", Email: "test@example.com", Password: string(hash), Language: "en", IsActive: true}
+	mockRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockAuthRepo.On("FindMFA", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindPendingDeletion", uint(1)).Return(nil, gorm.ErrRecordNotFound).Once()
+	var entry *model.DeletedDataLog
+	mockRepo.On("ScheduleDeletion", mock.AnythingOfType("*model.DeletedDataLog")).Run(func(args mock.Arguments) {
+		entry = args.Get(0).(*model.DeletedDataLog)
+		entry.ID = 7
+	}).Return(nil)
+	mockAuthRepo.On("RevokeUserSessions", uint(1), "account_deleted").Return([]uint{3}, nil)
+	var sent []*mailer.Message
+	mockMailer.On("Send", mock.AnythingOfType("*mailer.Message")).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(0).(*mailer.Message))
+	}).Return(nil)
+
+	// Act
+	deleteErr := svc.DeleteAccount(1, &userForm.DeleteAccountForm{Password: "Secret123!"})
+	require.NoError(t, deleteErr)
+	require.Len(t, sent, 1)
+	_, link, found := strings.Cut(sent[0].Text, "/account/recover?")
+	require.True(t, found)
+	query, err := url.ParseQuery(strings.SplitN(link, "\n", 2)[0])
+	require.NoError(t, err)
+
+	mockRepo.On("FindPendingDeletion", uint(1)).Return(entry, nil)
+	mockRepo.On("RecoverDeletion", entry).Return(true, nil)
+	recoverErr := svc.RecoverAccount(&userForm.RecoverAccountForm{Token: query.Get("token")})
+
+	// Assert
+	assert.NoError(t, recoverErr)
+	assert.True(t, entry.CanRecover)
+	assert.Equal(t, userRepo.DeletionDeactivate, entry.DeletionMethod)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), entry.RecoveryExpiresAt, time.Minute)
+	mockAuthRepo.AssertCalled(t, "RevokeUserSessions", uint(1), "account_deleted")
+	mockRepo.AssertCalled(t, "RecoverDeletion", entry)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "MindSteps - Your account has been restored", sent[1].Subject)
+}
+
+// stubSecondFactor нь зөвхөн code-г зөв гэж үзэх auth.SecondFactorVerifier
+type stubSecondFactor struct{ code string }
+
+func (s stubSecondFactor) VerifySecondFactor(userID uint, code string) error {
+	if code != s.code {
+		return errors.New("баталгаажуулах код буруу байна")
+	}
+	return nil
+}
+
+func TestUserService_DeleteAccount_RequiresReauthentication(t *testing.T) {
+	// Arrange
+	previous := auth.SecondFactor
+	auth.SecondFactor = stubSecondFactor{code: "123456"}
+	t.Cleanup(func() { auth.SecondFactor = previous })
+
+	mockRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, nil, nil)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mockRepo.On("FindByID", uint(1)).Return(&model.Users{ID: 1, Password: string(hash)}, nil)
+	mockRepo.On("FindByID", uint(2)).Return(&model.Users{ID: 2, Password: string(hash)}, nil)
+	mockAuthRepo.On("FindMFA", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockAuthRepo.On("FindMFA", uint(2)).Return(&model.UserMfa{UserID: 2, EnabledAt: time.Now()}, nil)
+
+	// Act
+	emptyErr := svc.DeleteAccount(1, &userForm.DeleteAccountForm{})
+	wrongPasswordErr := svc.DeleteAccount(1, &userForm.DeleteAccountForm{Password: "wrong"})
+	passwordOnlyErr := svc.DeleteAccount(2, &userForm.DeleteAccountForm{Password: "Secret123!"})
+	wrongCodeErr := svc.DeleteAccount(2, &userForm.DeleteAccountForm{Code: "000000"})
+
+	// Assert
+	assert.Error(t, emptyErr)
+	assert.ErrorIs(t, wrongPasswordErr, userService.ErrInvalidPassword)
+	assert.ErrorIs(t, passwordOnlyErr, userService.ErrSecondFactorRequired)
+	assert.Error(t, wrongCodeErr)
+	mockRepo.AssertNotCalled(t, "ScheduleDeletion", mock.Anything)
+}
+
+func TestUserService_PurgeDueAccounts_RemovesMediaAfterPurge(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockMedia := new(mockRepository.MockMediaStore)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, nil, mockMedia)
+
+	due := []model.DeletedDataLog{{ID: 7, UserID: 1}, {ID: 8, UserID: 2}}
+	mockRepo.On("DueDeletions", mock.AnythingOfType("time.Time"), 50).Return(due, nil)
+	for _, id := range []uint{1, 2} {
+		mockRepo.On("FindByID", id).Return(&model.Users{ID: id}, nil)
+		mockAuthRepo.On("RevokeUserSessions", id, "account_deleted").Return([]uint{}, nil)
+	}
+	mockRepo.On("MediaURLs", uint(1)).Return([]string{"https://cdn.example.com/users/1/avatar.png"}, nil)
+	mockRepo.On("MediaURLs", uint(2)).Return([]string{"https://cdn.example.com/users/2/avatar.png"}, nil)
+	mockRepo.On("Purge", &due[0]).Return(true, nil)
+	// Хоёр дахь нь энэ хооронд сэргээгдсэн
+	mockRepo.On("Purge", &due[1]).Return(false, nil)
+	mockMedia.On("RemoveURL", "https://cdn.example.com/users/1/avatar.png").Return(nil)
+	mockMedia.On("RemovePrefix", "users/1/").Return(nil)
+
+	// Act
+	count, err := svc.PurgeDueAccounts()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	mockMedia.AssertExpectations(t)
+	mockMedia.AssertNotCalled(t, "RemovePrefix", "users/2/")
+}
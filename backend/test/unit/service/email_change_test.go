@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authService "mindsteps/internal/auth/service"
+	"mindsteps/internal/mailer"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestAuthService_EmailChange_SwapsOnlyAfterNewAddressConfirms(t *testing.T) {
+	// Arrange
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	auth.Gjwt = auth.NewGJWT("test", key, map[string]*rsa.PublicKey{"test": &key.PublicKey})
+
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), mockMailer, mockLimiter)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	user := &model.Users{ID: 1, Name: "Test", Email: "old@example.com", Password: string(hashed), Language: "en", IsEmailVerified: true}
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockUserRepo.On("FindByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(0), time.Duration(0), nil)
+	mockLimiter.On("Hit", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(int64(1), time.Minute, nil)
+	mockLimiter.On("Reset", mock.AnythingOfType("string")).Return(nil)
+
+	var change *model.UserEmailChanges
+	mockAuthRepo.On("CreateEmailChange", mock.AnythingOfType("*model.UserEmailChanges")).Run(func(args mock.Arguments) {
+		change = args.Get(0).(*model.UserEmailChanges)
+		change.ID = 5
+	}).Return(nil)
+	mockAuthRepo.On("InvalidateOTPs", uint(1), "email_change").Return(nil)
+	var otp *model.AuthOTP
+	mockAuthRepo.On("CreateOTP", mock.AnythingOfType("*model.AuthOTP")).Run(func(args mock.Arguments) {
+		otp = args.Get(0).(*model.AuthOTP)
+	}).Return(nil)
+	var sent []*mailer.Message
+	mockMailer.On("Send", mock.AnythingOfType("*mailer.Message")).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(0).(*mailer.Message))
+	}).Return(nil)
+
+	client := auth.ClientInfo{IPAddress: "127.0.0.1"}
+
+	// Act
+	requestErr := svc.RequestEmailChange(1, &authForm.EmailChangeForm{NewEmail: "New@Example.com", Password: "correct-password"}, client)
+	require.NoError(t, requestErr)
+	mockAuthRepo.AssertNotCalled(t, "ConfirmEmailChange", mock.Anything)
+
+	mockAuthRepo.On("FindPendingEmailChange", uint(1)).Return(change, nil)
+	mockAuthRepo.On("ConsumeOTP", uint(1), otp.OtpCode, "email_change").Return(true, nil)
+	mockAuthRepo.On("ConfirmEmailChange", change).Return(nil)
+	mockAuthRepo.On("RevokeOtherSessions", uint(1), uint(9), "email_change").Return([]uint{10, 11}, nil)
+	_, confirmErr := svc.ConfirmEmailChange(1, 9, &authForm.EmailChangeConfirmForm{OTPCode: otp.OtpCode})
+
+	// Assert
+	assert.NoError(t, confirmErr)
+	assert.Equal(t, "old@example.com", change.OldEmail)
+	assert.Equal(t, "new@example.com", change.NewEmail)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "old@example.com", sent[0].To)
+	assert.Contains(t, sent[0].Text, "/auth/email-change/cancel?token=")
+	assert.Equal(t, "new@example.com", sent[1].To)
+	assert.Contains(t, sent[1].Text, otp.OtpCode)
+	mockAuthRepo.AssertCalled(t, "RevokeOtherSessions", uint(1), uint(9), "email_change")
+}
@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/errorlog"
+	errorLogForm "mindsteps/internal/errorlog/form"
+	errorLogService "mindsteps/internal/errorlog/service"
+	"mindsteps/internal/shared"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorLogMiddleware_RecordsSanitizedPanicsAndResponses(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockErrorLogRepository)
+	previousStore := errorlog.Store
+	errorlog.Store = mockRepo
+	t.Cleanup(func() { errorlog.Store = previousStore })
+
+	app := fiber.New(fiber.Config{ErrorHandler: errorlog.ErrorHandler})
+	app.Use(errorlog.Middleware)
+	app.Post("/api/v1/journals/:id", func(c *fiber.Ctx) error { panic("journal " + c.Params("id") + " эвдэрсэн") })
+	app.Get("/api/v1/goals", func(c *fiber.Ctx) error {
+		return shared.ResponseErr(c, "user test@example.com олдсонгүй")
+	})
+
+	var entries []*model.ErrorLogs
+	mockRepo.On("Record", mock.AnythingOfType("*model.ErrorLogs")).Run(func(args mock.Arguments) {
+		entries = append(entries, args.Get(0).(*model.ErrorLogs))
+	}).Return(uint(42), nil)
+
+	post := func(path string) fiber.Map {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(`{"content":"нууц","password":"Secret123!"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		var payload fiber.Map
+		require.NoError(t, json.Unmarshal(body, &payload))
+		return payload
+	}
+
+	// Act
+	first := post("/api/v1/journals/5?token=abc&page=1")
+	post("/api/v1/journals/7")
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/goals", nil))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, float64(42), first["error_id"])
+	assert.NotContains(t, first["message"], "эвдэрсэн")
+
+	require.Len(t, entries, 3)
+	assert.Equal(t, errorlog.TypePanic, entries[0].ErrorType)
+	assert.Contains(t, entries[0].StackTrace, "runtime/debug.Stack")
+	assert.Equal(t, "/api/v1/journals/5?page=1&token=%5BREDACTED%5D", entries[0].RequestURL)
+	assert.NotContains(t, entries[0].RequestBody, "Secret123!")
+	assert.NotContains(t, entries[0].RequestBody, "нууц")
+	assert.Equal(t, entries[0].Fingerprint, entries[1].Fingerprint)
+
+	assert.Equal(t, errorlog.TypeResponse, entries[2].ErrorType)
+	assert.Equal(t, "user "+auth.Redacted+" олдсонгүй", entries[2].ErrorMessage)
+	assert.NotEqual(t, entries[0].Fingerprint, entries[2].Fingerprint)
+}
+
+func TestErrorLogService_Resolve(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockErrorLogRepository)
+	svc := errorLogService.NewErrorLogService(mockRepo)
+
+	mockRepo.On("FindByID", uint(1)).Return(&model.ErrorLogs{ID: 1, ResolutionNotes: "шалгаж байна"}, nil)
+	mockRepo.On("FindByID", uint(2)).Return(&model.ErrorLogs{ID: 2, IsResolved: true}, nil)
+	mockRepo.On("Resolve", uint(1), uint(9), "", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	resolved, err := svc.Resolve(1, 9, &errorLogForm.ResolveForm{})
+	_, againErr := svc.Resolve(2, 9, &errorLogForm.ResolveForm{Notes: "дахин"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resolved.IsResolved)
+	assert.Equal(t, uint(9), resolved.ResolvedByID)
+	assert.Equal(t, "шалгаж байна", resolved.ResolutionNotes)
+	assert.ErrorIs(t, againErr, errorLogService.ErrAlreadyResolved)
+	mockRepo.AssertNumberOfCalls(t, "Resolve", 1)
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	userForm "mindsteps/internal/user/form"
+	userRepo "mindsteps/internal/user/repository"
+	userService "mindsteps/internal/user/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_RequestDataExport_ValidatesTables(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockUserRepository)
+	svc := userService.NewUserService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("HasOpenDataRequest", uint(1), userRepo.DataRequestTypeExport).Return(false, nil)
+	mockRepo.On("CreateDataRequest", mock.AnythingOfType("*model.UserDataRequests")).Return(nil)
+
+	// Act
+	_, unknownErr := svc.RequestDataExport(1, &userForm.DataExportForm{Tables: []string{"users"}})
+	req, err := svc.RequestDataExport(1, &userForm.DataExportForm{
+		Tables:   []string{"journals", "goals", "journals"},
+		DateFrom: "2026-01-01",
+		DateTo:   "2026-03-31",
+	})
+
+	// Assert
+	assert.Error(t, unknownErr)
+	require.NoError(t, err)
+	assert.Equal(t, userRepo.DataRequestPending, req.Status)
+	assert.Equal(t, "{\"journals\",\"goals\"}", req.RequestedTables)
+	assert.Equal(t, []string{"journals", "goals"}, userService.RequestedTables(req))
+	assert.Equal(t, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), req.DateRangeEnd)
+	mockRepo.AssertNumberOfCalls(t, "CreateDataRequest", 1)
+}
+
+func TestUserService_ProcessDataExports_UploadsZipAndNotifies(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	mockMedia := new(mockRepository.MockMediaStore)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, mockMailer, mockMedia)
+
+	user := &model.Users{ID: 1, Name: "Test", Email: "test@example.com", Language: "en"}
+	req := model.UserDataRequests{ID: 5, UserID: 1, RequestedTables: "{journals}"}
+	mockRepo.On("ExpiredDataRequests", mock.AnythingOfType("time.Time"), 50).Return([]model.UserDataRequests{}, nil)
+	mockRepo.On("ClaimDataRequests", userRepo.DataRequestTypeExport, mock.AnythingOfType("time.Time"), 5).Return([]model.UserDataRequests{req}, nil)
+	mockRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockRepo.On("ExportRows", mock.MatchedBy(func(s userRepo.ExportSection) bool { return s.Key == "journals" }), uint(1), time.Time{}, time.Time{}).
+		Return(&userRepo.ExportTable{
+			Columns: []string{"id", "title", "created_at"},
+			Rows:    [][]interface{}{{int64(3), "Өдрийн тэмдэглэл", time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)}},
+		}, nil)
+	var archive []byte
+	mockMedia.On("Put", "users/1/exports/5.zip", "application/zip", mock.Anything, mock.AnythingOfType("int64")).Run(func(args mock.Arguments) {
+		archive, _ = io.ReadAll(args.Get(2).(io.Reader))
+	}).Return(nil)
+	mockMedia.On("PresignedURL", "users/1/exports/5.zip", 7*24*time.Hour).Return("https://r2.example.com/bucket/users/1/exports/5.zip?X-Amz-Signature=abc", nil)
+	var completed *model.UserDataRequests
+	mockRepo.On("CompleteDataRequest", mock.AnythingOfType("*model.UserDataRequests")).Run(func(args mock.Arguments) {
+		completed = args.Get(0).(*model.UserDataRequests)
+	}).Return(nil)
+	mockAuthRepo.On("CreateNotification", mock.AnythingOfType("*model.Notifications")).Return(nil)
+	mockMailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Act
+	count, err := svc.ProcessDataExports()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NotNil(t, completed)
+	assert.Contains(t, completed.ExportFileURL, "X-Amz-Signature")
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), completed.ExportExpiresAt, time.Minute)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	require.Contains(t, files, "journals.json")
+	assert.Equal(t, "id,title,created_at\n3,Өдрийн тэмдэглэл,2026-02-01T09:00:00Z\n", string(files["journals.csv"]))
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["journals.json"], &rows))
+	assert.Equal(t, "Өдрийн тэмдэглэл", rows[0]["title"])
+	mockAuthRepo.AssertCalled(t, "CreateNotification", mock.AnythingOfType("*model.Notifications"))
+	mockMailer.AssertNumberOfCalls(t, "Send", 1)
+}
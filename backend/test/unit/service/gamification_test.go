@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	gamificationService "mindsteps/internal/gamification/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// storedWallClock нь t-г DB-ээс уншсан timestamp without time zone (Asia/Ulaanbaatar цаг, UTC байршил) болгоно.
+func storedWallClock(t time.Time) time.Time {
+	ub := t.In(time.FixedZone("Asia/Ulaanbaatar", 8*60*60))
+	return time.Date(ub.Year(), ub.Month(), ub.Day(), ub.Hour(), ub.Minute(), ub.Second(), 0, time.UTC)
+}
+
+func addXPWithLastActivity(t *testing.T, timezone string, lastActivity time.Time) *model.UserGamification {
+	mockRepo := new(mockRepository.MockGamificationRepository)
+	svc := gamificationService.NewGamificationService(mockRepo)
+	stats := &model.UserGamification{UserID: 1, CurrentStreak: 3, LongestStreak: 3, LastActivityAt: storedWallClock(lastActivity)}
+	mockRepo.On("GetByUserID", uint(1)).Return(stats, nil)
+	mockRepo.On("UserTimezone", uint(1)).Return(timezone, nil)
+	mockRepo.On("GetLevelByScore", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateScoreHistory", mock.Anything).Return(nil)
+	mockRepo.On("UpdateProgress", stats).Return(nil)
+
+	require.NoError(t, svc.AddXP(1, 10, "mood_entry", 5, "{}"))
+	return stats
+}
+
+func TestGamificationService_AddXP_CountsStreakDaysInUserTimezone(t *testing.T) {
+	// Arrange
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	now := time.Now().In(loc)
+	yesterdayNoon := time.Date(now.Year(), now.Month(), now.Day()-1, 12, 0, 0, 0, loc)
+
+	// Act
+	stats := addXPWithLastActivity(t, "America/Los_Angeles", yesterdayNoon)
+
+	// Assert
+	assert.Equal(t, 4, stats.CurrentStreak)
+	assert.Equal(t, 4, stats.LongestStreak)
+}
+
+func TestGamificationService_AddXP_KeepsStreakOnSameLocalDay(t *testing.T) {
+	// Arrange: хэрэглэгчийн шөнө дунд нь Улаанбаатарт өөр өдөр байж болно
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	// Act
+	stats := addXPWithLastActivity(t, "America/Los_Angeles", midnight)
+
+	// Assert
+	assert.Equal(t, 3, stats.CurrentStreak)
+	assert.Equal(t, 3, stats.LongestStreak)
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"crypto/rsa"
+	"net/http/httptest"
+	"testing"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authService "mindsteps/internal/auth/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_Impersonate_ReadOnlyAndAudited(t *testing.T) {
+	// Arrange
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	gjwt := auth.NewGJWT("test", key, map[string]*rsa.PublicKey{"test": &key.PublicKey})
+	auth.Gjwt = gjwt
+
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, mockRoleRepo, mockMailer, new(mockRepository.MockRateLimitStore))
+
+	previousSessions, previousAudit := auth.Sessions, auth.Audit
+	auth.Sessions, auth.Audit = nil, mockAuthRepo
+	t.Cleanup(func() { auth.Sessions, auth.Audit = previousSessions, previousAudit })
+
+	user := &model.Users{ID: 2, Name: "Test", Email: "user@example.com", Language: "en", IsActive: true, IsEmailVerified: true}
+	mockUserRepo.On("FindByID", uint(2)).Return(user, nil)
+	mockRoleRepo.On("FindActiveByOwner", uint(2)).Return([]model.Roles{}, nil)
+	mockAuthRepo.On("CreateSession", mock.AnythingOfType("*model.UserSessions")).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.UserSessions).ID = 40
+	}).Return(nil)
+	mockAuthRepo.On("CreateAuditLog", mock.AnythingOfType("*model.SystemAuditLog")).Return(nil)
+	mockAuthRepo.On("CreateNotification", mock.AnythingOfType("*model.Notifications")).Return(nil)
+	mockMailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	actor := &auth.Token{UserID: 1, Level: 50, Permissions: []string{"user:impersonate"}}
+	client := auth.ClientInfo{IPAddress: "127.0.0.1"}
+
+	app := fiber.New()
+	app.All("/mood", auth.TokenMiddleware, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Act
+	result, impersonateErr := svc.Impersonate(actor, 2, &authForm.ImpersonateForm{Reason: "TICKET-12"}, client)
+	require.NoError(t, impersonateErr)
+
+	send := func(method string) int {
+		req := httptest.NewRequest(method, "/mood", nil)
+		req.Header.Set("Authorization", "Bearer "+result.AccessToken)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	getStatus := send(fiber.MethodGet)
+	postStatus := send(fiber.MethodPost)
+
+	// Assert
+	assert.True(t, result.ReadOnly)
+	claims, err := gjwt.ReadToken(result.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), claims.UserID)
+	assert.Equal(t, uint(1), claims.ImpersonatorID)
+	assert.Equal(t, uint(40), claims.SessionID)
+	assert.Empty(t, claims.Permissions)
+	assert.Equal(t, fiber.StatusOK, getStatus)
+	assert.Equal(t, fiber.StatusForbidden, postStatus)
+	mockAuthRepo.AssertCalled(t, "CreateAuditLog", mock.MatchedBy(func(entry *model.SystemAuditLog) bool {
+		return entry.ActionType == auth.AuditImpersonationStart && entry.UserID == 1 && entry.EntityID == 2
+	}))
+	requests := 0
+	for _, call := range mockAuthRepo.Calls {
+		if call.Method == "CreateAuditLog" && call.Arguments.Get(0).(*model.SystemAuditLog).ActionType == auth.AuditImpersonationRequest {
+			requests++
+		}
+	}
+	assert.Equal(t, 2, requests)
+	mockAuthRepo.AssertCalled(t, "CreateNotification", mock.MatchedBy(func(n *model.Notifications) bool {
+		return n.UserID == 2 && n.NotificationType == "support_access"
+	}))
+	mockMailer.AssertNumberOfCalls(t, "Send", 1)
+}
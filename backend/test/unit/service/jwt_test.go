@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeyring_SignsWithActiveKeyAndVerifiesRotated(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, version := range []int{1, 2, 3} {
+		key, err := auth.GenerateKey()
+		require.NoError(t, err)
+		require.NoError(t, auth.WriteKeyFile(dir, auth.JWTKeyID(version), key))
+	}
+
+	mockKeyRepo := new(mockRepository.MockKeyRepository)
+	mockKeyRepo.On("ListKeys", "jwt").Return([]model.EncryptionKeys{
+		{KeyVersion: 1, ExpiresAt: time.Now().Add(-time.Hour)}, // retired
+		{KeyVersion: 2, ExpiresAt: time.Now().Add(time.Hour)},  // rotated, verify-only
+		{KeyVersion: 3, IsActive: true},
+	}, nil)
+
+	// Act
+	ring, err := auth.LoadKeyring(mockKeyRepo, dir, auth.LegacyKeys{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-v3", ring.SigningKeyID)
+	assert.NotContains(t, ring.PublicKeys, "jwt-v1")
+	assert.Contains(t, ring.PublicKeys, "jwt-v2")
+
+	oldSigner := auth.NewGJWT("jwt-v2", mustReadKey(t, dir, "jwt-v2"), ring.PublicKeys)
+	oldToken, err := oldSigner.GenerateToken(&auth.Token{UserID: 7}, 15)
+	require.NoError(t, err)
+
+	current := auth.NewGJWT(ring.SigningKeyID, ring.Private, ring.PublicKeys)
+	claims, err := current.ReadToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), claims.UserID)
+
+	retiredSigner := auth.NewGJWT("jwt-v1", mustReadKey(t, dir, "jwt-v1"), nil)
+	retiredToken, err := retiredSigner.GenerateToken(&auth.Token{UserID: 7}, 15)
+	require.NoError(t, err)
+	_, err = current.ReadToken(retiredToken)
+	assert.Error(t, err)
+
+	jwks := auth.NewJWKSet(ring.PublicKeys)
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "jwt-v2", jwks.Keys[0].Kid)
+}
+
+func TestLoadKeyring_SkipsMissingVerifyOnlyKeyButRequiresActive(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, auth.WriteKeyFile(dir, auth.JWTKeyID(2), key))
+
+	mockKeyRepo := new(mockRepository.MockKeyRepository)
+	mockKeyRepo.On("ListKeys", "jwt").Return([]model.EncryptionKeys{
+		{KeyVersion: 1, ExpiresAt: time.Now().Add(time.Hour)}, // өөр host дээр үүссэн
+		{KeyVersion: 2, IsActive: true},
+	}, nil).Once()
+	mockKeyRepo.On("ListKeys", "jwt").Return([]model.EncryptionKeys{
+		{KeyVersion: 3, IsActive: true},
+	}, nil).Once()
+
+	// Act
+	ring, err := auth.LoadKeyring(mockKeyRepo, dir, auth.LegacyKeys{})
+	_, missingActiveErr := auth.LoadKeyring(mockKeyRepo, dir, auth.LegacyKeys{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-v2", ring.SigningKeyID)
+	assert.NotContains(t, ring.PublicKeys, "jwt-v1")
+	assert.Error(t, missingActiveErr)
+}
+
+func mustReadKey(t *testing.T, dir, kid string) *rsa.PrivateKey {
+	key, err := auth.ReadKeyFile(dir, kid)
+	require.NoError(t, err)
+	return key
+}
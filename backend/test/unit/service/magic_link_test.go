@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"crypto/rsa"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authService "mindsteps/internal/auth/service"
+	"mindsteps/internal/mailer"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAuthService_MagicLink_LogsInOnlyOnce(t *testing.T) {
+	// Arrange
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	auth.Gjwt = auth.NewGJWT("test", key, map[string]*rsa.PublicKey{"test": &key.PublicKey})
+
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	mockMailer := new(mockRepository.MockMailer)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, mockRoleRepo, mockMailer, mockLimiter)
+
+	user := &model.Users{ID: 1, Name: "Test", Email: "test@example.com", Language: "en", IsActive: true, IsEmailVerified: true}
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(0), time.Duration(0), nil)
+	mockLimiter.On("Hit", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(int64(1), time.Minute, nil)
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockAuthRepo.On("InvalidateOTPs", uint(1), "magic_link").Return(nil)
+
+	var otp *model.AuthOTP
+	mockAuthRepo.On("CreateOTP", mock.AnythingOfType("*model.AuthOTP")).Run(func(args mock.Arguments) {
+		otp = args.Get(0).(*model.AuthOTP)
+	}).Return(nil)
+
+	var link string
+	mockMailer.On("Send", mock.AnythingOfType("*mailer.Message")).Run(func(args mock.Arguments) {
+		link = regexp.MustCompile(`\S*/auth/magic-link\?token=\S+`).FindString(args.Get(0).(*mailer.Message).Text)
+	}).Return(nil)
+
+	mockAuthRepo.On("FindMFA", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockAuthRepo.On("KnownLoginOrigin", uint(1), mock.Anything, mock.Anything).Return(true, true, nil)
+	mockUserRepo.On("UpdateLastLogin", uint(1)).Return(nil)
+	mockUserRepo.On("IncrementLoginCount", uint(1)).Return(nil)
+	mockAuthRepo.On("CreateSession", mock.AnythingOfType("*model.UserSessions")).Return(nil)
+	mockRoleRepo.On("FindActiveByOwner", uint(1)).Return([]model.Roles{}, nil)
+	mockAuthRepo.On("CreateAccessLog", mock.AnythingOfType("*model.UserDataAccessLog")).Return(nil)
+
+	client := auth.ClientInfo{IPAddress: "127.0.0.1"}
+
+	// Act
+	require.NoError(t, svc.SendMagicLink(&authForm.MagicLinkForm{Email: "test@example.com"}, client))
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	token := parsed.Query().Get("token")
+
+	mockAuthRepo.On("ConsumeOTP", uint(1), otp.OtpCode, "magic_link").Return(true, nil).Once()
+	pair, _, firstErr := svc.LoginWithMagicLink(&authForm.MagicLinkLoginForm{Token: token}, client)
+	mockAuthRepo.On("ConsumeOTP", uint(1), otp.OtpCode, "magic_link").Return(false, nil).Once()
+	_, _, secondErr := svc.LoginWithMagicLink(&authForm.MagicLinkLoginForm{Token: token}, client)
+
+	// Assert
+	assert.Equal(t, "magic_link", otp.OtpType)
+	assert.LessOrEqual(t, len(otp.OtpCode), 10)
+	assert.NoError(t, firstErr)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.ErrorIs(t, secondErr, auth.ErrInvalidLinkToken)
+}
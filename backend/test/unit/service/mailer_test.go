@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mindsteps/internal/mailer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_Send_WritesToMaildir(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	m, err := mailer.NewFileMailer(dir)
+	assert.NoError(t, err)
+
+	msg, err := mailer.Render(mailer.TemplateEmailVerification, "mn", "test@example.com", mailer.OTPData{
+		Name:           "Бат",
+		Code:           "123456",
+		ExpiresMinutes: 10,
+	})
+	assert.NoError(t, err)
+
+	// Act
+	err = m.Send(msg)
+
+	// Assert
+	assert.NoError(t, err)
+	files, _ := os.ReadDir(filepath.Join(dir, "new"))
+	assert.Len(t, files, 1)
+	content, _ := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	assert.True(t, strings.Contains(string(content), "To: test@example.com"))
+	assert.True(t, strings.Contains(msg.Text, "123456"))
+}
+
+func TestRender_FallsBackToMongolian(t *testing.T) {
+	// Act
+	msg, err := mailer.Render(mailer.TemplatePasswordReset, "fr", "test@example.com", mailer.OTPData{Code: "654321"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "MindSteps - Нууц үг сэргээх код", msg.Subject)
+	assert.Contains(t, msg.HTML, "654321")
+}
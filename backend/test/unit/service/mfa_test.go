@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authForm "mindsteps/internal/auth/form"
+	authService "mindsteps/internal/auth/service"
+	"mindsteps/pkg/totp"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthService_Login_RequiresTOTPWhenMFAEnabled(t *testing.T) {
+	// Arrange
+	key, err := auth.GenerateKey()
+	require.NoError(t, err)
+	gjwt := auth.NewGJWT("test", key, map[string]*rsa.PublicKey{"test": &key.PublicKey})
+	auth.Gjwt = gjwt
+
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	mockLimiter := new(mockRepository.MockRateLimitStore)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, mockRoleRepo, new(mockRepository.MockMailer), mockLimiter)
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	auth.MFAKey = bytes.Repeat([]byte{7}, totp.KeySize)
+	encrypted, err := totp.SealSecret(auth.MFAKey, secret)
+	require.NoError(t, err)
+	step := totp.Step(time.Now())
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	user := &model.Users{ID: 1, Email: "test@example.com", Password: string(hashed), IsActive: true}
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockLimiter.On("Count", mock.AnythingOfType("string")).Return(int64(0), time.Duration(0), nil)
+	mockLimiter.On("Reset", mock.AnythingOfType("string")).Return(nil)
+	mockAuthRepo.On("FindMFA", uint(1)).Return(&model.UserMfa{UserID: 1, SecretEncrypted: encrypted, EnabledAt: time.Now()}, nil)
+	mockAuthRepo.On("UseMFAStep", uint(1), step).Return(true, nil)
+	mockAuthRepo.On("KnownLoginOrigin", uint(1), mock.Anything, mock.Anything).Return(true, true, nil)
+	mockUserRepo.On("UpdateLastLogin", uint(1)).Return(nil)
+	mockUserRepo.On("IncrementLoginCount", uint(1)).Return(nil)
+	mockAuthRepo.On("CreateSession", mock.AnythingOfType("*model.UserSessions")).Return(nil)
+	mockRoleRepo.On("FindActiveByOwner", uint(1)).Return([]model.Roles{}, nil)
+	mockAuthRepo.On("CreateAccessLog", mock.AnythingOfType("*model.UserDataAccessLog")).Return(nil)
+
+	client := auth.ClientInfo{IPAddress: "127.0.0.1"}
+
+	// Act
+	pair, _, loginErr := svc.Login(&authForm.LoginForm{Email: "test@example.com", Password: "correct-password"}, client)
+	var mfaErr *authService.MFARequiredError
+	require.ErrorAs(t, loginErr, &mfaErr)
+	verified, _, err := svc.VerifyMFA(&authForm.MFAVerifyForm{MFAToken: mfaErr.Token, Code: code}, client)
+
+	// Assert
+	assert.Nil(t, pair)
+	require.NoError(t, err)
+	claims, err := gjwt.ReadToken(verified.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, claims.Otp)
+	assert.Empty(t, claims.Purpose)
+	mockAuthRepo.AssertCalled(t, "CreateAccessLog", mock.MatchedBy(func(entry *model.UserDataAccessLog) bool {
+		return entry.AccessReason == "success"
+	}))
+}
+
+func TestTOTP_SealSecret_UsesRandomNonceAndRejectsWrongKey(t *testing.T) {
+	// Arrange
+	key := bytes.Repeat([]byte{1}, totp.KeySize)
+
+	// Act
+	first, err := totp.SealSecret(key, "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	second, err := totp.SealSecret(key, "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	opened, openErr := totp.OpenSecret(key, first)
+	_, wrongKeyErr := totp.OpenSecret(bytes.Repeat([]byte{2}, totp.KeySize), first)
+
+	// Assert
+	assert.NotEqual(t, first, second)
+	assert.NoError(t, openErr)
+	assert.Equal(t, "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP", opened)
+	assert.Error(t, wrongKeyErr)
+}
+
+func TestAuthService_SetupMFA_UnavailableWithoutKey(t *testing.T) {
+	// Arrange
+	previous := auth.MFAKey
+	auth.MFAKey = nil
+	t.Cleanup(func() { auth.MFAKey = previous })
+
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, new(mockRepository.MockRoleRepository), new(mockRepository.MockMailer), new(mockRepository.MockRateLimitStore))
+
+	// Act
+	setup, err := svc.SetupMFA(1)
+
+	// Assert
+	assert.Nil(t, setup)
+	assert.ErrorIs(t, err, authService.ErrMFAUnavailable)
+	mockAuthRepo.AssertNotCalled(t, "SaveMFA", mock.Anything)
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"testing"
+
+	"mindsteps/database/model"
+	moodForm "mindsteps/internal/mood/form"
+	"mindsteps/internal/mood/repository"
+	moodService "mindsteps/internal/mood/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoodEntryService_Create_SplitsFreeTextIntoTags(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	mockGamification := new(mockRepository.MockGamificationService)
+	svc := moodService.NewMoodEntryService(mockRepo, new(mockRepository.MockPlutchikCombinationRepository), mockGamification)
+
+	mockRepo.On("UserTimezone", uint(1)).Return("Asia/Tokyo", nil)
+	mockRepo.On("CreateWithEmotions", mock.AnythingOfType("*model.MoodEntries"), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.MoodEntries).ID = 60
+	}).Return(nil)
+	mockGamification.On("AddXP", uint(1), mock.Anything, "mood_entry", uint(60), mock.Anything).Return(nil)
+	mockRepo.On("SyncEntryTags", uint(1), uint(60), moodForm.TagKindTrigger, []string{"Ажил", "дедлайн"}).Return(nil)
+	mockRepo.On("SyncEntryTags", uint(1), uint(60), moodForm.TagKindCoping, []string{"Алхах"}).Return(nil)
+	mockRepo.On("EntryTags", uint(60)).Return([]model.MoodTags{{ID: 1, Name: "Ажил"}}, nil)
+
+	// Act
+	checkIn, err := svc.Create(&moodForm.MoodEntryForm{
+		UserID:         1,
+		CorevalueID:    4,
+		MoodUnitId:     3,
+		Intensity:      6,
+		TriggerEvent:   " Ажил ;  дедлайн,ажил\n",
+		CopingTags:     []string{"  Алхах  ", "алхах"},
+		CopingStrategy: "өөр текст",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, checkIn.Tags, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMoodEntryService_Analyze_WritesHighestTagScorePerEntry(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+
+	mockRepo.On("AnalysisBaseline", mock.Anything).Return(repository.AnalysisBaseline{Entries: 20, NegativeEntries: 5}, nil)
+	mockRepo.On("TagStats", mock.Anything).Return([]repository.TagStat{
+		{TagID: 1, Kind: moodForm.TagKindTrigger, Name: "ажил", Uses: 10, NegativeUses: 8},
+		{TagID: 2, Kind: moodForm.TagKindTrigger, Name: "шөнө", Uses: 2, NegativeUses: 2},
+		{TagID: 3, Kind: moodForm.TagKindCoping, Name: "алхах", Uses: 4, FollowUps: 4, Improved: 3},
+	}, nil)
+	mockRepo.On("EntryTagLinks", mock.Anything).Return([]repository.EntryTagLink{
+		{MoodEntryID: 10, TagID: 1},
+		{MoodEntryID: 10, TagID: 2},
+		{MoodEntryID: 11, TagID: 3},
+		{MoodEntryID: 12, TagID: 0},
+	}, nil)
+	var saved []repository.EntryScore
+	mockRepo.On("SaveAnalyses", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).([]repository.EntryScore)
+	}).Return(nil)
+
+	// Act
+	count, err := svc.Analyze(1, 90)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, saved, 3)
+	// ажил: 8/10 = 80, шөнө: 2/2 боловч 2 удаа тул 100 * 2/5 = 40
+	require.NotNil(t, saved[0].TriggerScore)
+	assert.Equal(t, 80, *saved[0].TriggerScore)
+	assert.Nil(t, saved[0].CopingScore)
+	// алхах: 3/4 * 4/5 = 60
+	require.NotNil(t, saved[1].CopingScore)
+	assert.Equal(t, 60, *saved[1].CopingScore)
+	// шошгоо алдсан бүртгэлийн оноо арилна
+	assert.Nil(t, saved[2].TriggerScore)
+	assert.Nil(t, saved[2].CopingScore)
+}
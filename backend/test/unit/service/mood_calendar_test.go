@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"testing"
+
+	"mindsteps/database/model"
+	moodForm "mindsteps/internal/mood/form"
+	moodRepo "mindsteps/internal/mood/repository"
+	moodService "mindsteps/internal/mood/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoodEntryService_Calendar_FillsEveryLocalDay(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+
+	query := moodRepo.StatsQuery{UserID: 1, Timezone: "America/New_York", From: statsDate("2026-03-01"), To: statsDate("2026-03-05")}
+	mockRepo.On("UserTimezone", uint(1)).Return("America/New_York", nil)
+	mockRepo.On("CalendarDays", query).Return([]moodRepo.CalendarDay{
+		{Day: statsDate("2026-03-02"), Entries: 3, AverageIntensity: 6.666, MoodUnitID: 4, DisplayColor: "#FFD700", DisplayEmoji: "😊"},
+	}, nil)
+
+	// Act
+	calendar, err := svc.Calendar(1, &moodForm.MoodCalendarForm{From: "2026-03-01", To: "2026-03-04"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, calendar.Days, 4)
+	assert.Equal(t, "2026-03-01", calendar.Days[0].Date)
+	assert.Nil(t, calendar.Days[0].Mood)
+	assert.Equal(t, int64(3), calendar.Days[1].Entries)
+	assert.Equal(t, 6.67, calendar.Days[1].AverageIntensity)
+	require.NotNil(t, calendar.Days[1].Mood)
+	assert.Equal(t, "#FFD700", calendar.Days[1].Mood.Color)
+	assert.Equal(t, "2026-03-04", calendar.Days[3].Date)
+}
+
+func TestMoodEntryService_CalendarDay_CollectsDayActivity(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+
+	query := moodRepo.StatsQuery{UserID: 1, Timezone: "UTC", From: statsDate("2026-03-02"), To: statsDate("2026-03-03")}
+	mockRepo.On("UserTimezone", uint(1)).Return("", nil)
+	mockRepo.On("DayEntries", query).Return([]model.MoodEntries{{ID: 1}, {ID: 2}}, nil)
+	mockRepo.On("DayJournals", query).Return([]moodRepo.DayJournal{{ID: 9, Title: "Өглөө"}}, nil)
+	mockRepo.On("DayMeditations", query).Return([]model.MeditationSessions{}, nil)
+	mockRepo.On("DayLessons", query).Return([]moodRepo.DayLesson{{LessonID: 3}}, nil)
+
+	// Act
+	detail, err := svc.CalendarDay(1, "2026-03-02")
+	_, invalidErr := svc.CalendarDay(1, "03/02/2026")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", detail.Timezone)
+	assert.Len(t, detail.MoodEntries, 2)
+	assert.Equal(t, "Өглөө", detail.Journals[0].Title)
+	assert.Len(t, detail.Lessons, 1)
+	assert.Error(t, invalidErr)
+	mockRepo.AssertNumberOfCalls(t, "DayEntries", 1)
+	mockRepo.AssertNotCalled(t, "CalendarDays", mock.Anything)
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	moodForm "mindsteps/internal/mood/form"
+	moodRepo "mindsteps/internal/mood/repository"
+	moodService "mindsteps/internal/mood/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func statsDate(value string) time.Time {
+	t, _ := time.Parse(time.DateOnly, value)
+	return t
+}
+
+func TestMoodEntryService_Statistics_ComparesWithPreviousPeriod(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+
+	current := moodRepo.StatsQuery{UserID: 1, Timezone: "Asia/Tokyo", From: statsDate("2026-03-01"), To: statsDate("2026-03-08")}
+	previous := moodRepo.StatsQuery{UserID: 1, Timezone: "Asia/Tokyo", From: statsDate("2026-02-22"), To: statsDate("2026-03-01")}
+	mockRepo.On("UserTimezone", uint(1)).Return("Asia/Tokyo", nil)
+	mockRepo.On("StatsSummary", current).Return(moodRepo.StatsSummary{Entries: 6, AverageIntensity: 6.5, ActiveDays: 4}, nil)
+	mockRepo.On("StatsSummary", previous).Return(moodRepo.StatsSummary{Entries: 2, AverageIntensity: 4, ActiveDays: 2}, nil)
+	mockRepo.On("StatsTrend", current, moodForm.BucketDay).Return([]moodRepo.StatsBucket{
+		{Bucket: statsDate("2026-03-01"), Entries: 2, AverageIntensity: 5},
+		{Bucket: statsDate("2026-03-03"), Entries: 2, AverageIntensity: 7},
+		{Bucket: statsDate("2026-03-05"), Entries: 2, AverageIntensity: 9},
+	}, nil)
+	mockRepo.On("StatsEmotions", current).Return([]moodRepo.StatsShare{{ID: 1, NameEn: "Joy", Entries: 4}, {ID: 2, NameEn: "Trust", Entries: 2}}, nil)
+	mockRepo.On("StatsCategories", current).Return([]moodRepo.StatsShare{{ID: 3, Entries: 6}}, nil)
+	mockRepo.On("StatsHours", current).Return([]moodRepo.StatsHour{
+		{Hour: 7, Entries: 3, AverageIntensity: 6},
+		{Hour: 9, Entries: 1, AverageIntensity: 8},
+		{Hour: 22, Entries: 2, AverageIntensity: 5},
+	}, nil)
+
+	// Act
+	stats, err := svc.Statistics(1, &moodForm.MoodStatsForm{From: "2026-03-01", To: "2026-03-07"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", stats.Timezone)
+	assert.Equal(t, "2026-03-07", stats.To)
+	assert.Equal(t, "2026-02-22", stats.Previous.From)
+	assert.Equal(t, "2026-02-28", stats.Previous.To)
+	assert.Equal(t, int64(4), stats.Previous.EntriesChange)
+	assert.Equal(t, 2.5, stats.Previous.AverageIntensityChange)
+	assert.Equal(t, 1.0, stats.TrendSlope)
+	assert.Equal(t, "2026-03-03", stats.Trend[1].Bucket)
+	assert.Equal(t, 66.7, stats.Emotions[0].Percent)
+	assert.Equal(t, 100.0, stats.Categories[0].Percent)
+	assert.Equal(t, "morning", stats.DayParts[1].Name)
+	assert.Equal(t, int64(4), stats.DayParts[1].Entries)
+	assert.Equal(t, 6.5, stats.DayParts[1].AverageIntensity)
+	assert.Equal(t, int64(2), stats.DayParts[3].Entries)
+}
+
+func TestMoodEntryService_Statistics_ComparesWithPreviousCalendarMonth(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+
+	previous := moodRepo.StatsQuery{UserID: 1, Timezone: "Europe/Berlin", From: statsDate("2026-02-01"), To: statsDate("2026-03-01")}
+	mockRepo.On("UserTimezone", uint(1)).Return("Europe/Berlin", nil)
+	mockRepo.On("StatsSummary", previous).Return(moodRepo.StatsSummary{Entries: 3}, nil).Once()
+	mockRepo.On("StatsSummary", mock.Anything).Return(moodRepo.StatsSummary{Entries: 5}, nil)
+	mockRepo.On("StatsTrend", mock.Anything, moodForm.BucketMonth).Return([]moodRepo.StatsBucket{}, nil)
+	mockRepo.On("StatsEmotions", mock.Anything).Return([]moodRepo.StatsShare{}, nil)
+	mockRepo.On("StatsCategories", mock.Anything).Return([]moodRepo.StatsShare{}, nil)
+	mockRepo.On("StatsHours", mock.Anything).Return([]moodRepo.StatsHour{}, nil)
+
+	// Act
+	stats, err := svc.Statistics(1, &moodForm.MoodStatsForm{Bucket: moodForm.BucketMonth, From: "2026-03-01", To: "2026-03-31"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-01", stats.Previous.From)
+	assert.Equal(t, "2026-02-28", stats.Previous.To)
+	assert.Equal(t, int64(2), stats.Previous.EntriesChange)
+}
+
+func TestMoodEntryService_Statistics_RejectsInvalidRange(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, nil, nil)
+	mockRepo.On("UserTimezone", uint(1)).Return("", nil)
+
+	// Act
+	_, bucketErr := svc.Statistics(1, &moodForm.MoodStatsForm{Bucket: "year"})
+	_, longErr := svc.Statistics(1, &moodForm.MoodStatsForm{From: "2024-01-01", To: "2026-01-01"})
+
+	// Assert
+	assert.Error(t, bucketErr)
+	assert.Error(t, longErr)
+	mockRepo.AssertNotCalled(t, "StatsSummary", mock.Anything)
+}
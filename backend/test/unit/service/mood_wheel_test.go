@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	moodForm "mindsteps/internal/mood/form"
+	moodService "mindsteps/internal/mood/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMoodEntryService_Create_StoresEmotionsWithDerivedDyads(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	mockComb := new(mockRepository.MockPlutchikCombinationRepository)
+	mockGamification := new(mockRepository.MockGamificationService)
+	svc := moodService.NewMoodEntryService(mockRepo, mockComb, mockGamification)
+
+	mockRepo.On("UserTimezone", uint(1)).Return("Asia/Tokyo", nil)
+	// 11 = ecstasy (joy-ийн хүчтэй түвшин), 2 = trust, 3 = fear
+	mockRepo.On("FindEmotions", []int{11, 2, 3}).Return([]model.PlutchikEmotions{
+		{ID: 11, NameEn: "Ecstasy", BaseEmotionID: 1},
+		{ID: 2, NameEn: "Trust"},
+		{ID: 3, NameEn: "Fear"},
+	}, nil)
+	love := &model.PlutchikCombinations{ID: 100, CombinedNameEn: "Love"}
+	submission := &model.PlutchikCombinations{ID: 101, CombinedNameEn: "Submission"}
+	mockComb.On("GetByEmotionPair", 1, 2).Return(love, nil)
+	mockComb.On("GetByEmotionPair", 1, 3).Return(nil, gorm.ErrRecordNotFound)
+	mockComb.On("GetByEmotionPair", 2, 3).Return(submission, nil)
+	mockRepo.On("MoodUnitByEmotion", 11).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("MoodUnitByEmotion", 1).Return(&model.MoodUnit{ID: 7}, nil)
+	var saved []model.UserEmotionWheel
+	mockRepo.On("CreateWithEmotions", mock.AnythingOfType("*model.MoodEntries"), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.MoodEntries).ID = 50
+		saved = args.Get(1).([]model.UserEmotionWheel)
+	}).Return(nil)
+	mockGamification.On("AddXP", uint(1), 10, "mood_entry", uint(50), `{"emotion":"Ecstasy","intensity":8}`).Return(nil)
+	mockRepo.On("SyncEntryTags", uint(1), uint(50), mock.Anything, []string{}).Return(nil)
+	mockRepo.On("EntryTags", uint(50)).Return([]model.MoodTags{}, nil)
+
+	// Act
+	checkIn, err := svc.Create(&moodForm.MoodEntryForm{
+		UserID:      1,
+		CorevalueID: 4,
+		Emotions: []moodForm.EmotionIntensityForm{
+			{PlutchikEmotionID: 11, Intensity: 8},
+			{PlutchikEmotionID: 2, Intensity: 6},
+			{PlutchikEmotionID: 3, Intensity: 3},
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, checkIn.MoodUnitID)
+	assert.Equal(t, 8, checkIn.Intensity)
+	require.Len(t, saved, 3)
+	assert.Equal(t, 100, saved[0].DetectedCombinationID)
+	assert.Equal(t, 100, saved[1].DetectedCombinationID)
+	assert.Equal(t, 101, saved[2].DetectedCombinationID)
+	assert.Equal(t, uint(1), saved[2].UserID)
+	require.Len(t, checkIn.Dyads, 2)
+	assert.Equal(t, "Love", checkIn.Dyads[0].CombinedNameEn)
+	mockGamification.AssertExpectations(t)
+}
+
+func TestMoodEntryService_Create_RejectsSameBaseEmotionTwice(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, new(mockRepository.MockPlutchikCombinationRepository), nil)
+	mockRepo.On("UserTimezone", uint(1)).Return("Asia/Tokyo", nil)
+	mockRepo.On("FindEmotions", []int{1, 11}).Return([]model.PlutchikEmotions{
+		{ID: 1, NameEn: "Joy"},
+		{ID: 11, NameEn: "Ecstasy", BaseEmotionID: 1},
+	}, nil)
+
+	// Act
+	_, err := svc.Create(&moodForm.MoodEntryForm{
+		UserID:      1,
+		CorevalueID: 4,
+		Emotions: []moodForm.EmotionIntensityForm{
+			{PlutchikEmotionID: 1, Intensity: 5},
+			{PlutchikEmotionID: 11, Intensity: 9},
+		},
+	})
+
+	// Assert
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateWithEmotions", mock.Anything, mock.Anything)
+}
+
+func TestMoodEntryService_Create_BackdatesEntryInUserTimezone(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	mockGamification := new(mockRepository.MockGamificationService)
+	svc := moodService.NewMoodEntryService(mockRepo, new(mockRepository.MockPlutchikCombinationRepository), mockGamification)
+
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	now := time.Now().In(loc)
+	twoDaysAgo := time.Date(now.Year(), now.Month(), now.Day()-2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("UserTimezone", uint(1)).Return("Pacific/Kiritimati", nil)
+	var saved *model.MoodEntries
+	mockRepo.On("CreateWithEmotions", mock.AnythingOfType("*model.MoodEntries"), mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*model.MoodEntries)
+		saved.ID = 70
+	}).Return(nil)
+	mockGamification.On("AddXP", uint(1), mock.Anything, "mood_entry", uint(70), mock.Anything).Return(nil)
+	mockRepo.On("SyncEntryTags", uint(1), uint(70), mock.Anything, []string{}).Return(nil)
+	mockRepo.On("EntryTags", uint(70)).Return([]model.MoodTags{}, nil)
+
+	// Act
+	_, err = svc.Create(&moodForm.MoodEntryForm{
+		UserID:      1,
+		CorevalueID: 4,
+		MoodUnitId:  3,
+		Intensity:   5,
+		EntryDate:   twoDaysAgo.Format(time.DateOnly),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, twoDaysAgo, saved.EntryDate)
+}
+
+func TestMoodEntryService_Create_RejectsEntryDateOutsideBackdateWindow(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockMoodEntryRepository)
+	svc := moodService.NewMoodEntryService(mockRepo, new(mockRepository.MockPlutchikCombinationRepository), nil)
+	mockRepo.On("UserTimezone", uint(1)).Return("UTC", nil)
+	today := time.Now().UTC()
+
+	for _, entryDate := range []string{
+		today.AddDate(0, 0, -moodForm.MaxBackdateDays-1).Format(time.DateOnly),
+		today.AddDate(0, 0, 1).Format(time.DateOnly),
+		"2024/01/01",
+	} {
+		// Act
+		_, err := svc.Create(&moodForm.MoodEntryForm{UserID: 1, CorevalueID: 4, MoodUnitId: 3, Intensity: 5, EntryDate: entryDate})
+
+		// Assert
+		assert.Error(t, err, entryDate)
+	}
+	mockRepo.AssertNotCalled(t, "CreateWithEmotions", mock.Anything, mock.Anything)
+}
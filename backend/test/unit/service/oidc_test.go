@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authService "mindsteps/internal/auth/service"
+	"mindsteps/internal/oidc"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// authorizeWithStub нь хуурамч issuer дээр login_hint-ээр нэвтэрч, callback руу ирэх code-ийг буцаана.
+func authorizeWithStub(t *testing.T, provider *oidc.Provider, req *oidc.AuthRequest, email string) string {
+	authURL, err := provider.AuthCodeURL(context.Background(), req.State, req.Nonce, req.Verifier)
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL + "&login_hint=" + url.QueryEscape(email))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, req.State, callback.Query().Get("state"))
+	return callback.Query().Get("code")
+}
+
+func TestAuthService_LoginWithIdentity_LinksVerifiedEmailFromStubIssuer(t *testing.T) {
+	// Arrange
+	srv := httptest.NewUnstartedServer(nil)
+	stub, err := oidc.NewStub("http://" + srv.Listener.Addr().String())
+	require.NoError(t, err)
+	srv.Config.Handler = stub
+	srv.Start()
+	defer srv.Close()
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:        oidc.StubProviderName,
+		Issuer:      stub.Issuer(),
+		ClientID:    oidc.StubClientID,
+		RedirectURL: "http://localhost:8080/api/v1/auth/oidc/local/callback",
+	})
+	req, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+
+	auth.Gjwt = &mockRepository.MockGJWT{}
+	mockUserRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	svc := authService.NewAuthService(mockUserRepo, mockAuthRepo, mockRoleRepo, new(mockRepository.MockMailer), new(mockRepository.MockRateLimitStore))
+
+	subject := oidc.StubSubject("test@example.com")
+	// Баталгаажаагүй бүртгэлийг өөр хүн урьдчилан үүсгэсэн байж болно
+	user := &model.Users{ID: 7, Email: "test@example.com", Password: "attacker-hash", IsActive: true}
+	mockAuthRepo.On("FindIdentity", "local", subject).Return(nil, gorm.ErrRecordNotFound)
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockUserRepo.On("Update", user).Return(nil)
+	mockAuthRepo.On("RevokeUserSessions", uint(7), "oidc_link_unverified").Return([]uint{3}, nil)
+	mockAuthRepo.On("CreateIdentity", mock.MatchedBy(func(identity *model.UserIdentities) bool {
+		return identity.UserID == 7 && identity.Provider == "local" && identity.Subject == subject
+	})).Return(nil)
+	mockAuthRepo.On("FindMFA", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+	mockAuthRepo.On("KnownLoginOrigin", uint(7), mock.Anything, mock.Anything).Return(true, true, nil)
+	mockUserRepo.On("UpdateLastLogin", uint(7)).Return(nil)
+	mockUserRepo.On("IncrementLoginCount", uint(7)).Return(nil)
+	mockAuthRepo.On("CreateSession", mock.AnythingOfType("*model.UserSessions")).Return(nil)
+	mockRoleRepo.On("FindActiveByOwner", uint(7)).Return([]model.Roles{}, nil)
+	mockAuthRepo.On("CreateAccessLog", mock.MatchedBy(func(entry *model.UserDataAccessLog) bool {
+		return entry.AccessReason == "oidc:local"
+	})).Return(nil)
+
+	// Act
+	code := authorizeWithStub(t, provider, req, "Test@Example.com")
+	identity, err := provider.Exchange(context.Background(), code, req.Verifier, req.Nonce)
+	require.NoError(t, err)
+	pair, loggedIn, err := svc.LoginWithIdentity(identity, auth.ClientInfo{IPAddress: "127.0.0.1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "mocked-token", pair.AccessToken)
+	assert.Equal(t, uint(7), loggedIn.ID)
+	assert.True(t, loggedIn.IsEmailVerified)
+	assert.NotEqual(t, "attacker-hash", loggedIn.Password)
+	mockAuthRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestProvider_Exchange_RejectsWrongVerifier(t *testing.T) {
+	// Arrange
+	srv := httptest.NewUnstartedServer(nil)
+	stub, err := oidc.NewStub("http://" + srv.Listener.Addr().String())
+	require.NoError(t, err)
+	srv.Config.Handler = stub
+	srv.Start()
+	defer srv.Close()
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:        oidc.StubProviderName,
+		Issuer:      stub.Issuer(),
+		ClientID:    oidc.StubClientID,
+		RedirectURL: "http://localhost:8080/api/v1/auth/oidc/local/callback",
+	})
+	req, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	code := authorizeWithStub(t, provider, req, "test@example.com")
+
+	// Act
+	identity, err := provider.Exchange(context.Background(), code, "wrong-verifier", req.Nonce)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, identity)
+}
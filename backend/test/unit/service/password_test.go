@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"testing"
+
+	"mindsteps/internal/password"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	// Arrange
+	policy := password.Default()
+	cases := []struct {
+		name     string
+		password string
+		valid    bool
+		is       error
+	}{
+		{name: "хүчтэй", password: "Blue-Kettle-42", valid: true},
+		{name: "богино", password: "Ab1!"},
+		{name: "нэг төрлийн тэмдэгт", password: "onlylowercaseletters"},
+		{name: "түгээмэл + тоо", password: "Password123!", is: password.ErrCommon},
+		{name: "email агуулсан", password: "Batbold-2024!", is: password.ErrContainsEmail},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := policy.Validate(tc.password, "batbold@example.com")
+
+			// Assert
+			if tc.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			if tc.is != nil {
+				assert.ErrorIs(t, err, tc.is)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"testing"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	"mindsteps/internal/rbac"
+	rbacForm "mindsteps/internal/rbac/form"
+	rbacService "mindsteps/internal/rbac/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+)
+
+func TestRBAC_Resolve_MergesRolePermissions(t *testing.T) {
+	// Arrange
+	roles := []model.Roles{
+		{Code: "editor", Level: 10, Permissions: datatypes.JSON(`{"lesson": ["write"], "plutchik": ["write"]}`)},
+		{Code: "ops", Level: 20, Permissions: datatypes.JSON(`{"cache": ["manage"], "lesson": ["write"]}`)},
+	}
+
+	// Act
+	codes, permissions, level, err := rbac.Resolve(roles)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"editor", "ops"}, codes)
+	assert.Equal(t, []string{"cache:manage", "lesson:write", "plutchik:write"}, permissions)
+	assert.Equal(t, 20, level)
+	assert.True(t, auth.HasPermission(permissions, rbac.PermLessonWrite))
+	assert.False(t, auth.HasPermission(permissions, rbac.PermRoleManage))
+	assert.True(t, auth.HasPermission([]string{"*:*"}, rbac.PermRoleManage))
+}
+
+func TestRoleService_Assign_RejectsHigherLevelRole(t *testing.T) {
+	// Arrange
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	svc := rbacService.NewRoleService(mockRoleRepo, new(mockRepository.MockUserRepository), new(mockRepository.MockAuthRepository))
+
+	mockRoleRepo.On("FindByID", uint(3)).Return(&model.Roles{ID: 3, Code: "super_admin", Level: 100}, nil)
+
+	// Act
+	err := svc.Assign(&auth.Token{UserID: 1, Level: 50}, 2, &rbacForm.AssignRoleForm{RoleID: 3})
+
+	// Assert
+	assert.ErrorIs(t, err, rbacService.ErrRoleLevelTooHigh)
+	mockRoleRepo.AssertNotCalled(t, "Assign", mock.Anything)
+}
+
+func TestRoleService_Revoke_SignsUserOut(t *testing.T) {
+	// Arrange
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := rbacService.NewRoleService(mockRoleRepo, new(mockRepository.MockUserRepository), mockAuthRepo)
+
+	mockRoleRepo.On("FindByID", uint(3)).Return(&model.Roles{ID: 3, Code: "editor", Level: 10}, nil)
+	mockRoleRepo.On("Revoke", uint(2), uint(3)).Return(true, nil)
+	mockAuthRepo.On("RevokeUserSessions", uint(2), "role_revoked").Return([]uint{7, 8}, nil)
+
+	// Act
+	err := svc.Revoke(&auth.Token{UserID: 1, Level: 50}, 2, 3)
+
+	// Assert
+	assert.NoError(t, err)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestRoleService_Create_RejectsPermissionsActorDoesNotHold(t *testing.T) {
+	// Arrange
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	svc := rbacService.NewRoleService(mockRoleRepo, new(mockRepository.MockUserRepository), new(mockRepository.MockAuthRepository))
+	actor := &auth.Token{UserID: 1, Level: 50, Permissions: []string{rbac.PermRoleManage}}
+
+	// Act
+	_, err := svc.Create(actor, &rbacForm.RoleForm{
+		Code:        "shadow_admin",
+		Name:        "Shadow admin",
+		Level:       50,
+		Permissions: rbac.Permissions{"*": {"*"}},
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, rbacService.ErrPermissionNotHeld)
+	mockRoleRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestRoleService_Assign_RejectsSelfAssignment(t *testing.T) {
+	// Arrange
+	mockRoleRepo := new(mockRepository.MockRoleRepository)
+	svc := rbacService.NewRoleService(mockRoleRepo, new(mockRepository.MockUserRepository), new(mockRepository.MockAuthRepository))
+
+	// Act
+	err := svc.Assign(&auth.Token{UserID: 1, Level: 50, Permissions: []string{"*:*"}}, 1, &rbacForm.AssignRoleForm{RoleID: 3})
+
+	// Assert
+	assert.ErrorIs(t, err, rbacService.ErrAssignOwnRole)
+	mockRoleRepo.AssertNotCalled(t, "Assign", mock.Anything)
+}
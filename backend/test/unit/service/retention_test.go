@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	retentionRepo "mindsteps/internal/retention/repository"
+	retentionService "mindsteps/internal/retention/service"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionService_Run_DryRunOnlyCounts(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockRetentionRepository)
+	svc := retentionService.NewRetentionService(mockRepo)
+
+	sessions, _ := retentionRepo.LookupTarget("user_sessions")
+	mockRepo.On("Policies").Return([]model.DataRetentionPolicies{
+		{ID: 1, TableName_: "user_sessions", RetentionDays: 90, ArchiveAfterDays: 30, AutoDelete: true, IsActive: true},
+		{ID: 2, TableName_: "users", RetentionDays: 30, AutoDelete: true, IsActive: true},
+		{ID: 3, TableName_: "revoked_tokens", RetentionDays: 7, AutoDelete: true, IsActive: false},
+	}, nil)
+	mockRepo.On("Count", sessions, retentionRepo.MethodArchive, mock.AnythingOfType("time.Time")).Return(int64(12), nil)
+	mockRepo.On("Count", sessions, retentionRepo.MethodDelete, mock.AnythingOfType("time.Time")).Return(int64(40), nil)
+	mockRepo.On("CountExpiredArchives", sessions, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	// Act
+	reports, err := svc.Run(true)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.True(t, reports[0].DryRun)
+	assert.Equal(t, int64(12), reports[0].Archived)
+	assert.Equal(t, int64(40), reports[0].Removed)
+	assert.Equal(t, int64(3), reports[0].ArchivesExpired)
+	assert.NotEmpty(t, reports[1].Error)
+	mockRepo.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Archive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "MarkCleanup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetentionService_RunDue_BatchesAndReschedules(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockRetentionRepository)
+	svc := retentionService.NewRetentionService(mockRepo)
+
+	errorLogs, _ := retentionRepo.LookupTarget("mindstep.error_logs")
+	mockRepo.On("DuePolicies", mock.AnythingOfType("time.Time")).Return([]model.DataRetentionPolicies{
+		{ID: 4, TableName_: "mindstep.error_logs", RetentionDays: 90, AutoDelete: true, DeletionMethod: "anonymize", IsActive: true},
+	}, nil)
+	var cutoff time.Time
+	mockRepo.On("Remove", errorLogs, retentionRepo.MethodAnonymize, mock.AnythingOfType("time.Time"), 500).Run(func(args mock.Arguments) {
+		cutoff = args.Get(2).(time.Time)
+	}).Return(int64(500), nil).Once()
+	mockRepo.On("Remove", errorLogs, retentionRepo.MethodAnonymize, mock.AnythingOfType("time.Time"), 500).Return(int64(120), nil).Once()
+	mockRepo.On("DropExpiredArchives", errorLogs, mock.AnythingOfType("time.Time"), 500).Return(int64(0), nil)
+	var lastAt, nextAt time.Time
+	mockRepo.On("MarkCleanup", 4, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		lastAt, nextAt = args.Get(1).(time.Time), args.Get(2).(time.Time)
+	}).Return(nil)
+
+	// Act
+	reports, err := svc.RunDue()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Empty(t, reports[0].Error)
+	assert.Equal(t, int64(620), reports[0].Removed)
+	assert.True(t, reports[0].Complete)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), cutoff, time.Minute)
+	assert.Equal(t, 24*time.Hour, nextAt.Sub(lastAt))
+	mockRepo.AssertNumberOfCalls(t, "Remove", 2)
+	mockRepo.AssertNotCalled(t, "Archive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"mindsteps/database/model"
+	"mindsteps/internal/auth"
+	authRepo "mindsteps/internal/auth/repository"
+	mockRepository "mindsteps/test/unit/mockRepository"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSessionGuard_Validate_ActiveSession(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockAuthRepository)
+	guard := auth.NewSessionGuard(mockRepo)
+
+	mockRepo.On("IsTokenRevoked", authRepo.HashToken("token")).Return(false, nil)
+	mockRepo.On("FindSessionByID", uint(7)).Return(&model.UserSessions{
+		ID:        7,
+		UserID:    1,
+		IsActive:  true,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockRepo.On("TouchSession", uint(7)).Return(nil)
+
+	// Act
+	err := guard.Validate("token", &auth.Token{UserID: 1, SessionID: 7})
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionGuard_Validate_RevokedToken(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockAuthRepository)
+	guard := auth.NewSessionGuard(mockRepo)
+
+	mockRepo.On("IsTokenRevoked", authRepo.HashToken("token")).Return(true, nil)
+
+	// Act
+	err := guard.Validate("token", &auth.Token{UserID: 1, SessionID: 7})
+
+	// Assert
+	assert.ErrorIs(t, err, auth.ErrSessionRevoked)
+	mockRepo.AssertNotCalled(t, "FindSessionByID", uint(7))
+}
+
+func TestSessionGuard_Validate_SessionClosed(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockAuthRepository)
+	guard := auth.NewSessionGuard(mockRepo)
+
+	mockRepo.On("IsTokenRevoked", authRepo.HashToken("token")).Return(false, nil)
+	mockRepo.On("FindSessionByID", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := guard.Validate("token", &auth.Token{UserID: 1, SessionID: 7})
+
+	// Assert
+	assert.ErrorIs(t, err, auth.ErrSessionRevoked)
+	mockRepo.AssertNotCalled(t, "TouchSession", uint(7))
+}
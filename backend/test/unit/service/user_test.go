@@ -5,18 +5,20 @@ import (
 	"testing"
 
 	"mindsteps/database/model"
+	"mindsteps/internal/password"
 	userForm "mindsteps/internal/user/form"
 	userService "mindsteps/internal/user/service"
 	mockRepository "mindsteps/test/unit/mockRepository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestUserService_GetProfile_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(mockRepository.MockUserRepository)
-	svc := userService.NewUserService(mockRepo)
+	svc := userService.NewUserService(mockRepo, new(mockRepository.MockAuthRepository), nil, nil)
 
 	expectedUser := &model.Users{
 		ID:    1,
@@ -38,7 +40,7 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 func TestUserService_GetProfile_NotFound(t *testing.T) {
 	// Arrange
 	mockRepo := new(mockRepository.MockUserRepository)
-	svc := userService.NewUserService(mockRepo)
+	svc := userService.NewUserService(mockRepo, new(mockRepository.MockAuthRepository), nil, nil)
 
 	mockRepo.On("FindByID", uint(999)).Return(nil, errors.New("record not found"))
 
@@ -54,7 +56,7 @@ func TestUserService_GetProfile_NotFound(t *testing.T) {
 func TestUserService_UpdateProfile_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(mockRepository.MockUserRepository)
-	svc := userService.NewUserService(mockRepo)
+	svc := userService.NewUserService(mockRepo, new(mockRepository.MockAuthRepository), nil, nil)
 
 	existingUser := &model.Users{
 		ID:    1,
@@ -83,7 +85,7 @@ func TestUserService_UpdateProfile_Success(t *testing.T) {
 func TestUserService_UpdateProfile_ValidationError(t *testing.T) {
 	// Arrange
 	mockRepo := new(mockRepository.MockUserRepository)
-	svc := userService.NewUserService(mockRepo)
+	svc := userService.NewUserService(mockRepo, new(mockRepository.MockAuthRepository), nil, nil)
 
 	form := &userForm.UpdateProfileForm{
 		Name:     "A", // Too short
@@ -98,17 +100,69 @@ func TestUserService_UpdateProfile_ValidationError(t *testing.T) {
 	assert.Nil(t, result)
 }
 
-func TestUserService_DeleteAccount_Success(t *testing.T) {
+func TestUserService_ChangePassword_RevokesOtherSessions(t *testing.T) {
 	// Arrange
 	mockRepo := new(mockRepository.MockUserRepository)
-	svc := userService.NewUserService(mockRepo)
-
-	mockRepo.On("Delete", uint(1)).Return(nil)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, nil, nil)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("oldPassword1"), bcrypt.MinCost)
+	mockRepo.On("FindByID", uint(1)).Return(&model.Users{ID: 1, Password: string(hashed)}, nil)
+	mockRepo.On("PasswordHistory", uint(1), 5).Return([]string{}, nil)
+	mockRepo.On("UpdatePassword", uint(1), mock.AnythingOfType("string")).Return(nil)
+	mockAuthRepo.On("RevokeOtherSessions", uint(1), uint(3), "password_change").Return([]uint{4, 5}, nil)
+
+	form := &userForm.ChangePasswordForm{
+		CurrentPassword: "oldPassword1",
+		NewPassword:     "newPassword1",
+		ConfirmPassword: "newPassword1",
+	}
 
 	// Act
-	err := svc.DeleteAccount(1)
+	err := svc.ChangePassword(1, 3, form)
 
 	// Assert
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestUserService_ChangePassword_RejectsRecentPassword(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockUserRepository)
+	svc := userService.NewUserService(mockRepo, new(mockRepository.MockAuthRepository), nil, nil)
+
+	current, _ := bcrypt.GenerateFromPassword([]byte("newPassword1"), bcrypt.MinCost)
+	previous, _ := bcrypt.GenerateFromPassword([]byte("Correct-Horse-7"), bcrypt.MinCost)
+	mockRepo.On("FindByID", uint(1)).Return(&model.Users{ID: 1, Email: "test@example.com", Password: string(current)}, nil)
+	mockRepo.On("PasswordHistory", uint(1), 5).Return([]string{string(previous)}, nil)
+
+	form := &userForm.ChangePasswordForm{
+		CurrentPassword: "newPassword1",
+		NewPassword:     "Correct-Horse-7",
+		ConfirmPassword: "Correct-Horse-7",
+	}
+
+	// Act
+	err := svc.ChangePassword(1, 3, form)
+
+	// Assert
+	assert.ErrorIs(t, err, password.ErrReused)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", uint(1), mock.Anything)
+}
+
+func TestUserService_RevokeSession_OtherUser(t *testing.T) {
+	// Arrange
+	mockRepo := new(mockRepository.MockUserRepository)
+	mockAuthRepo := new(mockRepository.MockAuthRepository)
+	svc := userService.NewUserService(mockRepo, mockAuthRepo, nil, nil)
+
+	mockAuthRepo.On("FindSessionByID", uint(9)).Return(&model.UserSessions{ID: 9, UserID: 2, IsActive: true}, nil)
+
+	// Act
+	err := svc.RevokeSession(1, 9)
+
+	// Assert
+	assert.ErrorIs(t, err, userService.ErrSessionNotFound)
+	mockAuthRepo.AssertNotCalled(t, "RevokeSession", uint(9), "user_revoked")
 }